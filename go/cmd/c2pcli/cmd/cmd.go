@@ -21,6 +21,7 @@ import (
 
 	"github.com/oscal-compass/compliance-to-policy/go/cmd/c2pcli/options"
 	"github.com/oscal-compass/compliance-to-policy/go/cmd/c2pcli/subcommands"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg"
 )
 
 func New() *cobra.Command {
@@ -29,6 +30,9 @@ func New() *cobra.Command {
 	command := &cobra.Command{
 		Use:   "c2pcli",
 		Short: "C2P CLI",
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			pkg.SetStrictOscalValidation(opts.StrictValidation)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := opts.Complete(); err != nil {
 				return err
@@ -41,10 +45,12 @@ func New() *cobra.Command {
 		},
 	}
 
-	opts.AddFlags(command.Flags())
+	opts.AddFlags(command.PersistentFlags())
 
 	command.AddCommand(subcommands.NewKyvernoSubCommand())
 	command.AddCommand(subcommands.NewOcmSubCommand())
+	command.AddCommand(subcommands.NewOscalSubCommand())
+	command.AddCommand(subcommands.NewVapSubCommand())
 
 	return command
 }
@@ -21,6 +21,7 @@ import (
 )
 
 type Options struct {
+	StrictValidation bool
 }
 
 func NewOptions() *Options {
@@ -28,6 +29,7 @@ func NewOptions() *Options {
 }
 
 func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&o.StrictValidation, "strict-validation", false, "fail on OSCAL schema violations of the loaded and written OSCAL documents instead of warning")
 }
 
 func (o *Options) Complete() error {
@@ -19,24 +19,23 @@ package subcommands
 import (
 	"github.com/spf13/cobra"
 
-	"github.com/oscal-compass/compliance-to-policy/go/cmd/c2pcli/options"
+	oscal2apcmd "github.com/oscal-compass/compliance-to-policy/go/cmd/kyverno/oscal2ap/cmd"
 	oscal2policycmd "github.com/oscal-compass/compliance-to-policy/go/cmd/kyverno/oscal2policy/cmd"
 	result2oscalcmd "github.com/oscal-compass/compliance-to-policy/go/cmd/kyverno/result2oscal/cmd"
+	scancmd "github.com/oscal-compass/compliance-to-policy/go/cmd/kyverno/scan/cmd"
 	toolscmd "github.com/oscal-compass/compliance-to-policy/go/cmd/kyverno/tools/cmd"
 )
 
 func NewKyvernoSubCommand() *cobra.Command {
-	opts := options.NewOptions()
-
 	command := &cobra.Command{
 		Use:   "kyverno",
 		Short: "C2P CLI Kyverno plugin",
 	}
 
-	opts.AddFlags(command.Flags())
-
 	command.AddCommand(oscal2policycmd.New())
 	command.AddCommand(result2oscalcmd.New())
+	command.AddCommand(oscal2apcmd.New())
+	command.AddCommand(scancmd.New())
 	command.AddCommand(toolscmd.New())
 
 	return command
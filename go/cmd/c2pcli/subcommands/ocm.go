@@ -19,24 +19,21 @@ package subcommands
 import (
 	"github.com/spf13/cobra"
 
-	"github.com/oscal-compass/compliance-to-policy/go/cmd/c2pcli/options"
+	oscal2apcmd "github.com/oscal-compass/compliance-to-policy/go/cmd/ocm/oscal2ap/cmd"
 	oscal2policycmd "github.com/oscal-compass/compliance-to-policy/go/cmd/ocm/oscal2policy/cmd"
 	result2oscalcmd "github.com/oscal-compass/compliance-to-policy/go/cmd/ocm/result2oscal/cmd"
 	toolscmd "github.com/oscal-compass/compliance-to-policy/go/cmd/ocm/tools/cmd"
 )
 
 func NewOcmSubCommand() *cobra.Command {
-	opts := options.NewOptions()
-
 	command := &cobra.Command{
 		Use:   "ocm",
 		Short: "C2P CLI OCM plugin",
 	}
 
-	opts.AddFlags(command.Flags())
-
 	command.AddCommand(oscal2policycmd.New())
 	command.AddCommand(result2oscalcmd.New())
+	command.AddCommand(oscal2apcmd.New())
 	command.AddCommand(toolscmd.New())
 
 	return command
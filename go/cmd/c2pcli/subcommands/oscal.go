@@ -0,0 +1,48 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package subcommands
+
+import (
+	"github.com/spf13/cobra"
+
+	ar2poamcmd "github.com/oscal-compass/compliance-to-policy/go/cmd/oscal/ar2poam/cmd"
+	cd2csvcmd "github.com/oscal-compass/compliance-to-policy/go/cmd/oscal/cd2csv/cmd"
+	coveragecmd "github.com/oscal-compass/compliance-to-policy/go/cmd/oscal/coverage/cmd"
+	csv2cdcmd "github.com/oscal-compass/compliance-to-policy/go/cmd/oscal/csv2cd/cmd"
+	diffarcmd "github.com/oscal-compass/compliance-to-policy/go/cmd/oscal/diffar/cmd"
+	lintcdcmd "github.com/oscal-compass/compliance-to-policy/go/cmd/oscal/lintcd/cmd"
+	mergearcmd "github.com/oscal-compass/compliance-to-policy/go/cmd/oscal/mergear/cmd"
+	validatecmd "github.com/oscal-compass/compliance-to-policy/go/cmd/oscal/validate/cmd"
+)
+
+func NewOscalSubCommand() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "oscal",
+		Short: "C2P CLI OSCAL utilities",
+	}
+
+	command.AddCommand(validatecmd.New())
+	command.AddCommand(lintcdcmd.New())
+	command.AddCommand(mergearcmd.New())
+	command.AddCommand(diffarcmd.New())
+	command.AddCommand(ar2poamcmd.New())
+	command.AddCommand(coveragecmd.New())
+	command.AddCommand(csv2cdcmd.New())
+	command.AddCommand(cd2csvcmd.New())
+
+	return command
+}
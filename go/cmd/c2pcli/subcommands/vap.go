@@ -0,0 +1,36 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package subcommands
+
+import (
+	"github.com/spf13/cobra"
+
+	oscal2policycmd "github.com/oscal-compass/compliance-to-policy/go/cmd/vap/oscal2policy/cmd"
+	result2oscalcmd "github.com/oscal-compass/compliance-to-policy/go/cmd/vap/result2oscal/cmd"
+)
+
+func NewVapSubCommand() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "vap",
+		Short: "C2P CLI Kubernetes ValidatingAdmissionPolicy plugin",
+	}
+
+	command.AddCommand(oscal2policycmd.New())
+	command.AddCommand(result2oscalcmd.New())
+
+	return command
+}
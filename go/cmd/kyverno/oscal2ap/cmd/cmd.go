@@ -0,0 +1,76 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/oscal-compass/compliance-to-policy/go/cmd/kyverno/oscal2ap/options"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/kyverno"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal/format"
+	typec2pcr "github.com/oscal-compass/compliance-to-policy/go/pkg/types/c2pcr"
+)
+
+func New() *cobra.Command {
+	opts := options.NewOptions()
+
+	command := &cobra.Command{
+		Use:   "oscal2ap",
+		Short: "Generate OSCAL Assessment Plan of Kyverno policies from OSCAL Component Definition",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.Complete(); err != nil {
+				return err
+			}
+
+			if err := opts.Validate(); err != nil {
+				return err
+			}
+			return Run(opts)
+		},
+	}
+
+	opts.AddFlags(command.Flags())
+
+	return command
+}
+
+func Run(options *options.Options) error {
+	var c2pcrSpec typec2pcr.Spec
+	if err := pkg.LoadYamlFileToObject(options.C2PCRPath, &c2pcrSpec); err != nil {
+		return err
+	}
+
+	gitUtils := pkg.NewGitUtils(pkg.NewTempDirectory(options.TempDirPath))
+	c2pcrParser := kyverno.NewParser(gitUtils)
+	c2pcrParsed, err := c2pcrParser.Parse(c2pcrSpec)
+	if err != nil {
+		return err
+	}
+
+	apRoot, err := kyverno.GenerateAssessmentPlan(c2pcrParsed, c2pcrSpec.Compliance.ComponentDefinition.Url, oscal.NewStamper(options.Deterministic))
+	if err != nil {
+		return err
+	}
+
+	outputFormat, err := format.ParseFormat(options.OutputFormat)
+	if err != nil {
+		return err
+	}
+	return pkg.WriteOscalObjToFile(options.OutputPath, apRoot, outputFormat)
+}
@@ -22,7 +22,10 @@ import (
 	"github.com/oscal-compass/compliance-to-policy/go/cmd/kyverno/result2oscal/options"
 	"github.com/oscal-compass/compliance-to-policy/go/pkg"
 	"github.com/oscal-compass/compliance-to-policy/go/pkg/kyverno"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal/format"
 	typec2pcr "github.com/oscal-compass/compliance-to-policy/go/pkg/types/c2pcr"
+	typeap "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/assessmentplan"
 )
 
 func New() *cobra.Command {
@@ -64,12 +67,38 @@ func Run(options *options.Options) error {
 	}
 
 	r := kyverno.NewResultToOscal(c2pcrParsed, policyResultsDir)
+	if policyResultsDir == "" {
+		client, err := kyverno.NewDynamicClient(options.Kubeconfig, options.Context)
+		if err != nil {
+			return err
+		}
+		r.SetResultsSource(kyverno.NewClusterResultsSource(client))
+	}
+	aggregationRule, err := oscal.ParseAggregationRule(options.AggregationRule)
+	if err != nil {
+		return err
+	}
+	r.SetAggregationRule(aggregationRule)
+	r.SetDeterministic(options.Deterministic)
+
+	outputFormat, err := format.ParseFormat(options.OutputFormat)
+	if err != nil {
+		return err
+	}
+	if options.AssessmentPlan != "" {
+		var apRoot typeap.AssessmentPlanRoot
+		if err := pkg.LoadOscalFileToObject(options.AssessmentPlan, &apRoot); err != nil {
+			return err
+		}
+		r.SetAssessmentPlan(oscal.AssessmentPlanHref(outputPath, options.AssessmentPlan), apRoot.AssessmentPlan)
+	}
+
 	ar, err := r.GenerateAssessmentResults()
 	if err != nil {
 		return err
 	}
 
-	err = pkg.WriteObjToJsonFile(outputPath, ar)
+	err = pkg.WriteOscalObjToFile(outputPath, ar, outputFormat)
 	if err != nil {
 		return err
 	}
@@ -18,15 +18,25 @@ package options
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/spf13/pflag"
+
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal/format"
 )
 
 type Options struct {
 	C2PCRPath        string
 	PolicyResultsDir string
+	Kubeconfig       string
+	Context          string
 	TempDirPath      string
 	OutputPath       string
+	OutputFormat     string
+	AggregationRule  string
+	Deterministic    bool
+	AssessmentPlan   string
 }
 
 func NewOptions() *Options {
@@ -36,8 +46,14 @@ func NewOptions() *Options {
 func (o *Options) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVarP(&o.C2PCRPath, "config", "c", "", "path to c2p-config.yaml")
 	fs.StringVar(&o.PolicyResultsDir, "results", "", "path to directory containing Kyverno Policies List (policies.kyverno.io.yaml), ClusterPolicies List (clusterpolicies.kyverno.io.yaml), PolicyReports List (policyreports.wgpolicyk8s.io.yaml), and ClusterPolicyReports List (clusterpolicyreports.wgpolicyk8s.io.yaml)")
+	fs.StringVar(&o.Kubeconfig, "kubeconfig", "", "path to kubeconfig of the cluster from which Kyverno Policies, ClusterPolicies, PolicyReports and ClusterPolicyReports are listed instead of --results")
+	fs.StringVar(&o.Context, "context", "", "context in the kubeconfig to use (the current context if not specified)")
 	fs.StringVar(&o.TempDirPath, "temp-dir", "", "path to temp directory")
 	fs.StringVarP(&o.OutputPath, "out", "o", "./assessment-results.json", "path to output OSCAL Assessment Results")
+	fs.StringVar(&o.OutputFormat, "output-format", "json", "format of output OSCAL Assessment Results (json, yaml or xml)")
+	fs.StringVar(&o.AggregationRule, "aggregation-rule", string(oscal.AggregationRuleAllPass), "rule aggregating the results of observations into the status of the findings of controls (all-pass, any-pass or no-fail)")
+	fs.BoolVar(&o.Deterministic, "deterministic", false, "generate the same output for the same inputs (UUIDs derived from the contents, timestamps from the inputs or SOURCE_DATE_EPOCH, and sorted collections)")
+	fs.StringVar(&o.AssessmentPlan, "assessment-plan", "", "path to OSCAL Assessment Plan imported by the assessment results. If not specified, the assessment results refer to the component-definition instead")
 }
 
 func (o *Options) Complete() error {
@@ -48,8 +64,17 @@ func (o *Options) Validate() error {
 	if o.C2PCRPath == "" {
 		return errors.New("-c or --config <c2p-config.yaml> is required")
 	}
-	if o.PolicyResultsDir == "" {
-		return errors.New("--results is required")
+	if o.PolicyResultsDir == "" && o.Kubeconfig == "" && o.Context == "" {
+		return errors.New("--results or --kubeconfig/--context is required")
+	}
+	if o.PolicyResultsDir != "" && (o.Kubeconfig != "" || o.Context != "") {
+		return errors.New("--results cannot be used with --kubeconfig/--context")
+	}
+	if _, err := format.ParseFormat(o.OutputFormat); err != nil {
+		return fmt.Errorf("--output-format: %w", err)
+	}
+	if _, err := oscal.ParseAggregationRule(o.AggregationRule); err != nil {
+		return fmt.Errorf("--aggregation-rule: %w", err)
 	}
 	return nil
 }
@@ -0,0 +1,123 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/oscal-compass/compliance-to-policy/go/cmd/kyverno/scan/options"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/kyverno"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal/format"
+	typec2pcr "github.com/oscal-compass/compliance-to-policy/go/pkg/types/c2pcr"
+	typeap "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/assessmentplan"
+)
+
+func New() *cobra.Command {
+	opts := options.NewOptions()
+
+	command := &cobra.Command{
+		Use:   "scan",
+		Short: "Generate OSCAL Assessment Results by evaluating Kyverno policies against Kubernetes manifests without a cluster",
+		Long: `Generate OSCAL Assessment Results by evaluating the validate rules of Kyverno policies against Kubernetes manifests without a cluster.
+Only pattern, anyPattern and deny of the validate rules are evaluated. Rules using context, foreach, podSecurity, cel, manifests,
+verifyImages or variables other than the request result in error.`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.Complete(); err != nil {
+				return err
+			}
+
+			if err := opts.Validate(); err != nil {
+				return err
+			}
+			return Run(opts)
+		},
+	}
+
+	opts.AddFlags(command.Flags())
+
+	return command
+}
+
+func Run(options *options.Options) error {
+	outputPath, tempDirPath := options.OutputPath, options.TempDirPath
+
+	var c2pcrSpec typec2pcr.Spec
+	if err := pkg.LoadYamlFileToObject(options.C2PCRPath, &c2pcrSpec); err != nil {
+		return err
+	}
+
+	gitUtils := pkg.NewGitUtils(pkg.NewTempDirectory(tempDirPath))
+	c2pcrParser := kyverno.NewParser(gitUtils)
+	c2pcrParsed, err := c2pcrParser.Parse(c2pcrSpec)
+	if err != nil {
+		return err
+	}
+
+	policiesDir := options.PoliciesDir
+	if policiesDir == "" {
+		tmpdir := pkg.NewTempDirectory(tempDirPath)
+		if err := kyverno.NewOscal2Policy(c2pcrParsed.PolicyResoureDir, tmpdir).Generate(c2pcrParsed); err != nil {
+			return err
+		}
+		policiesDir = tmpdir.GetTempDir()
+	}
+
+	scanner := kyverno.NewScanner(policiesDir, options.ManifestsDir)
+	if !options.Deterministic {
+		scanner.SetTimestamp(time.Now())
+	}
+	if options.Kubeconfig != "" {
+		restMapper, err := kyverno.NewDiscoveryRESTMapper(options.Kubeconfig)
+		if err != nil {
+			return err
+		}
+		scanner.SetRESTMapper(restMapper)
+	}
+
+	r := kyverno.NewResultToOscal(c2pcrParsed, "")
+	r.SetResultsSource(scanner)
+	aggregationRule, err := oscal.ParseAggregationRule(options.AggregationRule)
+	if err != nil {
+		return err
+	}
+	r.SetAggregationRule(aggregationRule)
+	r.SetDeterministic(options.Deterministic)
+
+	outputFormat, err := format.ParseFormat(options.OutputFormat)
+	if err != nil {
+		return err
+	}
+	if options.AssessmentPlan != "" {
+		var apRoot typeap.AssessmentPlanRoot
+		if err := pkg.LoadOscalFileToObject(options.AssessmentPlan, &apRoot); err != nil {
+			return err
+		}
+		r.SetAssessmentPlan(oscal.AssessmentPlanHref(outputPath, options.AssessmentPlan), apRoot.AssessmentPlan)
+	}
+
+	ar, err := r.GenerateAssessmentResults()
+	if err != nil {
+		return err
+	}
+
+	return pkg.WriteOscalObjToFile(outputPath, ar, outputFormat)
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/pflag"
+
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal/format"
+)
+
+type Options struct {
+	C2PCRPath       string
+	ManifestsDir    string
+	PoliciesDir     string
+	TempDirPath     string
+	OutputPath      string
+	OutputFormat    string
+	AggregationRule string
+	Deterministic   bool
+	AssessmentPlan  string
+	Kubeconfig      string
+}
+
+func NewOptions() *Options {
+	return &Options{}
+}
+
+func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVarP(&o.C2PCRPath, "config", "c", "", "path to c2p-config.yaml")
+	fs.StringVar(&o.ManifestsDir, "manifests", "", "path to directory containing Kubernetes manifests (e.g. output of helm template or kustomize build) evaluated by the policies")
+	fs.StringVar(&o.PoliciesDir, "policies", "", "path to directory containing Kyverno policies generated by oscal2policy. If not specified, the policies are generated from the c2p config")
+	fs.StringVar(&o.TempDirPath, "temp-dir", "", "path to temp directory")
+	fs.StringVarP(&o.OutputPath, "out", "o", "./assessment-results.json", "path to output OSCAL Assessment Results")
+	fs.StringVar(&o.OutputFormat, "output-format", "json", "format of output OSCAL Assessment Results (json, yaml or xml)")
+	fs.StringVar(&o.AggregationRule, "aggregation-rule", string(oscal.AggregationRuleAllPass), "rule aggregating the results of observations into the status of the findings of controls (all-pass, any-pass or no-fail)")
+	fs.BoolVar(&o.Deterministic, "deterministic", false, "generate the same output for the same inputs (UUIDs derived from the contents, timestamps from the inputs or SOURCE_DATE_EPOCH, and sorted collections)")
+	fs.StringVar(&o.AssessmentPlan, "assessment-plan", "", "path to OSCAL Assessment Plan imported by the assessment results. If not specified, the assessment results refer to the component-definition instead")
+	fs.StringVar(&o.Kubeconfig, "kubeconfig", "", "path to kubeconfig of a cluster whose API discovery resolves the scopes of the kinds of the manifests. If not specified, the kinds built into Kubernetes and the CustomResourceDefinitions of the manifests are resolved")
+}
+
+func (o *Options) Complete() error {
+	return nil
+}
+
+func (o *Options) Validate() error {
+	if o.C2PCRPath == "" {
+		return errors.New("-c or --config <c2p-config.yaml> is required")
+	}
+	if o.ManifestsDir == "" {
+		return errors.New("--manifests is required")
+	}
+	if _, err := format.ParseFormat(o.OutputFormat); err != nil {
+		return fmt.Errorf("--output-format: %w", err)
+	}
+	if _, err := oscal.ParseAggregationRule(o.AggregationRule); err != nil {
+		return fmt.Errorf("--aggregation-rule: %w", err)
+	}
+	return nil
+}
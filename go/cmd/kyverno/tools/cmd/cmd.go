@@ -20,6 +20,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/oscal-compass/compliance-to-policy/go/cmd/c2pcli/options"
+	bootstrapcdcmd "github.com/oscal-compass/compliance-to-policy/go/cmd/kyverno/tools/subcommands/bootstrapcd"
 	kyvernocmd "github.com/oscal-compass/compliance-to-policy/go/cmd/kyverno/tools/subcommands/kyverno"
 	oscal2posturecmd "github.com/oscal-compass/compliance-to-policy/go/cmd/pvpcommon/oscal2posture/cmd"
 	"github.com/oscal-compass/compliance-to-policy/go/pkg"
@@ -46,6 +47,7 @@ func New() *cobra.Command {
 	opts.AddFlags(command.Flags())
 
 	command.AddCommand(kyvernocmd.New())
+	command.AddCommand(bootstrapcdcmd.New())
 	command.AddCommand(oscal2posturecmd.New(pkg.GetLogger("kyverno/oscal2posture")))
 
 	return command
@@ -0,0 +1,104 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapcd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/oscal-compass/compliance-to-policy/go/pkg"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/kyverno"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal/format"
+)
+
+func New() *cobra.Command {
+	opts := NewOptions()
+
+	command := &cobra.Command{
+		Use:          "bootstrap-cd",
+		Short:        "Bootstrap a starter OSCAL Component Definition from Kyverno policy collection",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.Complete(); err != nil {
+				return err
+			}
+
+			if err := opts.Validate(); err != nil {
+				return err
+			}
+			return Run(opts, cmd.OutOrStdout())
+		},
+	}
+
+	opts.AddFlags(command.Flags())
+
+	return command
+}
+
+func Run(options *Options, out io.Writer) error {
+	mapping := kyverno.ControlMapping{}
+	if options.MappingPath != "" {
+		if err := pkg.LoadYamlFileToObject(options.MappingPath, &mapping); err != nil {
+			return err
+		}
+	}
+	mapping.Annotations = append(mapping.Annotations, options.ControlAnnotations...)
+
+	gitUtils := pkg.NewGitUtils(pkg.NewTempDirectory(options.TempDirPath))
+	cloneDir, path, err := gitUtils.GitClone(options.SourceUrl)
+	if err != nil {
+		return err
+	}
+	fl := kyverno.NewFileLoader()
+	if err := fl.LoadFromDirectory(cloneDir + "/" + path); err != nil {
+		return err
+	}
+
+	result, err := kyverno.BootstrapComponentDefinition(fl.GetPolicyResourceIndice(), mapping, kyverno.BootstrapOptions{
+		Title:                options.Title,
+		ComponentTitle:       options.ComponentTitle,
+		ComponentType:        options.ComponentType,
+		ComponentDescription: options.ComponentDescription,
+		Source:               options.Source,
+	}, oscal.NewStamper(options.Deterministic))
+	if err != nil {
+		return err
+	}
+
+	outputFormat, err := format.ParseFormat(options.OutputFormat)
+	if err != nil {
+		return err
+	}
+	if err := pkg.WriteOscalObjToFile(options.OutputPath, result.ComponentDefinition, outputFormat); err != nil {
+		return err
+	}
+
+	if len(result.UnmappedPolicies) > 0 {
+		fmt.Fprintf(out, "%d policies are not mapped to any controls. Please review them:\n", len(result.UnmappedPolicies))
+		for _, pri := range result.UnmappedPolicies {
+			category := pri.Category
+			if category == "" {
+				category = "-"
+			}
+			fmt.Fprintf(out, "  - %s (category: %s) %s\n", pri.Name, category, pri.SrcPath)
+		}
+	}
+	return nil
+}
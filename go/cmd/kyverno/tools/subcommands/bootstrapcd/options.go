@@ -0,0 +1,76 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapcd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/pflag"
+
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal/format"
+)
+
+const defaultSource = "https://raw.githubusercontent.com/usnistgov/oscal-content/master/nist.gov/SP800-53/rev5/json/NIST_SP-800-53_rev5_catalog.json"
+
+type Options struct {
+	SourceUrl            string
+	MappingPath          string
+	ControlAnnotations   []string
+	Title                string
+	ComponentTitle       string
+	ComponentType        string
+	ComponentDescription string
+	Source               string
+	OutputPath           string
+	OutputFormat         string
+	Deterministic        bool
+	TempDirPath          string
+}
+
+func NewOptions() *Options {
+	return &Options{}
+}
+
+func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.SourceUrl, "src", "", "url or path to a directory of Kyverno policy collection")
+	fs.StringVar(&o.MappingPath, "mapping", "", "path to a yaml file mapping policies.kyverno.io/category to control IDs ('categories: {<category>: [<control id>, ...]}') and listing annotations of control IDs ('annotations: [<annotation>, ...]')")
+	fs.StringSliceVar(&o.ControlAnnotations, "control-annotation", []string{}, "annotation of the policies whose value is comma-separated control IDs (can be specified multiple times)")
+	fs.StringVar(&o.Title, "title", "Component Definition of Kyverno Policies", "title of the component-definition")
+	fs.StringVar(&o.ComponentTitle, "component-title", "Kubernetes", "title of the component validated by the policies")
+	fs.StringVar(&o.ComponentType, "component-type", "Service", "type of the component validated by the policies")
+	fs.StringVar(&o.ComponentDescription, "component-description", "Kubernetes cluster validated by Kyverno policies", "description of the component validated by the policies")
+	fs.StringVar(&o.Source, "source", defaultSource, "href of the catalog or profile of the controls")
+	fs.StringVarP(&o.OutputPath, "out", "o", "./component-definition.json", "path to output OSCAL Component Definition")
+	fs.StringVar(&o.OutputFormat, "output-format", "json", "format of output OSCAL Component Definition (json, yaml or xml)")
+	fs.BoolVar(&o.Deterministic, "deterministic", false, "generate the same output for the same inputs (UUIDs derived from the contents and timestamp from SOURCE_DATE_EPOCH)")
+	fs.StringVar(&o.TempDirPath, "temp-dir", "", "path to temp directory (default: system-defined temporary directory)")
+}
+
+func (o *Options) Complete() error {
+	return nil
+}
+
+func (o *Options) Validate() error {
+	if o.SourceUrl == "" {
+		return errors.New("--src is required")
+	}
+	if _, err := format.ParseFormat(o.OutputFormat); err != nil {
+		return fmt.Errorf("--output-format: %w", err)
+	}
+	return nil
+}
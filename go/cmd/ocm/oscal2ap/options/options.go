@@ -0,0 +1,60 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/pflag"
+
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal/format"
+)
+
+type Options struct {
+	C2PCRPath     string
+	TempDirPath   string
+	OutputPath    string
+	OutputFormat  string
+	Deterministic bool
+}
+
+func NewOptions() *Options {
+	return &Options{}
+}
+
+func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVarP(&o.C2PCRPath, "config", "c", "", "path to c2p config file")
+	fs.StringVar(&o.TempDirPath, "temp-dir", "", "path to temp directory")
+	fs.StringVarP(&o.OutputPath, "out", "o", "./assessment-plan.json", "path to output OSCAL Assessment Plan")
+	fs.StringVar(&o.OutputFormat, "output-format", "json", "format of output OSCAL Assessment Plan (json, yaml or xml)")
+	fs.BoolVar(&o.Deterministic, "deterministic", false, "generate the same output for the same inputs (UUIDs derived from the contents, timestamps from the inputs or SOURCE_DATE_EPOCH)")
+}
+
+func (o *Options) Complete() error {
+	return nil
+}
+
+func (o *Options) Validate() error {
+	if o.C2PCRPath == "" {
+		return errors.New("-c or --config is required")
+	}
+	if _, err := format.ParseFormat(o.OutputFormat); err != nil {
+		return fmt.Errorf("--output-format: %w", err)
+	}
+	return nil
+}
@@ -70,6 +70,7 @@ func Run(options *options.Options) error {
 
 	tmpdir := pkg.NewTempDirectory(options.TempDirPath)
 	composer := ocm.NewComposerByTempDirectory(c2pcrParsed.PolicyResoureDir, tmpdir)
+	composer.SetParameterMode(ocm.ParameterMode(options.ParameterMode))
 	if err := composer.ComposeByC2PParsed(c2pcrParsed); err != nil {
 		panic(err)
 	}
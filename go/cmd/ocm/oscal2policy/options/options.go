@@ -18,8 +18,11 @@ package options
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/spf13/pflag"
+
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/ocm"
 )
 
 type Options struct {
@@ -27,6 +30,7 @@ type Options struct {
 	TempDirPath                 string
 	OutputDir                   string
 	OutputDirForPolicyGenerator string
+	ParameterMode               string
 }
 
 func NewOptions() *Options {
@@ -38,6 +42,7 @@ func (o *Options) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&o.TempDirPath, "temp-dir", "", "path to temp directory")
 	fs.StringVarP(&o.OutputDir, "out", "o", ".", "path to a directory for output manifest files of generated OCM Policy manifests")
 	fs.StringVar(&o.OutputDirForPolicyGenerator, "out-for-policy-generator", "", "path to a directory for output files for policy generator to generate OCM Policy manifests (default: system temporary directory or directory specified by --temp-dir)")
+	fs.StringVar(&o.ParameterMode, "parameter-mode", string(ocm.ParameterModeHubTemplate), "how to resolve parameter placeholders in policy manifests (hub-template: refer to the parameters configmap by hub templates, substitute: replace with the parameter values)")
 }
 
 func (o *Options) Complete() error {
@@ -48,5 +53,8 @@ func (o *Options) Validate() error {
 	if o.C2PCRPath == "" {
 		return errors.New("-c or --config is required")
 	}
+	if o.ParameterMode != string(ocm.ParameterModeHubTemplate) && o.ParameterMode != string(ocm.ParameterModeSubstitute) {
+		return fmt.Errorf("--parameter-mode must be %s or %s", ocm.ParameterModeHubTemplate, ocm.ParameterModeSubstitute)
+	}
 	return nil
 }
@@ -22,7 +22,10 @@ import (
 	"github.com/oscal-compass/compliance-to-policy/go/cmd/ocm/result2oscal/options"
 	"github.com/oscal-compass/compliance-to-policy/go/pkg"
 	"github.com/oscal-compass/compliance-to-policy/go/pkg/ocm"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal/format"
 	typec2pcr "github.com/oscal-compass/compliance-to-policy/go/pkg/types/c2pcr"
+	typeap "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/assessmentplan"
 )
 
 func New() *cobra.Command {
@@ -64,12 +67,31 @@ func Run(options *options.Options) error {
 	}
 
 	r := ocm.NewResultToOscal(c2pcrParsed, policyResultsDir)
+	aggregationRule, err := oscal.ParseAggregationRule(options.AggregationRule)
+	if err != nil {
+		return err
+	}
+	r.SetAggregationRule(aggregationRule)
+	r.SetDeterministic(options.Deterministic)
+
+	outputFormat, err := format.ParseFormat(options.OutputFormat)
+	if err != nil {
+		return err
+	}
+	if options.AssessmentPlan != "" {
+		var apRoot typeap.AssessmentPlanRoot
+		if err := pkg.LoadOscalFileToObject(options.AssessmentPlan, &apRoot); err != nil {
+			return err
+		}
+		r.SetAssessmentPlan(oscal.AssessmentPlanHref(outputPath, options.AssessmentPlan), apRoot.AssessmentPlan)
+	}
+
 	arRoot, err := r.Generate()
 	if err != nil {
 		panic(err)
 	}
 
-	err = pkg.WriteObjToJsonFile(outputPath, arRoot)
+	err = pkg.WriteOscalObjToFile(outputPath, arRoot, outputFormat)
 	if err != nil {
 		return err
 	}
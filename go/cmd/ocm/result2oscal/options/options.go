@@ -18,8 +18,12 @@ package options
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/spf13/pflag"
+
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal/format"
 )
 
 type Options struct {
@@ -27,6 +31,10 @@ type Options struct {
 	PolicyResultsDir string
 	TempDirPath      string
 	OutputPath       string
+	OutputFormat     string
+	AggregationRule  string
+	Deterministic    bool
+	AssessmentPlan   string
 }
 
 func NewOptions() *Options {
@@ -38,6 +46,10 @@ func (o *Options) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&o.PolicyResultsDir, "results", "", "path to directory containing OCM Policy List (placementdecisions.cluster.open-cluster-management.io.yaml), OCM PolicySet List (policysets.policy.open-cluster-management.io.yaml), and OCM PlacementDecisions List (placementdecisions.cluster.open-cluster-management.io.yaml)")
 	fs.StringVar(&o.TempDirPath, "temp-dir", "", "path to temp directory")
 	fs.StringVarP(&o.OutputPath, "out", "o", "./assessment-results.json", "path to output OSCAL Assessment Results")
+	fs.StringVar(&o.OutputFormat, "output-format", "json", "format of output OSCAL Assessment Results (json, yaml or xml)")
+	fs.StringVar(&o.AggregationRule, "aggregation-rule", string(oscal.AggregationRuleAllPass), "rule aggregating the results of observations into the status of the findings of controls (all-pass, any-pass or no-fail)")
+	fs.BoolVar(&o.Deterministic, "deterministic", false, "generate the same output for the same inputs (UUIDs derived from the contents, timestamps from the inputs or SOURCE_DATE_EPOCH, and sorted collections)")
+	fs.StringVar(&o.AssessmentPlan, "assessment-plan", "", "path to OSCAL Assessment Plan imported by the assessment results. If not specified, the assessment results refer to the component-definition instead")
 }
 
 func (o *Options) Complete() error {
@@ -51,5 +63,11 @@ func (o *Options) Validate() error {
 	if o.PolicyResultsDir == "" {
 		return errors.New("--results is required")
 	}
+	if _, err := format.ParseFormat(o.OutputFormat); err != nil {
+		return fmt.Errorf("--output-format: %w", err)
+	}
+	if _, err := oscal.ParseAggregationRule(o.AggregationRule); err != nil {
+		return fmt.Errorf("--aggregation-rule: %w", err)
+	}
 	return nil
 }
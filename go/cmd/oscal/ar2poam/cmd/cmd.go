@@ -0,0 +1,79 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/oscal-compass/compliance-to-policy/go/cmd/oscal/ar2poam/options"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal/format"
+	typear "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/assessmentresults"
+	typepoam "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/poam"
+)
+
+func New() *cobra.Command {
+	opts := options.NewOptions()
+
+	command := &cobra.Command{
+		Use:          "ar2poam <file>",
+		Short:        "Generate (or update) OSCAL POA&M from failing findings of OSCAL Assessment Results",
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.Complete(args); err != nil {
+				return err
+			}
+
+			if err := opts.Validate(); err != nil {
+				return err
+			}
+			return Run(opts)
+		},
+	}
+
+	opts.AddFlags(command.Flags())
+
+	return command
+}
+
+func Run(options *options.Options) error {
+	var arRoot typear.AssessmentResultsRoot
+	if err := pkg.LoadOscalFileToObject(options.FilePath, &arRoot); err != nil {
+		return err
+	}
+
+	var existing *typepoam.PlanOfActionAndMilestonesRoot
+	if options.PoamPath != "" {
+		existing = &typepoam.PlanOfActionAndMilestonesRoot{}
+		if err := pkg.LoadOscalFileToObject(options.PoamPath, existing); err != nil {
+			return err
+		}
+	}
+
+	poamRoot, err := oscal.GeneratePoam(arRoot, existing, oscal.NewStamper(options.Deterministic))
+	if err != nil {
+		return err
+	}
+
+	outputFormat, err := format.ParseFormat(options.OutputFormat)
+	if err != nil {
+		return err
+	}
+	return pkg.WriteOscalObjToFile(options.OutputPath, poamRoot, outputFormat)
+}
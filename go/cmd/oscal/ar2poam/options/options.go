@@ -0,0 +1,62 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/pflag"
+
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal/format"
+)
+
+type Options struct {
+	FilePath      string
+	PoamPath      string
+	OutputPath    string
+	OutputFormat  string
+	Deterministic bool
+}
+
+func NewOptions() *Options {
+	return &Options{}
+}
+
+func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.PoamPath, "poam", "", "path to existing OSCAL POA&M to be updated")
+	fs.StringVarP(&o.OutputPath, "out", "o", "./poam.json", "path to output OSCAL POA&M")
+	fs.StringVar(&o.OutputFormat, "output-format", "json", "format of output OSCAL POA&M (json, yaml or xml)")
+	fs.BoolVar(&o.Deterministic, "deterministic", false, "generate the same output for the same inputs (UUIDs derived from the contents, timestamps from the inputs or SOURCE_DATE_EPOCH)")
+}
+
+func (o *Options) Complete(args []string) error {
+	if len(args) > 0 {
+		o.FilePath = args[0]
+	}
+	return nil
+}
+
+func (o *Options) Validate() error {
+	if o.FilePath == "" {
+		return errors.New("path to an OSCAL assessment-results is required")
+	}
+	if _, err := format.ParseFormat(o.OutputFormat); err != nil {
+		return fmt.Errorf("--output-format: %w", err)
+	}
+	return nil
+}
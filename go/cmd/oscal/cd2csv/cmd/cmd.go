@@ -0,0 +1,72 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/oscal-compass/compliance-to-policy/go/cmd/oscal/cd2csv/options"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal"
+	typecd "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/componentdefinition"
+)
+
+func New() *cobra.Command {
+	opts := options.NewOptions()
+
+	command := &cobra.Command{
+		Use:          "cd2csv <file>",
+		Short:        "Generate trestle CSV from OSCAL Component Definition",
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.Complete(args); err != nil {
+				return err
+			}
+
+			if err := opts.Validate(); err != nil {
+				return err
+			}
+			return Run(opts, cmd.OutOrStdout())
+		},
+	}
+
+	opts.AddFlags(command.Flags())
+
+	return command
+}
+
+func Run(options *options.Options, out io.Writer) error {
+	var cdRoot typecd.ComponentDefinitionRoot
+	if err := pkg.LoadOscalFileToObject(options.FilePath, &cdRoot); err != nil {
+		return err
+	}
+
+	rows := oscal.MakeTrestleCsvFromComponentDefinition(cdRoot)
+	if options.OutputPath == "-" {
+		return oscal.WriteTrestleCsv(out, rows)
+	}
+	f, err := os.Create(options.OutputPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return oscal.WriteTrestleCsv(f, rows)
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"errors"
+
+	"github.com/spf13/pflag"
+)
+
+type Options struct {
+	FilePath   string
+	OutputPath string
+}
+
+func NewOptions() *Options {
+	return &Options{}
+}
+
+func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVarP(&o.OutputPath, "out", "o", "-", "path to output trestle CSV. Use '-' for stdout. Default '-'.")
+}
+
+func (o *Options) Complete(args []string) error {
+	if len(args) > 0 {
+		o.FilePath = args[0]
+	}
+	return nil
+}
+
+func (o *Options) Validate() error {
+	if o.FilePath == "" {
+		return errors.New("path to an OSCAL component-definition is required")
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/oscal-compass/compliance-to-policy/go/cmd/oscal/coverage/options"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal"
+	typeoscal "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal"
+	typecd "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/componentdefinition"
+)
+
+func New() *cobra.Command {
+	opts := options.NewOptions()
+
+	command := &cobra.Command{
+		Use:          "coverage <file>",
+		Short:        "Report coverage of the controls required by OSCAL Profile by OSCAL Component Definition and policies",
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.Complete(args); err != nil {
+				return err
+			}
+
+			if err := opts.Validate(); err != nil {
+				return err
+			}
+			return Run(opts, cmd.OutOrStdout())
+		},
+	}
+
+	opts.AddFlags(command.Flags())
+
+	return command
+}
+
+func Run(options *options.Options, out io.Writer) error {
+	var cdRoot typecd.ComponentDefinitionRoot
+	if err := pkg.LoadOscalFileToObject(options.FilePath, &cdRoot); err != nil {
+		return err
+	}
+
+	resolver := oscal.NewProfileResolver(pkg.NewGitUtils(pkg.NewTempDirectory(options.TempDirPath)))
+	resolvedProfile, err := resolver.Resolve(options.Profile)
+	if err != nil {
+		return err
+	}
+	var catalog *typeoscal.Catalog
+	if options.Catalog != "" {
+		catalog, err = resolver.Resolve(options.Catalog)
+		if err != nil {
+			return err
+		}
+	}
+
+	report, err := oscal.AnalyzeCoverage(*resolvedProfile, catalog, cdRoot, oscal.CoverageOptions{
+		PVP:       options.PVP,
+		PolicyDir: options.PolicyDir,
+	})
+	if err != nil {
+		return err
+	}
+
+	if options.OutputFormat == "json" {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+	return writeText(out, report)
+}
+
+func writeText(out io.Writer, report oscal.CoverageReport) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "FAMILY\tTITLE\tREQUIRED\tIMPLEMENTED\tWITH RULES\tIMPLEMENTED %\tWITH RULES %")
+	for _, family := range append(report.Families, report.Total) {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%.1f\t%.1f\n", family.GroupId, family.GroupTitle, family.Required, family.Implemented, family.WithRules, family.ImplementedPercent, family.WithRulesPercent)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "\nControls required by the profile but not implemented (%d): %s\n", len(report.NotImplementedControls), strings.Join(report.NotImplementedControls, ", "))
+	fmt.Fprintf(out, "Controls implemented with no rules (%d): %s\n", len(report.ControlsWithoutRules), strings.Join(report.ControlsWithoutRules, ", "))
+	fmt.Fprintf(out, "Rules without policy (%d):\n", len(report.RulesWithoutPolicy))
+	for _, rule := range report.RulesWithoutPolicy {
+		if len(rule.PolicyIds) == 0 {
+			fmt.Fprintf(out, "  - %s: %s has no policy ID\n", rule.Component, rule.RuleId)
+		} else {
+			fmt.Fprintf(out, "  - %s: %s (missing %s)\n", rule.Component, rule.RuleId, strings.Join(rule.PolicyIds, ", "))
+		}
+	}
+	fmt.Fprintf(out, "Policies referred by no rules (%d): %s\n", len(report.UnreferencedPolicies), strings.Join(report.UnreferencedPolicies, ", "))
+	return nil
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/pflag"
+
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal"
+)
+
+type Options struct {
+	FilePath     string
+	Profile      string
+	Catalog      string
+	PVP          string
+	PolicyDir    string
+	TempDirPath  string
+	OutputFormat string
+}
+
+func NewOptions() *Options {
+	return &Options{}
+}
+
+func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.Profile, "profile", "", "path or URL to the profile requiring the controls")
+	fs.StringVar(&o.Catalog, "catalog", "", "path or URL to the catalog defining the families of the controls. Defaults to the resolved profile.")
+	fs.StringVar(&o.PVP, "pvp", oscal.LintPVPOcm, "PVP whose conventions map the rules to the policies (ocm or kyverno)")
+	fs.StringVar(&o.PolicyDir, "policy-dir", "", "path to directory of the policy resources, containing a directory for each Policy_Id (ocm) or Rule_Id (kyverno)")
+	fs.StringVar(&o.TempDirPath, "temp-dir", "", "path to temp directory")
+	fs.StringVar(&o.OutputFormat, "output-format", "text", "format of the coverage report (text or json)")
+}
+
+func (o *Options) Complete(args []string) error {
+	if len(args) > 0 {
+		o.FilePath = args[0]
+	}
+	return nil
+}
+
+func (o *Options) Validate() error {
+	if o.FilePath == "" {
+		return errors.New("path to an OSCAL component-definition is required")
+	}
+	if o.Profile == "" {
+		return errors.New("--profile is required")
+	}
+	if o.PVP != oscal.LintPVPOcm && o.PVP != oscal.LintPVPKyverno {
+		return fmt.Errorf("--pvp: unsupported PVP %s: must be %s or %s", o.PVP, oscal.LintPVPOcm, oscal.LintPVPKyverno)
+	}
+	if o.OutputFormat != "text" && o.OutputFormat != "json" {
+		return fmt.Errorf("--output-format: unsupported format %s: must be text or json", o.OutputFormat)
+	}
+	return nil
+}
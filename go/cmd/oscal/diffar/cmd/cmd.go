@@ -0,0 +1,136 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/oscal-compass/compliance-to-policy/go/cmd/oscal/diffar/options"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal"
+	typear "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/assessmentresults"
+)
+
+func New() *cobra.Command {
+	opts := options.NewOptions()
+
+	command := &cobra.Command{
+		Use:          "diff-ar <old-file> <new-file>",
+		Short:        "Report compliance drift between two OSCAL Assessment Results",
+		Long:         "Report compliance drift between two OSCAL Assessment Results. It exits with non-zero code if any control or subject newly fails.",
+		Args:         cobra.ExactArgs(2),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.Complete(args); err != nil {
+				return err
+			}
+
+			if err := opts.Validate(); err != nil {
+				return err
+			}
+			return Run(opts, cmd.OutOrStdout())
+		},
+	}
+
+	opts.AddFlags(command.Flags())
+
+	return command
+}
+
+type report struct {
+	Old         string `json:"old"`
+	New         string `json:"new"`
+	Regressions int    `json:"regressions"`
+	oscal.AssessmentResultsDiff
+}
+
+func Run(options *options.Options, out io.Writer) error {
+	var oldAr, newAr typear.AssessmentResultsRoot
+	if err := pkg.LoadOscalFileToObject(options.OldFilePath, &oldAr); err != nil {
+		return err
+	}
+	if err := pkg.LoadOscalFileToObject(options.NewFilePath, &newAr); err != nil {
+		return err
+	}
+
+	diff := oscal.DiffAssessmentResults(oldAr, newAr)
+	r := report{Old: options.OldFilePath, New: options.NewFilePath, Regressions: diff.Regressions(), AssessmentResultsDiff: diff}
+
+	if options.OutputFormat == "json" {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(r); err != nil {
+			return err
+		}
+	} else {
+		writeMarkdown(out, r)
+	}
+
+	if r.Regressions > 0 {
+		return fmt.Errorf("%s has regressions from %s: %d regression(s)", r.New, r.Old, r.Regressions)
+	}
+	return nil
+}
+
+func writeMarkdown(out io.Writer, r report) {
+	fmt.Fprintf(out, "## Compliance drift\n%s -> %s\n\n", r.Old, r.New)
+	fmt.Fprintf(out, "Regressions: %d\n", r.Regressions)
+
+	writeControls := func(title string, controls []oscal.ControlDiff) {
+		fmt.Fprintf(out, "\n### %s (%d)\n", title, len(controls))
+		if len(controls) == 0 {
+			return
+		}
+		fmt.Fprintf(out, "| Control | Statement | Old state | New state |\n| --- | --- | --- | --- |\n")
+		for _, control := range controls {
+			fmt.Fprintf(out, "| %s | %s | %s | %s |\n", control.ControlId, control.StatementId, control.OldState, control.NewState)
+		}
+	}
+	writeSubjects := func(title string, subjects []oscal.SubjectDiff) {
+		fmt.Fprintf(out, "\n### %s (%d)\n", title, len(subjects))
+		if len(subjects) == 0 {
+			return
+		}
+		fmt.Fprintf(out, "| Rule | Check | Policy | Controls | Subject | Old result | New result | Old reason | New reason | Regression |\n")
+		fmt.Fprintf(out, "| --- | --- | --- | --- | --- | --- | --- | --- | --- | --- |\n")
+		for _, subject := range subjects {
+			controls := strings.Join(subject.Controls, ", ")
+			if subject.OldControls != nil {
+				controls = fmt.Sprintf("%s -> %s", strings.Join(subject.OldControls, ", "), controls)
+			}
+			fmt.Fprintf(out, "| %s | %s | %s | %s | %s | %s | %s | %s | %s | %t |\n",
+				subject.RuleId, subject.CheckId, subject.PolicyId, controls, escape(subject.Subject),
+				subject.OldResult, subject.NewResult, escape(subject.OldReason), escape(subject.NewReason), subject.Regression)
+		}
+	}
+
+	writeControls("Newly failing controls", r.NewlyFailingControls)
+	writeControls("Fixed controls", r.FixedControls)
+	writeSubjects("New subjects", r.NewSubjects)
+	writeSubjects("Removed subjects", r.RemovedSubjects)
+	writeSubjects("Changed subjects", r.ChangedSubjects)
+}
+
+// escape makes the text a cell of a markdown table
+func escape(text string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(text, "|", "\\|"), "\n", " ")
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/pflag"
+)
+
+type Options struct {
+	OldFilePath  string
+	NewFilePath  string
+	OutputFormat string
+}
+
+func NewOptions() *Options {
+	return &Options{}
+}
+
+func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.OutputFormat, "output-format", "markdown", "format of the diff report (markdown or json)")
+}
+
+func (o *Options) Complete(args []string) error {
+	o.OldFilePath, o.NewFilePath = args[0], args[1]
+	return nil
+}
+
+func (o *Options) Validate() error {
+	if o.OldFilePath == "" || o.NewFilePath == "" {
+		return errors.New("paths to the old and new OSCAL assessment-results are required")
+	}
+	if o.OutputFormat != "markdown" && o.OutputFormat != "json" {
+		return fmt.Errorf("--output-format: unsupported format %s: must be markdown or json", o.OutputFormat)
+	}
+	return nil
+}
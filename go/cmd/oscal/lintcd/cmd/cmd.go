@@ -0,0 +1,110 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/oscal-compass/compliance-to-policy/go/cmd/oscal/lintcd/options"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal"
+	typeoscal "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal"
+	typecd "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/componentdefinition"
+)
+
+func New() *cobra.Command {
+	opts := options.NewOptions()
+
+	command := &cobra.Command{
+		Use:          "lint-cd <file>",
+		Short:        "Check rules, parameters and controls of OSCAL Component Definition",
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.Complete(args); err != nil {
+				return err
+			}
+
+			if err := opts.Validate(); err != nil {
+				return err
+			}
+			return Run(opts, cmd.OutOrStdout())
+		},
+	}
+
+	opts.AddFlags(command.Flags())
+
+	return command
+}
+
+type report struct {
+	File   string            `json:"file"`
+	Valid  bool              `json:"valid"`
+	Issues []oscal.LintIssue `json:"issues"`
+}
+
+func Run(options *options.Options, out io.Writer) error {
+	var cdRoot typecd.ComponentDefinitionRoot
+	if err := pkg.LoadOscalFileToObject(options.FilePath, &cdRoot); err != nil {
+		return err
+	}
+
+	resolver := oscal.NewProfileResolver(pkg.NewGitUtils(pkg.NewTempDirectory(options.TempDirPath)))
+	resolveCatalog := func(source string) (*typeoscal.Catalog, error) {
+		return resolver.Resolve(source)
+	}
+	if options.Catalog != "" {
+		catalog, err := resolver.Resolve(options.Catalog)
+		if err != nil {
+			return err
+		}
+		resolveCatalog = func(source string) (*typeoscal.Catalog, error) {
+			return catalog, nil
+		}
+	}
+
+	issues := oscal.LintComponentDefinition(cdRoot, oscal.LintOptions{
+		PVP:            options.PVP,
+		PolicyDir:      options.PolicyDir,
+		ResolveCatalog: resolveCatalog,
+	})
+	r := report{File: options.FilePath, Valid: !oscal.HasLintErrors(issues), Issues: issues}
+
+	if options.OutputFormat == "json" {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(r); err != nil {
+			return err
+		}
+	} else {
+		for _, issue := range r.Issues {
+			fmt.Fprintf(out, "%s: %s\n", r.File, issue.String())
+		}
+		if r.Valid {
+			fmt.Fprintf(out, "%s: no errors\n", r.File)
+		}
+	}
+
+	if !r.Valid {
+		return fmt.Errorf("%s has lint errors: %d issue(s)", r.File, len(r.Issues))
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/oscal-compass/compliance-to-policy/go/cmd/oscal/mergear/options"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal/format"
+	typear "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/assessmentresults"
+)
+
+func New() *cobra.Command {
+	opts := options.NewOptions()
+
+	command := &cobra.Command{
+		Use:          "merge-ar <file> [<file>...]",
+		Short:        "Merge OSCAL Assessment Results of multiple PVPs into one",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.Complete(args); err != nil {
+				return err
+			}
+
+			if err := opts.Validate(); err != nil {
+				return err
+			}
+			return Run(opts)
+		},
+	}
+
+	opts.AddFlags(command.Flags())
+
+	return command
+}
+
+func Run(options *options.Options) error {
+	sources := []oscal.AssessmentResultsSource{}
+	for _, filePath := range options.FilePaths {
+		var arRoot typear.AssessmentResultsRoot
+		if err := pkg.LoadOscalFileToObject(filePath, &arRoot); err != nil {
+			return err
+		}
+		sources = append(sources, oscal.AssessmentResultsSource{Name: filePath, AssessmentResults: arRoot})
+	}
+
+	aggregationRule, err := oscal.ParseAggregationRule(options.AggregationRule)
+	if err != nil {
+		return err
+	}
+	ar, err := oscal.MergeAssessmentResults(sources, oscal.MergeOptions{
+		AggregationRule: aggregationRule,
+		Stamper:         oscal.NewStamper(options.Deterministic),
+	})
+	if err != nil {
+		return err
+	}
+
+	outputFormat, err := format.ParseFormat(options.OutputFormat)
+	if err != nil {
+		return err
+	}
+	return pkg.WriteOscalObjToFile(options.OutputPath, ar, outputFormat)
+}
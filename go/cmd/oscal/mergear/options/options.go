@@ -0,0 +1,64 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/pflag"
+
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal/format"
+)
+
+type Options struct {
+	FilePaths       []string
+	OutputPath      string
+	OutputFormat    string
+	AggregationRule string
+	Deterministic   bool
+}
+
+func NewOptions() *Options {
+	return &Options{}
+}
+
+func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVarP(&o.OutputPath, "out", "o", "./assessment-results.json", "path to output OSCAL Assessment Results")
+	fs.StringVar(&o.OutputFormat, "output-format", "json", "format of output OSCAL Assessment Results (json, yaml or xml)")
+	fs.StringVar(&o.AggregationRule, "aggregation-rule", string(oscal.AggregationRuleAllPass), "rule aggregating the statuses of the findings of a control across the assessment results (all-pass, any-pass or no-fail)")
+	fs.BoolVar(&o.Deterministic, "deterministic", false, "generate the same output for the same inputs (UUIDs derived from the contents, timestamps from the inputs or SOURCE_DATE_EPOCH, and sorted collections)")
+}
+
+func (o *Options) Complete(args []string) error {
+	o.FilePaths = args
+	return nil
+}
+
+func (o *Options) Validate() error {
+	if len(o.FilePaths) == 0 {
+		return errors.New("paths to OSCAL assessment-results are required")
+	}
+	if _, err := format.ParseFormat(o.OutputFormat); err != nil {
+		return fmt.Errorf("--output-format: %w", err)
+	}
+	if _, err := oscal.ParseAggregationRule(o.AggregationRule); err != nil {
+		return fmt.Errorf("--aggregation-rule: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/oscal-compass/compliance-to-policy/go/cmd/oscal/validate/options"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal/format"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal/validation"
+)
+
+func New() *cobra.Command {
+	opts := options.NewOptions()
+
+	command := &cobra.Command{
+		Use:          "validate <file>",
+		Short:        "Validate OSCAL document against the OSCAL JSON schema",
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.Complete(args); err != nil {
+				return err
+			}
+
+			if err := opts.Validate(); err != nil {
+				return err
+			}
+			return Run(opts, cmd.OutOrStdout())
+		},
+	}
+
+	opts.AddFlags(command.Flags())
+
+	return command
+}
+
+type report struct {
+	File   string                       `json:"file"`
+	Model  string                       `json:"model"`
+	Valid  bool                         `json:"valid"`
+	Errors []validation.ValidationError `json:"errors,omitempty"`
+}
+
+func Run(options *options.Options, out io.Writer) error {
+	data, err := os.ReadFile(options.FilePath)
+	if err != nil {
+		return err
+	}
+	jsonData, err := format.ToJson(data, format.Detect(options.FilePath, data))
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", options.FilePath, err)
+	}
+	model, err := validation.DetectModel(jsonData)
+	if err != nil {
+		return err
+	}
+
+	r := report{File: options.FilePath, Model: model, Valid: true}
+	err = validation.ValidateModel(model, jsonData)
+	var validationErrs *validation.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		r.Valid = false
+		r.Errors = validationErrs.Errors
+	} else if err != nil {
+		return err
+	}
+
+	if options.OutputFormat == "json" {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(r); err != nil {
+			return err
+		}
+	} else {
+		if r.Valid {
+			fmt.Fprintf(out, "%s: valid %s\n", r.File, r.Model)
+		}
+		for _, e := range r.Errors {
+			fmt.Fprintf(out, "%s: %s (schema: %s)\n", r.File, e.Error(), e.KeywordLocation)
+		}
+	}
+
+	if !r.Valid {
+		return fmt.Errorf("%s is not a valid %s: %d schema violation(s)", r.File, r.Model, len(r.Errors))
+	}
+	return nil
+}
@@ -73,7 +73,13 @@ func Run(options *options.Options, logger *zap.Logger) error {
 		return err
 	}
 
+	format, err := pvpcommon.ParsePostureFormat(options.Format)
+	if err != nil {
+		return err
+	}
+
 	r := pvpcommon.NewOscal2Posture(c2pcrParsed, arRoot, nil, logger)
+	r.SetFormat(format)
 	data, err := r.Generate()
 	if err != nil {
 		return err
@@ -20,6 +20,8 @@ import (
 	"errors"
 
 	"github.com/spf13/pflag"
+
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/pvpcommon"
 )
 
 type Options struct {
@@ -27,6 +29,7 @@ type Options struct {
 	AssessmentResults string
 	TempDirPath       string
 	Out               string
+	Format            string
 }
 
 func NewOptions() *Options {
@@ -38,6 +41,7 @@ func (o *Options) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&o.AssessmentResults, "assessment-results", "", "path or url to assessment-results.json")
 	fs.StringVar(&o.TempDirPath, "temp-dir", "", "path to temp directory")
 	fs.StringVarP(&o.Out, "out", "o", "-", "path to output file. Use '-' for stdout. Default '-'.")
+	fs.StringVar(&o.Format, "format", string(pvpcommon.PostureFormatMarkdown), "format of the compliance posture (markdown, html, json or csv)")
 }
 
 func (o *Options) Complete() error {
@@ -48,5 +52,8 @@ func (o *Options) Validate() error {
 	if o.C2PCRPath == "" {
 		return errors.New("-c or --config <c2p-config.yaml> is required")
 	}
+	if _, err := pvpcommon.ParsePostureFormat(o.Format); err != nil {
+		return err
+	}
 	return nil
 }
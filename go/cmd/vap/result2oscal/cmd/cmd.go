@@ -0,0 +1,96 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/oscal-compass/compliance-to-policy/go/cmd/vap/result2oscal/options"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal/format"
+	typec2pcr "github.com/oscal-compass/compliance-to-policy/go/pkg/types/c2pcr"
+	typeap "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/assessmentplan"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/vap"
+)
+
+func New() *cobra.Command {
+	opts := options.NewOptions()
+
+	command := &cobra.Command{
+		Use:          "result2oscal",
+		Short:        "Generate OSCAL Assessment Results from the audit events of ValidatingAdmissionPolicies",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.Complete(); err != nil {
+				return err
+			}
+
+			if err := opts.Validate(); err != nil {
+				return err
+			}
+			return Run(opts)
+		},
+	}
+
+	opts.AddFlags(command.Flags())
+
+	return command
+}
+
+func Run(options *options.Options) error {
+	outputPath := options.OutputPath
+
+	var c2pcrSpec typec2pcr.Spec
+	if err := pkg.LoadYamlFileToObject(options.C2PCRPath, &c2pcrSpec); err != nil {
+		return err
+	}
+
+	gitUtils := pkg.NewGitUtils(pkg.NewTempDirectory(options.TempDirPath))
+	c2pcrParser := vap.NewParser(gitUtils)
+	c2pcrParsed, err := c2pcrParser.Parse(c2pcrSpec)
+	if err != nil {
+		return err
+	}
+
+	r := vap.NewResultToOscal(c2pcrParsed, options.AuditLogPath)
+	aggregationRule, err := oscal.ParseAggregationRule(options.AggregationRule)
+	if err != nil {
+		return err
+	}
+	r.SetAggregationRule(aggregationRule)
+	r.SetDeterministic(options.Deterministic)
+
+	outputFormat, err := format.ParseFormat(options.OutputFormat)
+	if err != nil {
+		return err
+	}
+	if options.AssessmentPlan != "" {
+		var apRoot typeap.AssessmentPlanRoot
+		if err := pkg.LoadOscalFileToObject(options.AssessmentPlan, &apRoot); err != nil {
+			return err
+		}
+		r.SetAssessmentPlan(oscal.AssessmentPlanHref(outputPath, options.AssessmentPlan), apRoot.AssessmentPlan)
+	}
+
+	ar, err := r.GenerateAssessmentResults()
+	if err != nil {
+		return err
+	}
+
+	return pkg.WriteOscalObjToFile(outputPath, ar, outputFormat)
+}
@@ -189,10 +189,15 @@ func makeControlReference(tempDir string, compDeploy c2pv1alpha1.ComplianceDeplo
 		return intCompliance, summary, _crComposit, err
 	}
 
-	profiledCd := oscal.IntersectProfileWithCD(cdobj.ComponentDefinition, profileObj.Profile)
+	resolver := oscal.NewProfileResolver(pkg.NewGitUtils(pkg.NewTempDirectory(tempDir)))
+	resolvedProfile, profiledCd, err := resolver.IntersectWithCD(profileObj.Profile, compDeploy.Spec.Compliance.Profile.Url, cdobj.ComponentDefinition)
+	if err != nil {
+		logger.Error(err, "Failed to resolve profile")
+		return intCompliance, summary, _crComposit, err
+	}
 	intCompliance = oscal.MakeInternalCompliance(catalogObj.Catalog, profileObj.Profile, profiledCd)
 
-	summary = logControlIds(logger, profileObj.Profile, cdobj.ComponentDefinition, intCompliance)
+	summary = logControlIds(logger, *resolvedProfile, cdobj.ComponentDefinition, intCompliance)
 	summary["name"] = profileObj.Metadata.Title
 	summary["compliance-definition-name"] = compDeploy.Name
 	summary["compliance-definition-namespace"] = compDeploy.Spec.Target.Namespace
@@ -316,13 +321,8 @@ func gitClone(url string, tmpdir string) (string, error) {
 	return dir, nil
 }
 
-func logControlIds(logger logr.Logger, profile typesoscal.Profile, compDef cd.ComponentDefinition, intCompliance internalcompliance.Compliance) map[string]string {
-	controlIdsInProfile := []string{}
-	for _, profileImport := range profile.Imports {
-		for _, includeControl := range profileImport.IncludeControls {
-			controlIdsInProfile = append(controlIdsInProfile, includeControl.WithIds...)
-		}
-	}
+func logControlIds(logger logr.Logger, resolvedProfile typesoscal.Catalog, compDef cd.ComponentDefinition, intCompliance internalcompliance.Compliance) map[string]string {
+	controlIdsInProfile := oscal.ListControlIds(resolvedProfile)
 	controlIdsInCD := []string{}
 	for _, category := range intCompliance.Standard.Categories {
 		for _, control := range category.Controls {
@@ -0,0 +1,39 @@
+# Copyright 2023 IBM Corporation
+
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+
+# http://www.apache.org/licenses/LICENSE-2.0
+
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+#!/bin/bash
+
+# Vendor the JSON schemas released by NIST. They are embedded as they are.
+# Usage: hack/update-oscal-schemas.sh [OSCAL version (default: 1.1.2)]
+
+set -e
+
+VERSION=${1:-1.1.2}
+SCHEMA_DIR=$(cd $(dirname $0)/..; pwd)/pkg/oscal/validation/schemas
+
+RELEASE_URL=https://github.com/usnistgov/OSCAL/releases/download/v${VERSION}
+
+for model in catalog profile component assessment-plan assessment-results poam
+do
+  curl -sSfL -o ${SCHEMA_DIR}/oscal_${model}_schema.json ${RELEASE_URL}/oscal_${model}_schema.json
+done
+
+# Vendor the schemas referred to by the schemas of the models (e.g. a common schema) from the same release
+for ref in $(cat ${SCHEMA_DIR}/oscal_*_schema.json | grep -o '"\$ref" *: *"[^#"][^"]*"' | sed -e 's/.*"\([^"]*\)"$/\1/' -e 's/#.*//' | sort -u)
+do
+  file=$(basename ${ref})
+  if [ ! -f ${SCHEMA_DIR}/${file} ]; then
+    curl -sSfL -o ${SCHEMA_DIR}/${file} ${RELEASE_URL}/${file}
+  fi
+done
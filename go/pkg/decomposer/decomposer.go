@@ -20,6 +20,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 
 	"github.com/oscal-compass/compliance-to-policy/go/pkg"
 	"github.com/oscal-compass/compliance-to-policy/go/pkg/tables/resources"
@@ -51,7 +52,8 @@ func (d *Decomposer) Decompose() (string, error) {
 	filenameCreator := pkg.NewFilenameCreator("", &pkg.FilenameCreatorOption{
 		UnlabelToZero: true,
 	})
-	for policy, table := range groupedByPolicy {
+	for _, policy := range sortedKeys(groupedByPolicy) {
+		table := groupedByPolicy[policy]
 		policyFilename := filenameCreator.Get(policy)
 		policyDir, err := pkg.MakeDir(policyResourcesDir + "/" + policyFilename)
 		if err != nil {
@@ -111,18 +113,15 @@ func GroupByComplianceInHierarchy(resourceTable *resources.Table) []Compliance {
 		category := compliance.Category
 		control := compliance.Control
 
-		policies := []string{}
-		for policy := range table.GroupBy("policy") {
-			policies = append(policies, policy)
-		}
+		policies := sortedKeys(table.GroupBy("policy"))
 		mapToHierarchy(standards, standard, category, control, policies)
 	}
 	compliances := []Compliance{}
-	for x1 := range standards {
+	for _, x1 := range sortedKeys(standards) {
 		categories := []Category{}
-		for x2 := range standards[x1] {
+		for _, x2 := range sortedKeys(standards[x1]) {
 			controls := []Control{}
-			for x3 := range standards[x1][x2] {
+			for _, x3 := range sortedKeys(standards[x1][x2]) {
 				control := Control{
 					Name:        x3,
 					ControlRefs: standards[x1][x2][x3],
@@ -179,3 +178,13 @@ func GroupByCompliance(table *resources.Table) map[policycomposition.Compliance]
 	}
 	return groupedByPolicyByCompliance
 }
+
+// sortedKeys returns the keys of the map in order so that the outputs don't depend on the map iteration order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := []string{}
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
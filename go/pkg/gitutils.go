@@ -17,7 +17,6 @@ limitations under the License.
 package pkg
 
 import (
-	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -27,6 +26,8 @@ import (
 
 	"github.com/go-git/go-git/v5"
 	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal/format"
 )
 
 type GitUtils struct {
@@ -66,9 +67,9 @@ func (g *GitUtils) LoadFromWeb(url string, out interface{}) error {
 		return fmt.Errorf("Failed to serialize body %s", url)
 	}
 
-	err = json.Unmarshal(byteArray, out)
+	err = UnmarshalOscal(byteArray, format.Detect(u.Path, byteArray), out)
 	if err != nil {
-		return fmt.Errorf("Failed to unmarshal %s", url)
+		return fmt.Errorf("Failed to unmarshal %s: %w", url, err)
 	}
 	return nil
 }
@@ -89,16 +90,16 @@ func (g *GitUtils) LoadFromGit(url string, out interface{}) error {
 	if err != nil {
 		return fmt.Errorf("Failed to clone %s", repoUrl)
 	}
-	if err := LoadJsonFileToObject(repoDir+"/"+path, out); err != nil {
-		return fmt.Errorf("Failed to marshal %s", repoDir+path)
+	if err := LoadOscalFileToObject(repoDir+"/"+path, out); err != nil {
+		return fmt.Errorf("Failed to marshal %s: %w", repoDir+path, err)
 	}
 	return nil
 }
 
 func loadFromLocalFs(u *neturl.URL, out interface{}) error {
 	path := toLocalPath(u)
-	if err := LoadJsonFileToObject(path, out); err != nil {
-		return fmt.Errorf("Failed to marshal %s in local directory", path)
+	if err := LoadOscalFileToObject(path, out); err != nil {
+		return fmt.Errorf("Failed to marshal %s in local directory: %w", path, err)
 	}
 	return nil
 }
@@ -0,0 +1,87 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kyverno
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal"
+	typec2pcr "github.com/oscal-compass/compliance-to-policy/go/pkg/types/c2pcr"
+	typeap "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/assessmentplan"
+	typecommon "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/common"
+)
+
+// GenerateAssessmentPlan returns the assessment plan of the Kyverno policies of the C2P config.
+// The subjects are the namespaces of the Policies, and the whole cluster if any of the policies is a ClusterPolicy.
+func GenerateAssessmentPlan(c2pParsed typec2pcr.C2PCRParsed, cdHref string, stamper oscal.Stamper) (*typeap.AssessmentPlanRoot, error) {
+	policyNames := []string{}
+	for _, componentObject := range c2pParsed.ComponentObjects {
+		if componentObject.ComponentType == "validation" {
+			continue
+		}
+		for _, ruleObject := range componentObject.RuleObjects {
+			for _, policyName := range ruleObject.PolicyIds(true) {
+				if !slices.Contains(policyNames, policyName) {
+					policyNames = append(policyNames, policyName)
+				}
+			}
+		}
+	}
+	slices.Sort(policyNames)
+
+	clusterWide := false
+	namespaces := []string{}
+	for _, policyName := range policyNames {
+		fileLoader := NewFileLoader()
+		if err := fileLoader.LoadFromDirectory(fmt.Sprintf("%s/%s", c2pParsed.PolicyResoureDir, policyName)); err != nil {
+			return nil, err
+		}
+		for _, pri := range fileLoader.GetPolicyResourceIndice() {
+			if pri.Kind == "ClusterPolicy" {
+				clusterWide = true
+			} else if !slices.Contains(namespaces, pri.Namespace) {
+				namespaces = append(namespaces, pri.Namespace)
+			}
+		}
+	}
+	slices.Sort(namespaces)
+
+	subjects := []typeap.AssessmentSubject{}
+	if clusterWide {
+		subjects = append(subjects, typeap.AssessmentSubject{
+			Type:        "inventory-item",
+			Description: "Kubernetes resources matched by the Kyverno ClusterPolicies",
+			IncludeAll:  &typeap.IncludeAll{},
+		})
+	}
+	for _, namespace := range namespaces {
+		subjects = append(subjects, typeap.AssessmentSubject{
+			Type:        "inventory-item",
+			Description: fmt.Sprintf("Kubernetes resources in the namespace %s matched by the Kyverno Policies", namespace),
+			Props:       []typecommon.Prop{{Name: "namespace", Value: namespace}},
+		})
+	}
+
+	task := oscal.AssessmentPlanTask{
+		PVP:         "kyverno",
+		Description: "Kyverno policies validate the Kubernetes resources and report the results in the policy reports",
+		PolicyIds:   policyNames,
+		Subjects:    subjects,
+	}
+	return oscal.GenerateAssessmentPlan(c2pParsed.ComponentDefinition, c2pParsed.ComponentObjects, cdHref, []oscal.AssessmentPlanTask{task}, stamper)
+}
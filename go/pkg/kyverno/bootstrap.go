@@ -0,0 +1,154 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kyverno
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal"
+	cd "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/componentdefinition"
+)
+
+// ControlMapping maps the Kyverno policies to the controls by their annotations
+type ControlMapping struct {
+	// Control IDs per policies.kyverno.io/category. Categories that are control IDs (e.g. AC-6(1)) are mapped to the controls as is.
+	Categories map[string][]string `json:"categories,omitempty"`
+	// Annotations whose values are comma-separated control IDs (e.g. "AC-6, CM-7(2)")
+	Annotations []string `json:"annotations,omitempty"`
+}
+
+// BootstrapOptions configures the component-definition bootstrapped from the Kyverno policies
+type BootstrapOptions struct {
+	// Title of the component-definition
+	Title string
+	// Title, type and description of the component validated by the policies
+	ComponentTitle       string
+	ComponentType        string
+	ComponentDescription string
+	// Source (catalog or profile) of the control implementation
+	Source string
+}
+
+// BootstrapResult is the component-definition bootstrapped from the Kyverno policies
+type BootstrapResult struct {
+	ComponentDefinition *cd.ComponentDefinitionRoot
+	// Policies whose controls are not found by the mapping to be reviewed by humans
+	UnmappedPolicies []PolicyResourceIndex
+}
+
+var controlIdRegexp = regexp.MustCompile(`^([A-Za-z]{2,3})-(\d+)(?:\((\d+)\)|\.(\d+))?$`)
+
+// normalizeControlId converts a control ID such as AC-6(1) or AC-6.1 into the OSCAL form ac-6.1. It returns false if the text is not a control ID.
+func normalizeControlId(text string) (string, bool) {
+	matches := controlIdRegexp.FindStringSubmatch(strings.TrimSpace(text))
+	if matches == nil {
+		return "", false
+	}
+	controlId := fmt.Sprintf("%s-%s", strings.ToLower(matches[1]), matches[2])
+	if enhancement := matches[3] + matches[4]; enhancement != "" {
+		controlId = fmt.Sprintf("%s.%s", controlId, enhancement)
+	}
+	return controlId, true
+}
+
+// ControlsOfPolicy returns the control IDs mapped to the policy by the category and the annotations
+func (m ControlMapping) ControlsOfPolicy(pri PolicyResourceIndex) []string {
+	controlIds := []string{}
+	add := func(ids ...string) {
+		for _, id := range ids {
+			if controlId, ok := normalizeControlId(id); ok && !slices.Contains(controlIds, controlId) {
+				controlIds = append(controlIds, controlId)
+			}
+		}
+	}
+	for _, category := range strings.Split(pri.Category, ",") {
+		category = strings.TrimSpace(category)
+		if category == "" {
+			continue
+		}
+		add(m.Categories[category]...)
+		add(category)
+	}
+	for _, annotation := range m.Annotations {
+		if value, ok := pri.Annotations[annotation]; ok {
+			add(strings.Split(value, ",")...)
+		}
+	}
+	sort.Strings(controlIds)
+	return controlIds
+}
+
+// BootstrapComponentDefinition makes a starter component-definition with a rule for each policy (Rule_Id, Rule_Description and Policy_Id)
+// and the implemented requirements of the controls mapped to the policies.
+// Policy_Id is the directory of the policy, from which oscal2policy copies the policy.
+// Rule_Description is the policies.kyverno.io/description annotation of the policy (the title if it has no description).
+func BootstrapComponentDefinition(policies []PolicyResourceIndex, mapping ControlMapping, options BootstrapOptions, stamper oscal.Stamper) (*BootstrapResult, error) {
+	unique := []PolicyResourceIndex{}
+	for _, pri := range policies {
+		duplicated := false
+		for _, u := range unique {
+			if u.Name == pri.Name {
+				duplicated = true
+				break
+			}
+		}
+		if !duplicated {
+			unique = append(unique, pri)
+		}
+	}
+	sort.Slice(unique, func(i, j int) bool { return unique[i].Name < unique[j].Name })
+
+	result := BootstrapResult{UnmappedPolicies: []PolicyResourceIndex{}}
+	rows := []oscal.TrestleCsvRow{}
+	for _, pri := range unique {
+		description := oscal.ToPropValue(pri.Description)
+		if description == "" {
+			description = oscal.ToPropValue(pri.Title)
+		}
+		row := oscal.TrestleCsvRow{
+			TrestleComponentProps: oscal.TrestleComponentProps{
+				ComponentTitle:       options.ComponentTitle,
+				ComponentDescription: options.ComponentDescription,
+				ComponentType:        options.ComponentType,
+			},
+			RuleId:          pri.Name,
+			RuleDescription: description,
+			Namespace:       oscal.OscaleNamespace,
+			UserColumns:     map[string]string{"Policy_Id": filepath.Base(filepath.Dir(pri.SrcPath))},
+		}
+		controlIds := mapping.ControlsOfPolicy(pri)
+		if len(controlIds) == 0 {
+			result.UnmappedPolicies = append(result.UnmappedPolicies, pri)
+		} else {
+			row.ControlIdList = controlIds
+			row.ProfileSource = options.Source
+			row.ProfileDescription = fmt.Sprintf("Controls implemented by Kyverno policies for %s", options.ComponentTitle)
+		}
+		rows = append(rows, row)
+	}
+	cdRoot, err := oscal.MakeComponentDefinitionFromTrestleCsv(rows, options.Title, stamper)
+	if err != nil {
+		return nil, err
+	}
+	result.ComponentDefinition = cdRoot
+	return &result, nil
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kyverno
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/oscal-compass/compliance-to-policy/go/pkg"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal"
+	typec2pcr "github.com/oscal-compass/compliance-to-policy/go/pkg/types/c2pcr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeControlId(t *testing.T) {
+	for text, expected := range map[string]string{"AC-6(1)": "ac-6.1", " cm-7 ": "cm-7", "AC-6.10": "ac-6.10"} {
+		actual, ok := normalizeControlId(text)
+		assert.True(t, ok, text)
+		assert.Equal(t, expected, actual, text)
+	}
+	_, ok := normalizeControlId("Best Practices")
+	assert.False(t, ok)
+}
+
+func TestBootstrapComponentDefinition(t *testing.T) {
+	fl := NewFileLoader()
+	err := fl.LoadFromDirectory(pkg.PathFromPkgDirectory("./testdata/kyverno/bootstrap/policies"))
+	assert.NoError(t, err)
+
+	var mapping ControlMapping
+	err = pkg.LoadYamlFileToObject(pkg.PathFromPkgDirectory("./testdata/kyverno/bootstrap/mapping.yaml"), &mapping)
+	assert.NoError(t, err)
+
+	options := BootstrapOptions{
+		Title:          "Kyverno Policies",
+		ComponentTitle: "Kubernetes",
+		ComponentType:  "Service",
+		Source:         "https://example.com/catalog.json",
+	}
+	result, err := BootstrapComponentDefinition(fl.GetPolicyResourceIndice(), mapping, options, oscal.NewStamper(true))
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, len(result.UnmappedPolicies))
+	assert.Equal(t, "require-labels", result.UnmappedPolicies[0].Name)
+
+	cdRoot := result.ComponentDefinition
+	err = pkg.WriteOscalObjToFile(pkg.PathFromPkgDirectory("./testdata/_test/bootstrapped-component-definition.json"), cdRoot, "json")
+	assert.NoError(t, err, "bootstrapped component-definition is valid")
+	parsed := oscal.ParseComponentDefinition(*cdRoot)
+	assert.Equal(t, 1, len(parsed))
+	component := parsed[0]
+	assert.Equal(t, 3, len(component.RuleObjects))
+	for _, ruleObject := range component.RuleObjects {
+		if ruleObject.RuleId == "disallow-host-namespaces" {
+			assert.Equal(t, "Host namespaces (Process ID namespace, Inter-Process Communication namespace, and network namespace) allow access to shared information and can be used to elevate privileges. Pods should not be allowed access to host namespaces.", ruleObject.RuleDescription)
+		}
+		if ruleObject.RuleId == "restrict-sa-token" {
+			// Title is the description of the rule if the policy has no description
+			assert.Equal(t, "Restrict Auto-Mount of Service Account Tokens", ruleObject.RuleDescription)
+		}
+	}
+
+	rulesOfControls := map[string][]string{}
+	for _, ir := range cdRoot.ComponentDefinition.Components[0].ControlImplementations[0].ImplementedRequirements {
+		for _, prop := range ir.Props {
+			rulesOfControls[ir.ControlID] = append(rulesOfControls[ir.ControlID], prop.Value)
+		}
+	}
+	assert.Equal(t, map[string][]string{
+		"ac-6.10": {"disallow-host-namespaces"},
+		"cm-7":    {"disallow-host-namespaces"},
+		"ac-6.1":  {"restrict-sa-token"},
+		"ia-5":    {"restrict-sa-token"},
+	}, rulesOfControls)
+
+	policyIds := []string{}
+	for _, prop := range cdRoot.ComponentDefinition.Components[0].Props {
+		if prop.Name == "Policy_Id" {
+			policyIds = append(policyIds, prop.Value)
+		}
+	}
+	// Policy_Id is the directory of the policy, which differs from the name of restrict-sa-token
+	assert.Equal(t, []string{"disallow-host-namespaces", "require-labels", "restrict-automount-sa-token"}, policyIds)
+
+	// The bootstrapped component-definition composes the policies from the directory of the policies
+	tempDirPath := pkg.PathFromPkgDirectory("./testdata/_test")
+	err = os.MkdirAll(tempDirPath, os.ModePerm)
+	assert.NoError(t, err, "Should not happen")
+	tempDir := pkg.NewTempDirectory(tempDirPath)
+	cdPath := filepath.Join(tempDir.GetTempDir(), "component-definition.json")
+	err = pkg.WriteObjToJsonFile(cdPath, cdRoot)
+	assert.NoError(t, err, "Should not happen")
+
+	c2pcrSpec := typec2pcr.Spec{
+		Compliance: typec2pcr.Compliance{
+			Name:                "Test Compliance",
+			ComponentDefinition: typec2pcr.ResourceRef{Url: cdPath},
+		},
+		PolicyResources: typec2pcr.ResourceRef{Url: pkg.PathFromPkgDirectory("./testdata/kyverno/bootstrap/policies")},
+	}
+	c2pcrParser := NewParser(pkg.NewGitUtils(tempDir))
+	c2pcrParsed, err := c2pcrParser.Parse(c2pcrSpec)
+	assert.NoError(t, err, "Should not happen")
+	policiesDir := pkg.NewTempDirectory(tempDirPath)
+	err = NewOscal2Policy(c2pcrParsed.PolicyResoureDir, policiesDir).Generate(c2pcrParsed)
+	assert.NoError(t, err, "Should not happen")
+	_, err = os.Stat(filepath.Join(policiesDir.GetTempDir(), "restrict-automount-sa-token", "restrict-automount-sa-token.yaml"))
+	assert.NoError(t, err, "restrict-sa-token is composed from its directory")
+}
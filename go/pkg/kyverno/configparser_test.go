@@ -0,0 +1,55 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kyverno
+
+import (
+	"os"
+	"testing"
+
+	"github.com/oscal-compass/compliance-to-policy/go/pkg"
+	typec2pcr "github.com/oscal-compass/compliance-to-policy/go/pkg/types/c2pcr"
+	"github.com/stretchr/testify/assert"
+)
+
+// The c2p config of the docs refers to the files by the paths relative to the go directory
+func TestParseDocsC2PConfig(t *testing.T) {
+	tempDirPath := pkg.PathFromPkgDirectory("./testdata/_test")
+	err := os.MkdirAll(tempDirPath, os.ModePerm)
+	assert.NoError(t, err, "Should not happen")
+
+	wd, err := os.Getwd()
+	assert.NoError(t, err, "Should not happen")
+	defer func() { _ = os.Chdir(wd) }()
+	pkg.ChdirFromPkgDirectory("..")
+
+	var c2pcrSpec typec2pcr.Spec
+	err = pkg.LoadYamlFileToObject("./docs/ocm/c2p-config.yaml", &c2pcrSpec)
+	assert.NoError(t, err, "Should not happen")
+
+	c2pcrParser := NewParser(pkg.NewGitUtils(pkg.NewTempDirectory(tempDirPath)))
+	c2pcrParsed, err := c2pcrParser.Parse(c2pcrSpec)
+	assert.NoError(t, err, "Should not happen")
+
+	controlIds := []string{}
+	for _, group := range c2pcrParsed.ResolvedProfile.Catalog.Groups {
+		for _, control := range group.Controls {
+			controlIds = append(controlIds, control.ID)
+		}
+	}
+	assert.Equal(t, []string{"ac-6", "cm-2", "cm-6"}, controlIds)
+	assert.NotEmpty(t, c2pcrParsed.ComponentObjects)
+}
@@ -35,6 +35,17 @@ type PolicyResourceIndex struct {
 	Namespace  string `json:"namespace,omitempty"`
 	SrcPath    string `json:"srcPath,omitempty"`
 	HasContext bool   `json:"hasContext,omitempty"`
+	// policies.kyverno.io/title, policies.kyverno.io/description and policies.kyverno.io/category annotations of the policy
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Category    string `json:"category,omitempty"`
+	// All annotations of the policy
+	Annotations map[string]string `json:"-"`
+}
+
+// IsNamespaced returns true if the policy is a namespaced Policy
+func (pri PolicyResourceIndex) IsNamespaced() bool {
+	return pri.Kind == "Policy"
 }
 
 type FileLoader struct {
@@ -93,12 +104,17 @@ func (fl *FileLoader) LoadFromDirectory(dir string) error {
 func (fl *FileLoader) mapLoadedObject(unstObj *unstructured.Unstructured, path string) *PolicyResourceIndex {
 	kind, apiVersion, name := unstObj.GetKind(), unstObj.GetAPIVersion(), unstObj.GetName()
 	fl.logger.Info(fmt.Sprintf("load yaml %s: %s/%s/%s", path, kind, apiVersion, name))
+	annotations := unstObj.GetAnnotations()
 	return &PolicyResourceIndex{
-		ApiVersion: unstObj.GetAPIVersion(),
-		Kind:       unstObj.GetKind(),
-		Name:       name,
-		Namespace:  unstObj.GetNamespace(),
-		SrcPath:    path,
+		ApiVersion:  unstObj.GetAPIVersion(),
+		Kind:        unstObj.GetKind(),
+		Name:        name,
+		Namespace:   unstObj.GetNamespace(),
+		SrcPath:     path,
+		Title:       annotations["policies.kyverno.io/title"],
+		Description: annotations["policies.kyverno.io/description"],
+		Category:    annotations["policies.kyverno.io/category"],
+		Annotations: annotations,
 	}
 }
 
@@ -20,6 +20,7 @@ import (
 	"fmt"
 
 	"github.com/oscal-compass/compliance-to-policy/go/pkg"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal"
 	typec2pcr "github.com/oscal-compass/compliance-to-policy/go/pkg/types/c2pcr"
 	cp "github.com/otiai10/copy"
 	"go.uber.org/zap"
@@ -40,17 +41,21 @@ func NewOscal2Policy(policiesDir string, tempDir pkg.TempDirectory) *Oscal2Polic
 }
 
 func (c *Oscal2Policy) Generate(c2pParsed typec2pcr.C2PCRParsed) error {
-	for _, componentObject := range c2pParsed.ComponentObjects {
-		if componentObject.ComponentType == "validation" {
-			continue
+	policyObjects, err := oscal.PolicyObjects(c2pParsed.ComponentObjects)
+	if err != nil {
+		return err
+	}
+	for _, policyObject := range policyObjects {
+		sourceDir := fmt.Sprintf("%s/%s", c.policiesDir, policyObject.PolicyId)
+		destDir := fmt.Sprintf("%s/%s", c.tempDir.GetTempDir(), policyObject.PolicyId)
+		if err := cp.Copy(sourceDir, destDir); err != nil {
+			return err
+		}
+		if err := applyParameters(destDir, policyObject); err != nil {
+			return err
 		}
-		for _, ruleObject := range componentObject.RuleObjects {
-			sourceDir := fmt.Sprintf("%s/%s", c.policiesDir, ruleObject.RuleId)
-			destDir := fmt.Sprintf("%s/%s", c.tempDir.GetTempDir(), ruleObject.RuleId)
-			err := cp.Copy(sourceDir, destDir)
-			if err != nil {
-				return err
-			}
+		if err := applyScope(destDir, policyObject.PolicyId, policyObject.Scope); err != nil {
+			return err
 		}
 	}
 	return nil
@@ -17,11 +17,14 @@ limitations under the License.
 package kyverno
 
 import (
+	"fmt"
 	"os"
 	"testing"
 
 	"github.com/oscal-compass/compliance-to-policy/go/pkg"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal"
 	typec2pcr "github.com/oscal-compass/compliance-to-policy/go/pkg/types/c2pcr"
+	cd "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/componentdefinition"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -75,3 +78,236 @@ func TestOscal2Policy(t *testing.T) {
 	err = o2p.Generate(c2pcrParsed)
 	assert.NoError(t, err, "Should not happen")
 }
+
+func TestOscal2PolicyWithParameters(t *testing.T) {
+	policyDir := pkg.PathFromPkgDirectory("./testdata/kyverno/parameterized/policy-resources")
+	cdPath := pkg.PathFromPkgDirectory("./testdata/kyverno/parameterized/component-definition.json")
+
+	tempDirPath := pkg.PathFromPkgDirectory("./testdata/_test")
+	err := os.MkdirAll(tempDirPath, os.ModePerm)
+	assert.NoError(t, err, "Should not happen")
+
+	c2pcrSpec := typec2pcr.Spec{
+		Compliance: typec2pcr.Compliance{
+			Name: "Test Compliance",
+			ComponentDefinition: typec2pcr.ResourceRef{
+				Url: cdPath,
+			},
+		},
+		PolicyResources: typec2pcr.ResourceRef{
+			Url: policyDir,
+		},
+	}
+	c2pcrParser := NewParser(pkg.NewGitUtils(pkg.NewTempDirectory(tempDirPath)))
+	c2pcrParsed, err := c2pcrParser.Parse(c2pcrSpec)
+	assert.NoError(t, err, "Should not happen")
+
+	tempDir := pkg.NewTempDirectory(tempDirPath)
+	o2p := NewOscal2Policy(c2pcrParsed.PolicyResoureDir, tempDir)
+	err = o2p.Generate(c2pcrParsed)
+	assert.NoError(t, err, "Should not happen")
+
+	var policy map[string]interface{}
+	err = pkg.LoadYamlFileToObject(tempDir.GetTempDir()+"/require-minimum-replicas/require-minimum-replicas.yaml", &policy)
+	assert.NoError(t, err, "Should not happen")
+	rule := policy["spec"].(map[string]interface{})["rules"].([]interface{})[0].(map[string]interface{})
+	validate := rule["validate"].(map[string]interface{})
+	assert.Equal(t, "Deployments must have at least 3 replicas.", validate["message"])
+	condition := validate["deny"].(map[string]interface{})["conditions"].(map[string]interface{})["any"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "3", condition["value"])
+	assert.Equal(t, "{{ request.object.spec.replicas }}", condition["key"])
+
+	unset := c2pcrParsed
+	unset.ComponentObjects = []oscal.ComponentObject{c2pcrParsed.ComponentObjects[0]}
+	unset.ComponentObjects[0].ControlImpleObjects = []oscal.ControlImpleObject{c2pcrParsed.ComponentObjects[0].ControlImpleObjects[0]}
+	unset.ComponentObjects[0].ControlImpleObjects[0].SetParameters = nil
+	err = NewOscal2Policy(c2pcrParsed.PolicyResoureDir, pkg.NewTempDirectory(tempDirPath)).Generate(unset)
+	assert.ErrorContains(t, err, "parameter minimum_replicas")
+	assert.ErrorContains(t, err, "is not set for rule require-minimum-replicas")
+
+	unknown := c2pcrParsed
+	unknown.ComponentObjects = []oscal.ComponentObject{c2pcrParsed.ComponentObjects[0]}
+	unknown.ComponentObjects[0].RuleObjects = []oscal.RuleObject{c2pcrParsed.ComponentObjects[0].RuleObjects[0]}
+	unknown.ComponentObjects[0].RuleObjects[0].ParameterId = "maximum_replicas"
+	unknown.ComponentObjects[0].ControlImpleObjects = []oscal.ControlImpleObject{c2pcrParsed.ComponentObjects[0].ControlImpleObjects[0]}
+	unknown.ComponentObjects[0].ControlImpleObjects[0].SetParameters = []cd.SetParameter{{ParamID: "maximum_replicas", Values: []string{"5"}}}
+	err = NewOscal2Policy(c2pcrParsed.PolicyResoureDir, pkg.NewTempDirectory(tempDirPath)).Generate(unknown)
+	assert.ErrorContains(t, err, "unknown parameter minimum_replicas")
+}
+
+func TestOscal2PolicyWithParametersOfRulesOfSamePolicy(t *testing.T) {
+	tempDirPath := pkg.PathFromPkgDirectory("./testdata/_test")
+	err := os.MkdirAll(tempDirPath, os.ModePerm)
+	assert.NoError(t, err, "Should not happen")
+
+	c2pcrParsed := parseTestC2PCR(t, "./testdata/kyverno/parameterized/component-definition.json", "./testdata/kyverno/parameterized/policy-resources")
+	// Two rules with different parameters referring to limit-replicas
+	c2pcrParsed.ComponentObjects = []oscal.ComponentObject{{
+		ComponentTitle: "Kubernetes",
+		ComponentType:  "service",
+		RuleObjects: []oscal.RuleObject{
+			{RuleId: "require-minimum-replicas", PolicyId: "limit-replicas", ParameterId: "minimum_replicas"},
+			{RuleId: "limit-maximum-replicas", PolicyId: "limit-replicas", ParameterId: "maximum_replicas"},
+		},
+		ControlImpleObjects: []oscal.ControlImpleObject{{
+			SetParameters: []cd.SetParameter{
+				{ParamID: "minimum_replicas", Values: []string{"3"}},
+				{ParamID: "maximum_replicas", Values: []string{"10"}},
+			},
+			ControlObjects: []oscal.ControlObject{{ControlId: "cp-10", RuleIds: []string{"require-minimum-replicas", "limit-maximum-replicas"}}},
+		}},
+	}}
+	tempDir := pkg.NewTempDirectory(tempDirPath)
+	err = NewOscal2Policy(c2pcrParsed.PolicyResoureDir, tempDir).Generate(c2pcrParsed)
+	assert.NoError(t, err, "Should not happen")
+
+	var policy map[string]interface{}
+	err = pkg.LoadYamlFileToObject(tempDir.GetTempDir()+"/limit-replicas/limit-replicas.yaml", &policy)
+	assert.NoError(t, err, "Should not happen")
+	values := []interface{}{}
+	for _, rule := range policy["spec"].(map[string]interface{})["rules"].([]interface{}) {
+		validate := rule.(map[string]interface{})["validate"].(map[string]interface{})
+		condition := validate["deny"].(map[string]interface{})["conditions"].(map[string]interface{})["any"].([]interface{})[0].(map[string]interface{})
+		values = append(values, condition["value"])
+	}
+	assert.Equal(t, []interface{}{"3", "10"}, values)
+}
+
+func TestOscal2PolicyWithValidationComponent(t *testing.T) {
+	policyDir := pkg.PathFromPkgDirectory("./testdata/kyverno/policy-resources")
+	cdPath := pkg.PathFromPkgDirectory("./testdata/kyverno/validation-component/component-definition.json")
+
+	tempDirPath := pkg.PathFromPkgDirectory("./testdata/_test")
+	err := os.MkdirAll(tempDirPath, os.ModePerm)
+	assert.NoError(t, err, "Should not happen")
+
+	c2pcrSpec := typec2pcr.Spec{
+		Compliance: typec2pcr.Compliance{
+			Name: "Test Compliance",
+			ComponentDefinition: typec2pcr.ResourceRef{
+				Url: cdPath,
+			},
+		},
+		PolicyResources: typec2pcr.ResourceRef{
+			Url: policyDir,
+		},
+	}
+	c2pcrParser := NewParser(pkg.NewGitUtils(pkg.NewTempDirectory(tempDirPath)))
+	c2pcrParsed, err := c2pcrParser.Parse(c2pcrSpec)
+	assert.NoError(t, err, "Should not happen")
+
+	// The rule of the target component is linked to the Kyverno policy by the Check_Id of the validation component
+	rule := c2pcrParsed.ComponentObjects[0].RuleObjects[0]
+	assert.Equal(t, "supply-chain-base-images", rule.RuleId)
+	assert.Equal(t, []string{"allowed-base-images"}, rule.CheckIds())
+
+	tempDir := pkg.NewTempDirectory(tempDirPath)
+	o2p := NewOscal2Policy(c2pcrParsed.PolicyResoureDir, tempDir)
+	err = o2p.Generate(c2pcrParsed)
+	assert.NoError(t, err, "Should not happen")
+	assert.DirExists(t, tempDir.GetTempDir()+"/allowed-base-images")
+	assert.NoDirExists(t, tempDir.GetTempDir()+"/supply-chain-base-images")
+}
+
+func TestOscal2PolicyWithScope(t *testing.T) {
+	tempDirPath := pkg.PathFromPkgDirectory("./testdata/_test")
+	err := os.MkdirAll(tempDirPath, os.ModePerm)
+	assert.NoError(t, err, "Should not happen")
+
+	c2pcrParsed := parseTestC2PCR(t, "./testdata/kyverno/scoped/component-definition.json", "./testdata/kyverno/scoped/policy-resources")
+	tempDir := pkg.NewTempDirectory(tempDirPath)
+	o2p := NewOscal2Policy(c2pcrParsed.PolicyResoureDir, tempDir)
+	err = o2p.Generate(c2pcrParsed)
+	assert.NoError(t, err, "Should not happen")
+
+	loadPolicy := func(name string) map[string]interface{} {
+		var policy map[string]interface{}
+		err := pkg.LoadYamlFileToObject(fmt.Sprintf("%s/%s/%s.yaml", tempDir.GetTempDir(), name, name), &policy)
+		assert.NoError(t, err, "Should not happen")
+		return policy
+	}
+
+	// Enforce of the library is overridden by audit of the control implementation
+	policy := loadPolicy("disallow-latest-tag")
+	spec := policy["spec"].(map[string]interface{})
+	assert.Equal(t, "Audit", spec["validationFailureAction"])
+	rule := spec["rules"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{
+		"any": []interface{}{map[string]interface{}{"resources": map[string]interface{}{"kinds": []interface{}{"Pod"}, "namespaces": []interface{}{"app", "web"}}}},
+	}, rule["match"])
+	assert.Equal(t, map[string]interface{}{"resources": map[string]interface{}{"namespaces": []interface{}{"kube-system"}}}, rule["exclude"])
+
+	// Props of the rule take precedence over those of the control implementation
+	policy = loadPolicy("require-labels")
+	spec = policy["spec"].(map[string]interface{})
+	assert.Equal(t, "Enforce", spec["validationFailureAction"])
+	rule = spec["rules"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{
+		"resources": map[string]interface{}{"kinds": []interface{}{"Pod"}, "namespaces": []interface{}{"app", "web"}},
+	}, rule["match"])
+	assert.Equal(t, map[string]interface{}{
+		"any": []interface{}{map[string]interface{}{"resources": map[string]interface{}{"namespaces": []interface{}{"kube-system", "kube-public"}}}},
+	}, rule["exclude"])
+
+	invalid := c2pcrParsed
+	invalid.ComponentObjects = []oscal.ComponentObject{c2pcrParsed.ComponentObjects[0]}
+	invalid.ComponentObjects[0].RuleObjects = []oscal.RuleObject{c2pcrParsed.ComponentObjects[0].RuleObjects[1]}
+	invalid.ComponentObjects[0].RuleObjects[0].Scope.EnforcementMode = "warn"
+	err = NewOscal2Policy(c2pcrParsed.PolicyResoureDir, pkg.NewTempDirectory(tempDirPath)).Generate(invalid)
+	assert.ErrorContains(t, err, "invalid Enforcement_Mode 'warn' of rule require-labels")
+}
+
+func TestOscal2PolicyWithScopesOfRulesOfSamePolicy(t *testing.T) {
+	tempDirPath := pkg.PathFromPkgDirectory("./testdata/_test")
+	err := os.MkdirAll(tempDirPath, os.ModePerm)
+	assert.NoError(t, err, "Should not happen")
+
+	c2pcrParsed := parseTestC2PCR(t, "./testdata/kyverno/scoped/component-definition.json", "./testdata/kyverno/scoped/policy-resources")
+	// A second rule referring to disallow-latest-tag, whose scope is merged with that of the first rule
+	componentObject := c2pcrParsed.ComponentObjects[0]
+	componentObject.RuleObjects = append([]oscal.RuleObject{}, componentObject.RuleObjects...)
+	componentObject.RuleObjects = append(componentObject.RuleObjects, oscal.RuleObject{
+		RuleId:   "disallow-latest-tag-in-production",
+		PolicyId: "disallow-latest-tag",
+		Scope: oscal.PolicyScope{
+			EnforcementMode:    "enforce",
+			TargetNamespaces:   []string{"production"},
+			ExcludedNamespaces: []string{"kube-public"},
+		},
+	})
+	c2pcrParsed.ComponentObjects = []oscal.ComponentObject{componentObject}
+	tempDir := pkg.NewTempDirectory(tempDirPath)
+	err = NewOscal2Policy(c2pcrParsed.PolicyResoureDir, tempDir).Generate(c2pcrParsed)
+	assert.NoError(t, err, "Should not happen")
+
+	var policy map[string]interface{}
+	err = pkg.LoadYamlFileToObject(tempDir.GetTempDir()+"/disallow-latest-tag/disallow-latest-tag.yaml", &policy)
+	assert.NoError(t, err, "Should not happen")
+	spec := policy["spec"].(map[string]interface{})
+	// Enforce of the second rule takes precedence over audit of the first rule and the target namespaces are merged.
+	// kube-public excluded only by the second rule is not excluded from the policy.
+	assert.Equal(t, "Enforce", spec["validationFailureAction"])
+	rule := spec["rules"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{
+		"any": []interface{}{map[string]interface{}{"resources": map[string]interface{}{"kinds": []interface{}{"Pod"}, "namespaces": []interface{}{"app", "web", "production"}}}},
+	}, rule["match"])
+	assert.Equal(t, map[string]interface{}{"resources": map[string]interface{}{"namespaces": []interface{}{"kube-system"}}}, rule["exclude"])
+
+	// A rule without scope keeps the policy applied to all the namespaces
+	componentObject.RuleObjects = append(componentObject.RuleObjects, oscal.RuleObject{
+		RuleId:   "disallow-latest-tag-everywhere",
+		PolicyId: "disallow-latest-tag",
+	})
+	c2pcrParsed.ComponentObjects = []oscal.ComponentObject{componentObject}
+	tempDir = pkg.NewTempDirectory(tempDirPath)
+	err = NewOscal2Policy(c2pcrParsed.PolicyResoureDir, tempDir).Generate(c2pcrParsed)
+	assert.NoError(t, err, "Should not happen")
+	err = pkg.LoadYamlFileToObject(tempDir.GetTempDir()+"/disallow-latest-tag/disallow-latest-tag.yaml", &policy)
+	assert.NoError(t, err, "Should not happen")
+	spec = policy["spec"].(map[string]interface{})
+	assert.Equal(t, "Enforce", spec["validationFailureAction"])
+	rule = spec["rules"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{
+		"any": []interface{}{map[string]interface{}{"resources": map[string]interface{}{"kinds": []interface{}{"Pod"}}}},
+	}, rule["match"])
+}
@@ -0,0 +1,36 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kyverno
+
+import (
+	"fmt"
+
+	"github.com/oscal-compass/compliance-to-policy/go/pkg"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal"
+)
+
+// applyParameters replaces parameter placeholders in the yaml files of the directory with the values of the parameters
+// of the rules referring to the policy.
+func applyParameters(dir string, policyObject oscal.PolicyObject) error {
+	return pkg.ReplaceParametersInDir(dir, func(placeholder pkg.Placeholder) ([]string, string, error) {
+		values, found := policyObject.Parameters[placeholder.ParamId]
+		if !found {
+			return nil, "", fmt.Errorf("unknown parameter %s: no rule referring to policy %s has such parameter", placeholder.ParamId, policyObject.PolicyId)
+		}
+		return values, placeholder.Tag(), nil
+	})
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kyverno
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// Cluster-scoped kinds of the API groups built into the API server, Kyverno and the CRDs. The scope is not part of the Go types,
+// so the other kinds of the API groups of the scheme of client-go are registered as namespaced.
+var builtinClusterScopedKinds = []schema.GroupKind{
+	{Group: "", Kind: "Namespace"},
+	{Group: "", Kind: "Node"},
+	{Group: "", Kind: "PersistentVolume"},
+	{Group: "", Kind: "ComponentStatus"},
+	{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"},
+	{Group: "rbac.authorization.k8s.io", Kind: "ClusterRoleBinding"},
+	{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"},
+	{Group: "apiregistration.k8s.io", Kind: "APIService"},
+	{Group: "storage.k8s.io", Kind: "StorageClass"},
+	{Group: "storage.k8s.io", Kind: "CSIDriver"},
+	{Group: "storage.k8s.io", Kind: "CSINode"},
+	{Group: "storage.k8s.io", Kind: "VolumeAttachment"},
+	{Group: "storage.k8s.io", Kind: "VolumeAttributesClass"},
+	{Group: "scheduling.k8s.io", Kind: "PriorityClass"},
+	{Group: "networking.k8s.io", Kind: "IngressClass"},
+	{Group: "networking.k8s.io", Kind: "IPAddress"},
+	{Group: "networking.k8s.io", Kind: "ServiceCIDR"},
+	{Group: "node.k8s.io", Kind: "RuntimeClass"},
+	{Group: "admissionregistration.k8s.io", Kind: "ValidatingWebhookConfiguration"},
+	{Group: "admissionregistration.k8s.io", Kind: "MutatingWebhookConfiguration"},
+	{Group: "admissionregistration.k8s.io", Kind: "ValidatingAdmissionPolicy"},
+	{Group: "admissionregistration.k8s.io", Kind: "ValidatingAdmissionPolicyBinding"},
+	{Group: "certificates.k8s.io", Kind: "CertificateSigningRequest"},
+	{Group: "certificates.k8s.io", Kind: "ClusterTrustBundle"},
+	{Group: "flowcontrol.apiserver.k8s.io", Kind: "FlowSchema"},
+	{Group: "flowcontrol.apiserver.k8s.io", Kind: "PriorityLevelConfiguration"},
+	{Group: "resource.k8s.io", Kind: "DeviceClass"},
+	{Group: "resource.k8s.io", Kind: "ResourceSlice"},
+	{Group: "internal.apiserver.k8s.io", Kind: "StorageVersion"},
+	{Group: "authentication.k8s.io", Kind: "TokenReview"},
+	{Group: "authentication.k8s.io", Kind: "SelfSubjectReview"},
+	{Group: "authorization.k8s.io", Kind: "SubjectAccessReview"},
+	{Group: "authorization.k8s.io", Kind: "SelfSubjectAccessReview"},
+	{Group: "authorization.k8s.io", Kind: "SelfSubjectRulesReview"},
+	{Group: kyvernov1.GroupName, Kind: "ClusterPolicy"},
+}
+
+// Versions of the API groups that are not in the scheme of client-go
+var extraGroupVersionKinds = []schema.GroupVersionKind{
+	{Group: "apiregistration.k8s.io", Version: "v1", Kind: "APIService"},
+}
+
+var metav1PkgPath = reflect.TypeOf(metav1.Status{}).PkgPath()
+
+// NewBuiltinRESTMapper returns the RESTMapper of the kinds built into the API server (the scheme of client-go), of the CRDs and of Kyverno.
+func NewBuiltinRESTMapper() meta.RESTMapper {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(apiextv1.AddToScheme(scheme))
+	utilruntime.Must(kyvernov1.AddToScheme(scheme))
+	clusterScoped := map[schema.GroupKind]bool{}
+	for _, gk := range builtinClusterScopedKinds {
+		clusterScoped[gk] = true
+	}
+	mapper := meta.NewDefaultRESTMapper(scheme.PrioritizedVersionsAllGroups())
+	add := func(gvk schema.GroupVersionKind) {
+		if clusterScoped[gvk.GroupKind()] {
+			mapper.Add(gvk, meta.RESTScopeRoot)
+		} else {
+			mapper.Add(gvk, meta.RESTScopeNamespace)
+		}
+	}
+	for gvk, t := range scheme.AllKnownTypes() {
+		// Options, events of watches and lists are not resources
+		if gvk.Version == runtime.APIVersionInternal || t.PkgPath() == metav1PkgPath || strings.HasSuffix(gvk.Kind, "List") {
+			continue
+		}
+		add(gvk)
+	}
+	for _, gvk := range extraGroupVersionKinds {
+		add(gvk)
+	}
+	return mapper
+}
+
+// NewManifestRESTMapper returns the RESTMapper of the custom resources defined by the CustomResourceDefinitions of the manifests
+func NewManifestRESTMapper(resources []*unstructured.Unstructured) (meta.RESTMapper, error) {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	for _, resource := range resources {
+		if resource.GroupVersionKind().GroupKind() != apiextv1.Kind("CustomResourceDefinition") {
+			continue
+		}
+		var crd apiextv1.CustomResourceDefinition
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(resource.Object, &crd); err != nil {
+			return nil, fmt.Errorf("failed to load CustomResourceDefinition %s: %w", resource.GetName(), err)
+		}
+		scope := meta.RESTScopeNamespace
+		if crd.Spec.Scope == apiextv1.ClusterScoped {
+			scope = meta.RESTScopeRoot
+		}
+		for _, version := range crd.Spec.Versions {
+			mapper.Add(schema.GroupVersionKind{Group: crd.Spec.Group, Version: version.Name, Kind: crd.Spec.Names.Kind}, scope)
+		}
+	}
+	return mapper, nil
+}
+
+// NewDiscoveryRESTMapper returns the RESTMapper of the kinds served by the cluster of the kubeconfig
+func NewDiscoveryRESTMapper(kubeconfigPath string) (meta.RESTMapper, error) {
+	config, err := clientcmd.BuildConfigFromFlags("", kubeconfigPath)
+	if err != nil {
+		return nil, err
+	}
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient)), nil
+}
+
+// isNamespaced returns whether the kind is namespaced by the mappers in order
+func isNamespaced(mappers []meta.RESTMapper, gvk schema.GroupVersionKind) (bool, error) {
+	var lastErr error
+	for _, mapper := range mappers {
+		mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		return mapping.Scope.Name() == meta.RESTScopeNameNamespace, nil
+	}
+	if lastErr == nil {
+		lastErr = &meta.NoKindMatchError{GroupKind: gvk.GroupKind(), SearchedVersions: []string{gvk.Version}}
+	}
+	return false, lastErr
+}
@@ -18,6 +18,8 @@ package kyverno
 
 import (
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -25,6 +27,7 @@ import (
 	"github.com/oscal-compass/compliance-to-policy/go/pkg"
 	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal"
 	typec2pcr "github.com/oscal-compass/compliance-to-policy/go/pkg/types/c2pcr"
+	typeap "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/assessmentplan"
 	typear "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/assessmentresults"
 	typeoscalcommon "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/common"
 	"go.uber.org/zap"
@@ -35,11 +38,15 @@ import (
 type ResultToOscal struct {
 	logger                  *zap.Logger
 	c2pParsed               typec2pcr.C2PCRParsed
-	policyResultsDir        string
+	source                  ResultsSource
 	policyReportList        *typepolr.PolicyReportList
 	clusterPolicyReportList *typepolr.ClusterPolicyReportList
 	policyList              *kyvernov1.PolicyList
 	clusterPolicyList       *kyvernov1.ClusterPolicyList
+	aggregationRule         oscal.AggregationRule
+	stamper                 oscal.Stamper
+	assessmentPlanHref      string
+	assessmentPlan          *typeap.AssessmentPlan
 }
 
 type PolicyReportContainer struct {
@@ -50,43 +57,79 @@ type PolicyReportContainer struct {
 type PolicyResourceIndexContainer struct {
 	PolicyResourceIndex PolicyResourceIndex
 	ControlIds          []string
+	// Rule validated by the policy
+	RuleId string
+	// Check of the rule implemented by the policy. Empty if the rule has no checks.
+	CheckId string
 }
 
 func NewResultToOscal(c2pParsed typec2pcr.C2PCRParsed, policyResultsDir string) *ResultToOscal {
 	r := ResultToOscal{
 		logger:                  pkg.GetLogger("kyverno/result2oscal"),
 		c2pParsed:               c2pParsed,
-		policyResultsDir:        policyResultsDir,
+		source:                  NewFileResultsSource(policyResultsDir),
 		policyReportList:        &typepolr.PolicyReportList{},
 		clusterPolicyReportList: &typepolr.ClusterPolicyReportList{},
 		policyList:              &kyvernov1.PolicyList{},
 		clusterPolicyList:       &kyvernov1.ClusterPolicyList{},
+		aggregationRule:         oscal.AggregationRuleAllPass,
+		stamper:                 oscal.NewStamper(false),
 	}
 	return &r
 }
 
-func (r *ResultToOscal) aggregateComponentObjects() (policyResourceIndice []PolicyResourceIndex, controlIds []string) {
-	controlIdSets := sets.NewString()
+// SetResultsSource replaces the directory given to NewResultToOscal with the source of the policies and the policy reports.
+func (r *ResultToOscal) SetResultsSource(source ResultsSource) {
+	r.source = source
+}
+
+// SetAggregationRule sets the rule aggregating the results of the observations into the status of the findings of the controls.
+func (r *ResultToOscal) SetAggregationRule(aggregationRule oscal.AggregationRule) {
+	r.aggregationRule = aggregationRule
+}
+
+// SetDeterministic makes the same inputs produce the same assessment results.
+func (r *ResultToOscal) SetDeterministic(deterministic bool) {
+	r.stamper = oscal.NewStamper(deterministic)
+}
+
+// SetAssessmentPlan makes the assessment results import the assessment plan by the href.
+func (r *ResultToOscal) SetAssessmentPlan(href string, ap typeap.AssessmentPlan) {
+	r.assessmentPlanHref = href
+	r.assessmentPlan = &ap
+}
+
+func (r *ResultToOscal) aggregateComponentObjects() (priContainers []PolicyResourceIndexContainer, controlObjects []oscal.ControlObject) {
 	for _, componentObject := range r.c2pParsed.ComponentObjects {
+		// Validation components only map checks to the rules of the target components
 		if componentObject.ComponentType == "validation" {
 			continue
 		}
 		for _, ruleObject := range componentObject.RuleObjects {
-			sourceDir := fmt.Sprintf("%s/%s", r.c2pParsed.PolicyResoureDir, ruleObject.RuleId)
-			fl := NewFileLoader()
-			if err := fl.LoadFromDirectory(sourceDir); err != nil {
-				r.logger.Error(fmt.Sprintf("Failed to load %s", sourceDir))
-				continue
+			for _, policyName := range ruleObject.PolicyIds(true) {
+				sourceDir := fmt.Sprintf("%s/%s", r.c2pParsed.PolicyResoureDir, policyName)
+				fl := NewFileLoader()
+				if err := fl.LoadFromDirectory(sourceDir); err != nil {
+					r.logger.Error(fmt.Sprintf("Failed to load %s", sourceDir))
+					continue
+				}
+				checkId := ""
+				if slices.Contains(ruleObject.CheckIds(), policyName) {
+					checkId = policyName
+				}
+				for _, pri := range fl.GetPolicyResourceIndice() {
+					priContainers = append(priContainers, PolicyResourceIndexContainer{
+						PolicyResourceIndex: pri,
+						RuleId:              ruleObject.RuleId,
+						CheckId:             checkId,
+					})
+				}
 			}
-			policyResourceIndice = append(policyResourceIndice, fl.GetPolicyResourceIndice()...)
 		}
 		for _, cio := range componentObject.ControlImpleObjects {
-			for _, cos := range cio.ControlObjects {
-				controlIdSets = controlIdSets.Insert(cos.GetControlId())
-			}
+			controlObjects = append(controlObjects, cio.ControlObjects...)
 		}
 	}
-	controlIds = controlIdSets.List()
 	return
 }
 
@@ -109,24 +152,68 @@ func (r *ResultToOscal) findControls(ruleId string) []oscal.ControlObject {
 	return controls
 }
 
-func (r *ResultToOscal) retrievePolicyReportResults(name string) []*typepolr.PolicyReportResult {
+// retrievePolicyReportResults returns the results of the policy in the PolicyReports and the ClusterPolicyReports.
+// Results of namespaced resources are in the PolicyReports and those of cluster-scoped resources (e.g. Namespaces) in the ClusterPolicyReports.
+// Results of a namespaced Policy are only in the PolicyReports of the namespace of the policy.
+func (r *ResultToOscal) retrievePolicyReportResults(pri PolicyResourceIndex) []*typepolr.PolicyReportResult {
 	prrs := []*typepolr.PolicyReportResult{}
-	for _, polr := range r.policyReportList.Items {
-		for _, result := range polr.Results {
-			policy := result.Policy
-			if policy == name {
-				prrs = append(prrs, &result)
+	for i := range r.policyReportList.Items {
+		polr := &r.policyReportList.Items[i]
+		if pri.IsNamespaced() && pri.Namespace != "" && polr.Namespace != pri.Namespace {
+			continue
+		}
+		prrs = append(prrs, filterPolicyReportResults(polr.Results, pri, polr.Namespace)...)
+	}
+	if pri.IsNamespaced() {
+		return prrs
+	}
+	for i := range r.clusterPolicyReportList.Items {
+		prrs = append(prrs, filterPolicyReportResults(r.clusterPolicyReportList.Items[i].Results, pri, "")...)
+	}
+	return prrs
+}
+
+// filterPolicyReportResults returns the results of the policy from the results of the report in the namespace.
+// Kyverno writes the policy field of the results of namespaced Policies as <namespace>/<name> (<name> by the older versions).
+func filterPolicyReportResults(results []typepolr.PolicyReportResult, pri PolicyResourceIndex, reportNamespace string) []*typepolr.PolicyReportResult {
+	prrs := []*typepolr.PolicyReportResult{}
+	for i := range results {
+		policy := results[i].Policy
+		if pri.IsNamespaced() {
+			namespace, name, found := strings.Cut(policy, "/")
+			if !found {
+				namespace, name = reportNamespace, policy
 			}
+			// A namespaced Policy without the namespace in the policy resources matches the policies of any namespace
+			if name != pri.Name || (pri.Namespace != "" && namespace != pri.Namespace) {
+				continue
+			}
+		} else if policy != pri.Name {
+			continue
 		}
+		prrs = append(prrs, &results[i])
 	}
 	return prrs
 }
 
-func (r *ResultToOscal) loadData(path string, out interface{}) error {
-	if err := pkg.LoadYamlFileToK8sTypedObject(r.policyResultsDir+"/"+path, &out); err != nil {
-		return err
+// makeResultProps makes the props of a subject from the result of a rule of the policy.
+func makeResultProps(prr *typepolr.PolicyReportResult) []typeoscalcommon.Prop {
+	props := []typeoscalcommon.Prop{}
+	props = append(props, makeProp("result", string(prr.Result)))
+	if reason := oscal.ToPropValue(prr.Description); reason != "" {
+		props = append(props, makeProp("reason", reason))
 	}
-	return nil
+	if prr.Rule != "" {
+		props = append(props, makeProp("rule", prr.Rule))
+	}
+	if prr.Severity != "" {
+		props = append(props, makeProp("severity", string(prr.Severity)))
+	}
+	if prr.Category != "" {
+		props = append(props, makeProp("category", oscal.ToPropValue(prr.Category)))
+	}
+	props = append(props, makeProp("scored", strconv.FormatBool(prr.Scored)))
+	return props
 }
 
 func makeProp(name string, value string) typeoscalcommon.Prop {
@@ -137,59 +224,61 @@ func makeProp(name string, value string) typeoscalcommon.Prop {
 }
 
 func (r *ResultToOscal) GenerateAssessmentResults() (*typear.AssessmentResultsRoot, error) {
-	var polList kyvernov1.PolicyList
-	if err := r.loadData("/policies.kyverno.io.yaml", &polList); err != nil {
-		return nil, err
-	}
-
-	var cpolList kyvernov1.ClusterPolicyList
-	if err := r.loadData("/clusterpolicies.kyverno.io.yaml", &cpolList); err != nil {
-		return nil, err
-	}
-
-	var polrList typepolr.PolicyReportList
-	if err := r.loadData("/policyreports.wgpolicyk8s.io.yaml", &polrList); err != nil {
-		return nil, err
-	}
-	r.policyReportList = &polrList
-
-	var cpolrList typepolr.ClusterPolicyReportList
-	if err := r.loadData("/clusterpolicyreports.wgpolicyk8s.io.yaml", &cpolrList); err != nil {
+	policyResults, err := r.source.Load()
+	if err != nil {
 		return nil, err
 	}
+	r.policyList = &policyResults.PolicyList
+	r.clusterPolicyList = &policyResults.ClusterPolicyList
+	r.policyReportList = &policyResults.PolicyReportList
+	r.clusterPolicyReportList = &policyResults.ClusterPolicyReportList
 
 	observations := []typear.Observation{}
-	pris, controlIds := r.aggregateComponentObjects()
+	priContainers, controlObjects := r.aggregateComponentObjects()
+	inputTimestamps := []time.Time{}
 
-	for _, pri := range pris {
-		name := pri.Name
-		prrs := r.retrievePolicyReportResults(name)
+	for _, priContainer := range priContainers {
+		pri := priContainer.PolicyResourceIndex
+		prrs := r.retrievePolicyReportResults(pri)
 		props := []typeoscalcommon.Prop{}
-		props = append(props, makeProp("assessment-rule-id", name))
-		controls := r.findControls(name)
+		props = append(props, makeProp("assessment-rule-id", priContainer.RuleId))
+		description := fmt.Sprintf("Observation of rule %s", priContainer.RuleId)
+		if priContainer.CheckId != "" {
+			props = append(props, makeProp("check-id", priContainer.CheckId))
+			description = fmt.Sprintf("Observation of check %s of rule %s", priContainer.CheckId, priContainer.RuleId)
+		}
+		props = append(props, makeProp("policy-id", pri.Name))
+		if pri.IsNamespaced() && pri.Namespace != "" {
+			props = append(props, makeProp("policy-namespace", pri.Namespace))
+		}
+		controls := r.findControls(priContainer.RuleId)
 		controlIds := sets.NewString()
 		for _, control := range controls {
 			controlIds = controlIds.Insert(control.GetControlId())
 		}
 		props = append(props, makeProp("controls", strings.Join(controlIds.List(), ",")))
 		observation := typear.Observation{
-			UUID:        oscal.GenerateUUID(),
-			Description: fmt.Sprintf("Observation of rule %s", pri.Name),
+			UUID:        r.stamper.UUID("observation", priContainer.RuleId, priContainer.CheckId, pri.Kind, pri.Namespace, pri.Name),
+			Description: description,
 			Methods:     []string{"TEST-AUTOMATED"},
 			Props:       props,
 			Subjects:    []typear.Subject{},
 		}
 		for _, prr := range prrs {
-			props := []typeoscalcommon.Prop{}
-			props = append(props, makeProp("result", string(prr.Result)))
-			props = append(props, makeProp("reason", prr.Description))
+			if prr.Timestamp.Seconds > 0 {
+				collected := time.Unix(prr.Timestamp.Seconds, int64(prr.Timestamp.Nanos)).UTC()
+				inputTimestamps = append(inputTimestamps, collected)
+				if collected.After(observation.Collected) {
+					observation.Collected = collected
+				}
+			}
 			for _, resource := range prr.Subjects {
 				gvknsn := fmt.Sprintf("ApiVersion: %s, Kind: %s, Namespace: %s, Name: %s", resource.APIVersion, resource.Kind, resource.Namespace, resource.Name)
 				subject := typear.Subject{
 					SubjectUUID: string(resource.UID),
 					Title:       gvknsn,
 					Type:        "resource",
-					Props:       props,
+					Props:       makeResultProps(prr),
 				}
 				observation.Subjects = append(observation.Subjects, subject)
 			}
@@ -197,43 +286,52 @@ func (r *ResultToOscal) GenerateAssessmentResults() (*typear.AssessmentResultsRo
 		observations = append(observations, observation)
 	}
 
+	timestamp, err := r.stamper.Timestamp(inputTimestamps...)
+	if err != nil {
+		return nil, err
+	}
+	// Observations without results are collected at the time of the assessment results
+	for i := range observations {
+		if observations[i].Collected.IsZero() {
+			observations[i].Collected = timestamp
+		}
+	}
 	metadata := typear.Metadata{
 		Title:        "OSCAL Assessment Results",
-		LastModified: time.Now(),
+		LastModified: timestamp,
 		Version:      "0.0.1",
-		OscalVersion: "1.0.4",
-	}
-	importAp := typear.ImportAp{
-		Href: "http://...",
+		OscalVersion: oscal.OscalVersion,
 	}
 	ar := typear.AssessmentResults{
-		UUID:     oscal.GenerateUUID(),
+		UUID:     r.stamper.UUID("assessment-results", "kyverno", timestamp.Format(time.RFC3339Nano)),
 		Metadata: metadata,
-		ImportAp: importAp,
 		Results:  []typear.Result{},
 	}
 
-	scs := []typear.SelectControlById{}
-	for _, controlId := range controlIds {
-		scs = append(scs, typear.SelectControlById{
-			ControlID: controlId,
-		})
-	}
 	controlSelection := typear.ControlSelection{
-		IncludeControls: scs,
+		IncludeControls: oscal.SelectControls(controlObjects),
 	}
 	result := typear.Result{
-		UUID:        oscal.GenerateUUID(),
+		UUID:        r.stamper.UUID("result", "kyverno", timestamp.Format(time.RFC3339Nano)),
 		Title:       "Assessment Results by Kyverno Policy",
 		Description: "Assessment Results by Kyverno Policy...",
-		Start:       time.Now(),
-		ReviewedControls: []typear.ReviewedControl{{
+		Start:       timestamp,
+		ReviewedControls: typear.ReviewedControl{
 			ControlSelections: []typear.ControlSelection{controlSelection},
-		}},
+		},
 		Observations: observations,
+		Findings:     oscal.GenerateFindings(r.c2pParsed.ComponentObjects, observations, r.aggregationRule, r.stamper),
 	}
 
 	ar.Results = append(ar.Results, result)
+	if r.assessmentPlan != nil {
+		oscal.ImportAssessmentPlan(&ar, r.assessmentPlanHref, *r.assessmentPlan)
+	} else {
+		oscal.ImportComponentDefinition(&ar, r.c2pParsed.ComponentDefinition, r.stamper)
+	}
+	if r.stamper.IsDeterministic() {
+		oscal.SortAssessmentResults(&ar)
+	}
 	arRoot := typear.AssessmentResultsRoot{AssessmentResults: ar}
 	return &arRoot, nil
 }
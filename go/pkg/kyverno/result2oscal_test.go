@@ -0,0 +1,203 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kyverno
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/oscal-compass/compliance-to-policy/go/pkg"
+	typec2pcr "github.com/oscal-compass/compliance-to-policy/go/pkg/types/c2pcr"
+	typear "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/assessmentresults"
+	typeoscalcommon "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/common"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	typepolr "sigs.k8s.io/wg-policy-prototypes/policy-report/pkg/api/wgpolicyk8s.io/v1beta1"
+)
+
+type staticResultsSource struct {
+	policyResults PolicyResults
+}
+
+func (s staticResultsSource) Load() (*PolicyResults, error) {
+	return &s.policyResults, nil
+}
+
+func parseTestC2PCR(t *testing.T, cdPath string, policyDir string) typec2pcr.C2PCRParsed {
+	tempDirPath := pkg.PathFromPkgDirectory("./testdata/_test")
+	err := os.MkdirAll(tempDirPath, os.ModePerm)
+	assert.NoError(t, err, "Should not happen")
+
+	c2pcrSpec := typec2pcr.Spec{
+		Compliance: typec2pcr.Compliance{
+			Name: "Test Compliance",
+			ComponentDefinition: typec2pcr.ResourceRef{
+				Url: pkg.PathFromPkgDirectory(cdPath),
+			},
+		},
+		PolicyResources: typec2pcr.ResourceRef{
+			Url: pkg.PathFromPkgDirectory(policyDir),
+		},
+	}
+	c2pcrParser := NewParser(pkg.NewGitUtils(pkg.NewTempDirectory(tempDirPath)))
+	c2pcrParsed, err := c2pcrParser.Parse(c2pcrSpec)
+	assert.NoError(t, err, "Should not happen")
+	return c2pcrParsed
+}
+
+func findProp(props []typeoscalcommon.Prop, name string) string {
+	for _, prop := range props {
+		if prop.Name == name {
+			return prop.Value
+		}
+	}
+	return ""
+}
+
+func TestResultToOscalWithClusterPolicyReports(t *testing.T) {
+	policyResults := PolicyResults{
+		PolicyReportList: typepolr.PolicyReportList{Items: []typepolr.PolicyReport{{
+			ObjectMeta: metav1.ObjectMeta{Name: "polr", Namespace: "app"},
+			Results: []typepolr.PolicyReportResult{{
+				Policy:    "allowed-base-images",
+				Rule:      "allowed-base-images",
+				Result:    "fail",
+				Severity:  "medium",
+				Category:  "Other",
+				Scored:    true,
+				Timestamp: metav1.Timestamp{Seconds: 1697608494},
+				Subjects: []corev1.ObjectReference{
+					{APIVersion: "v1", Kind: "Pod", Namespace: "app", Name: "pod-a", UID: "uid-pod-a"},
+					{APIVersion: "v1", Kind: "Pod", Namespace: "app", Name: "pod-b", UID: "uid-pod-b"},
+				},
+			}, {
+				Policy:    "other-policy",
+				Result:    "fail",
+				Timestamp: metav1.Timestamp{Seconds: 1697608999},
+				Subjects:  []corev1.ObjectReference{{APIVersion: "v1", Kind: "Pod", Namespace: "app", Name: "pod-c", UID: "uid-pod-c"}},
+			}},
+		}}},
+		ClusterPolicyReportList: typepolr.ClusterPolicyReportList{Items: []typepolr.ClusterPolicyReport{{
+			ObjectMeta: metav1.ObjectMeta{Name: "cpolr"},
+			Results: []typepolr.PolicyReportResult{{
+				Policy:    "allowed-base-images",
+				Rule:      "autogen-allowed-base-images",
+				Result:    "pass",
+				Severity:  "high",
+				Timestamp: metav1.Timestamp{Seconds: 1697608500},
+				Subjects:  []corev1.ObjectReference{{APIVersion: "v1", Kind: "Namespace", Name: "app", UID: "uid-ns-app"}},
+			}},
+		}}},
+	}
+
+	r := NewResultToOscal(parseTestC2PCR(t, "./testdata/kyverno/component-definition.json", "./testdata/kyverno/policy-resources"), "")
+	r.SetResultsSource(staticResultsSource{policyResults: policyResults})
+	r.SetDeterministic(true)
+	arRoot, err := r.GenerateAssessmentResults()
+	assert.NoError(t, err, "Should not happen")
+
+	observations := arRoot.AssessmentResults.Results[0].Observations
+	assert.Equal(t, 1, len(observations))
+	observation := observations[0]
+	assert.Equal(t, time.Unix(1697608500, 0).UTC(), observation.Collected)
+
+	subjects := map[string]typear.Subject{}
+	for _, subject := range observation.Subjects {
+		subjects[subject.SubjectUUID] = subject
+	}
+	assert.Equal(t, 3, len(subjects))
+	assert.NotContains(t, subjects, "uid-pod-c")
+
+	podA := subjects["uid-pod-a"]
+	assert.Equal(t, "fail", findProp(podA.Props, "result"))
+	assert.Equal(t, "allowed-base-images", findProp(podA.Props, "rule"))
+	assert.Equal(t, "medium", findProp(podA.Props, "severity"))
+	assert.Equal(t, "Other", findProp(podA.Props, "category"))
+	assert.Equal(t, "true", findProp(podA.Props, "scored"))
+
+	namespace := subjects["uid-ns-app"]
+	assert.Equal(t, "pass", findProp(namespace.Props, "result"))
+	assert.Equal(t, "autogen-allowed-base-images", findProp(namespace.Props, "rule"))
+	assert.Equal(t, "high", findProp(namespace.Props, "severity"))
+	assert.Equal(t, "", findProp(namespace.Props, "category"))
+	assert.Equal(t, "false", findProp(namespace.Props, "scored"))
+
+	// Subjects of the same result must not share the props
+	podA.Props[0].Value = "modified"
+	assert.Equal(t, "fail", findProp(subjects["uid-pod-b"].Props, "result"))
+}
+
+func makeTestPolicyReport(namespace string, results ...typepolr.PolicyReportResult) typepolr.PolicyReport {
+	return typepolr.PolicyReport{
+		ObjectMeta: metav1.ObjectMeta{Name: "polr", Namespace: namespace},
+		Results:    results,
+	}
+}
+
+func makeTestPolicyReportResult(policy string, result typepolr.PolicyResult, namespace string, name string) typepolr.PolicyReportResult {
+	return typepolr.PolicyReportResult{
+		Policy:   policy,
+		Rule:     "check-team-label",
+		Result:   result,
+		Subjects: []corev1.ObjectReference{{APIVersion: "apps/v1", Kind: "Deployment", Namespace: namespace, Name: name, UID: types.UID("uid-" + name)}},
+	}
+}
+
+func TestResultToOscalWithNamespacedPolicies(t *testing.T) {
+	policyResults := PolicyResults{
+		PolicyReportList: typepolr.PolicyReportList{Items: []typepolr.PolicyReport{
+			makeTestPolicyReport("team-a",
+				makeTestPolicyReportResult("team-a/require-team-label", "fail", "team-a", "deploy-a"),
+				// Older Kyverno writes the name of the policy only
+				makeTestPolicyReportResult("require-team-label", "pass", "team-a", "deploy-a2"),
+			),
+			makeTestPolicyReport("team-b",
+				makeTestPolicyReportResult("team-b/require-team-label", "pass", "team-b", "deploy-b"),
+			),
+			makeTestPolicyReport("team-c",
+				makeTestPolicyReportResult("team-c/require-team-label", "fail", "team-c", "deploy-c"),
+				makeTestPolicyReportResult("require-team-label", "fail", "team-c", "deploy-c2"),
+			),
+		}},
+		ClusterPolicyReportList: typepolr.ClusterPolicyReportList{Items: []typepolr.ClusterPolicyReport{{
+			ObjectMeta: metav1.ObjectMeta{Name: "cpolr"},
+			Results:    []typepolr.PolicyReportResult{makeTestPolicyReportResult("require-team-label", "fail", "", "cluster-scoped")},
+		}}},
+	}
+
+	r := NewResultToOscal(parseTestC2PCR(t, "./testdata/kyverno/namespaced/component-definition.json", "./testdata/kyverno/namespaced/policy-resources"), "")
+	r.SetResultsSource(staticResultsSource{policyResults: policyResults})
+	r.SetDeterministic(true)
+	arRoot, err := r.GenerateAssessmentResults()
+	assert.NoError(t, err, "Should not happen")
+
+	subjectsPerNamespace := map[string][]string{}
+	for _, observation := range arRoot.AssessmentResults.Results[0].Observations {
+		assert.Equal(t, "require-team-label", findProp(observation.Props, "policy-id"))
+		namespace := findProp(observation.Props, "policy-namespace")
+		for _, subject := range observation.Subjects {
+			subjectsPerNamespace[namespace] = append(subjectsPerNamespace[namespace], subject.SubjectUUID)
+		}
+	}
+	assert.Equal(t, map[string][]string{
+		"team-a": {"uid-deploy-a", "uid-deploy-a2"},
+		"team-b": {"uid-deploy-b"},
+	}, subjectsPerNamespace)
+}
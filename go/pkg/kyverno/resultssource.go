@@ -0,0 +1,156 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kyverno
+
+import (
+	"context"
+	"fmt"
+
+	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/clientcmd"
+	typepolr "sigs.k8s.io/wg-policy-prototypes/policy-report/pkg/api/wgpolicyk8s.io/v1beta1"
+)
+
+// DefaultPageSize is the number of objects listed per request by the cluster results source.
+const DefaultPageSize int64 = 500
+
+var (
+	PolicyGVR              = schema.GroupVersionResource{Group: "kyverno.io", Version: "v1", Resource: "policies"}
+	ClusterPolicyGVR       = schema.GroupVersionResource{Group: "kyverno.io", Version: "v1", Resource: "clusterpolicies"}
+	PolicyReportGVR        = schema.GroupVersionResource{Group: "wgpolicyk8s.io", Version: "v1alpha2", Resource: "policyreports"}
+	ClusterPolicyReportGVR = schema.GroupVersionResource{Group: "wgpolicyk8s.io", Version: "v1alpha2", Resource: "clusterpolicyreports"}
+)
+
+// PolicyResults holds the Kyverno policies and the policy reports from which the assessment results are generated.
+type PolicyResults struct {
+	PolicyList              kyvernov1.PolicyList
+	ClusterPolicyList       kyvernov1.ClusterPolicyList
+	PolicyReportList        typepolr.PolicyReportList
+	ClusterPolicyReportList typepolr.ClusterPolicyReportList
+}
+
+// ResultsSource loads the Kyverno policies and the policy reports.
+type ResultsSource interface {
+	Load() (*PolicyResults, error)
+}
+
+// FileResultsSource loads the policies and the policy reports from the lists dumped into a directory
+// (policies.kyverno.io.yaml, clusterpolicies.kyverno.io.yaml, policyreports.wgpolicyk8s.io.yaml and clusterpolicyreports.wgpolicyk8s.io.yaml).
+type FileResultsSource struct {
+	dir string
+}
+
+func NewFileResultsSource(dir string) *FileResultsSource {
+	return &FileResultsSource{dir: dir}
+}
+
+func (s *FileResultsSource) Load() (*PolicyResults, error) {
+	results := PolicyResults{}
+	files := []struct {
+		name string
+		out  interface{}
+	}{
+		{"policies.kyverno.io.yaml", &results.PolicyList},
+		{"clusterpolicies.kyverno.io.yaml", &results.ClusterPolicyList},
+		{"policyreports.wgpolicyk8s.io.yaml", &results.PolicyReportList},
+		{"clusterpolicyreports.wgpolicyk8s.io.yaml", &results.ClusterPolicyReportList},
+	}
+	for _, file := range files {
+		if err := pkg.LoadYamlFileToK8sTypedObject(s.dir+"/"+file.name, file.out); err != nil {
+			return nil, err
+		}
+	}
+	return &results, nil
+}
+
+// ClusterResultsSource lists the policies and the policy reports in all namespaces of a cluster.
+type ClusterResultsSource struct {
+	client   dynamic.Interface
+	pageSize int64
+}
+
+func NewClusterResultsSource(client dynamic.Interface) *ClusterResultsSource {
+	return &ClusterResultsSource{client: client, pageSize: DefaultPageSize}
+}
+
+// SetPageSize sets the number of objects listed per request.
+func (s *ClusterResultsSource) SetPageSize(pageSize int64) {
+	s.pageSize = pageSize
+}
+
+func (s *ClusterResultsSource) Load() (*PolicyResults, error) {
+	results := PolicyResults{}
+	lists := []struct {
+		gvr schema.GroupVersionResource
+		out interface{}
+	}{
+		{PolicyGVR, &results.PolicyList},
+		{ClusterPolicyGVR, &results.ClusterPolicyList},
+		{PolicyReportGVR, &results.PolicyReportList},
+		{ClusterPolicyReportGVR, &results.ClusterPolicyReportList},
+	}
+	for _, list := range lists {
+		unstList, err := s.listAll(list.gvr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", list.gvr.GroupResource(), err)
+		}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(unstList.UnstructuredContent(), list.out); err != nil {
+			return nil, fmt.Errorf("failed to convert %s: %w", list.gvr.GroupResource(), err)
+		}
+	}
+	return &results, nil
+}
+
+// listAll lists the objects of the resource page by page following the continue token.
+func (s *ClusterResultsSource) listAll(gvr schema.GroupVersionResource) (*unstructured.UnstructuredList, error) {
+	all := &unstructured.UnstructuredList{Object: map[string]interface{}{}}
+	all.SetAPIVersion("v1")
+	all.SetKind("List")
+	listOptions := metav1.ListOptions{Limit: s.pageSize}
+	for {
+		page, err := s.client.Resource(gvr).List(context.TODO(), listOptions)
+		if err != nil {
+			return nil, err
+		}
+		all.Items = append(all.Items, page.Items...)
+		if page.GetContinue() == "" {
+			return all, nil
+		}
+		listOptions.Continue = page.GetContinue()
+	}
+}
+
+// NewDynamicClient creates a dynamic client from the kubeconfig and the context.
+// The default loading rules (KUBECONFIG or ~/.kube/config) and the current context are used if they are empty.
+func NewDynamicClient(kubeconfig string, kubecontext string) (dynamic.Interface, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if kubeconfig != "" {
+		loadingRules.ExplicitPath = kubeconfig
+	}
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: kubecontext}
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides).ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	return dynamic.NewForConfig(config)
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kyverno
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/oscal-compass/compliance-to-policy/go/pkg"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+var listKinds = map[schema.GroupVersionResource]string{
+	PolicyGVR:              "PolicyList",
+	ClusterPolicyGVR:       "ClusterPolicyList",
+	PolicyReportGVR:        "PolicyReportList",
+	ClusterPolicyReportGVR: "ClusterPolicyReportList",
+}
+
+func toUnstructuredObjects(t *testing.T, policyResults *PolicyResults) []runtime.Object {
+	objs := []runtime.Object{}
+	add := func(obj interface{}) {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		assert.NoError(t, err)
+		objs = append(objs, &unstructured.Unstructured{Object: content})
+	}
+	for i := range policyResults.PolicyList.Items {
+		add(&policyResults.PolicyList.Items[i])
+	}
+	for i := range policyResults.ClusterPolicyList.Items {
+		add(&policyResults.ClusterPolicyList.Items[i])
+	}
+	for i := range policyResults.PolicyReportList.Items {
+		add(&policyResults.PolicyReportList.Items[i])
+	}
+	for i := range policyResults.ClusterPolicyReportList.Items {
+		add(&policyResults.ClusterPolicyReportList.Items[i])
+	}
+	return objs
+}
+
+func TestClusterResultsSource(t *testing.T) {
+	fileSource := NewFileResultsSource(pkg.PathFromPkgDirectory("./testdata/kyverno/policy-reports"))
+	expected, err := fileSource.Load()
+	assert.NoError(t, err)
+	assert.NotEmpty(t, expected.ClusterPolicyList.Items)
+	assert.NotEmpty(t, expected.PolicyReportList.Items)
+
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, toUnstructuredObjects(t, expected)...)
+	var source ResultsSource = NewClusterResultsSource(client)
+	actual, err := source.Load()
+	assert.NoError(t, err)
+
+	assert.Equal(t, len(expected.PolicyList.Items), len(actual.PolicyList.Items))
+	assert.Equal(t, len(expected.ClusterPolicyList.Items), len(actual.ClusterPolicyList.Items))
+	assert.Equal(t, len(expected.PolicyReportList.Items), len(actual.PolicyReportList.Items))
+	assert.Equal(t, len(expected.ClusterPolicyReportList.Items), len(actual.ClusterPolicyReportList.Items))
+	assert.Equal(t, expected.ClusterPolicyList.Items[0].Name, actual.ClusterPolicyList.Items[0].Name)
+	assert.Equal(t, expected.ClusterPolicyList.Items[0].Spec.Rules[0].Name, actual.ClusterPolicyList.Items[0].Spec.Rules[0].Name)
+	for i, polr := range expected.PolicyReportList.Items {
+		assert.Equal(t, polr.Results, actual.PolicyReportList.Items[i].Results)
+	}
+}
+
+func TestClusterResultsSourcePagination(t *testing.T) {
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds)
+
+	pages := [][]string{{"polr-1", "polr-2"}, {"polr-3", "polr-4"}, {"polr-5"}}
+	calls := 0
+	client.PrependReactor("list", PolicyReportGVR.Resource, func(action clienttesting.Action) (bool, runtime.Object, error) {
+		list := &unstructured.UnstructuredList{Object: map[string]interface{}{}}
+		list.SetAPIVersion(PolicyReportGVR.GroupVersion().String())
+		list.SetKind("PolicyReportList")
+		for _, name := range pages[calls] {
+			item := unstructured.Unstructured{}
+			item.SetAPIVersion(PolicyReportGVR.GroupVersion().String())
+			item.SetKind("PolicyReport")
+			item.SetNamespace("default")
+			item.SetName(name)
+			list.Items = append(list.Items, item)
+		}
+		calls++
+		if calls < len(pages) {
+			list.SetContinue(fmt.Sprintf("page-%d", calls))
+		}
+		return true, list, nil
+	})
+
+	source := NewClusterResultsSource(client)
+	source.SetPageSize(2)
+	actual, err := source.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, len(pages), calls)
+	names := []string{}
+	for _, polr := range actual.PolicyReportList.Items {
+		names = append(names, polr.Name)
+	}
+	assert.Equal(t, []string{"polr-1", "polr-2", "polr-3", "polr-4", "polr-5"}, names)
+}
@@ -0,0 +1,637 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kyverno
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	typepolr "sigs.k8s.io/wg-policy-prototypes/policy-report/pkg/api/wgpolicyk8s.io/v1beta1"
+)
+
+// ScanReportName is the name of the policy reports synthesized by the offline scan
+const ScanReportName = "c2p-scan"
+
+// DefaultNamespace is the namespace of namespaced resources of the manifests that have no namespace
+const DefaultNamespace = "default"
+
+// Pod controllers to which Kyverno applies the rules of Pods with the paths to their pod templates
+var podControllers = map[string][]string{
+	"Deployment":            {"spec", "template"},
+	"DaemonSet":             {"spec", "template"},
+	"StatefulSet":           {"spec", "template"},
+	"ReplicaSet":            {"spec", "template"},
+	"ReplicationController": {"spec", "template"},
+	"Job":                   {"spec", "template"},
+	"CronJob":               {"spec", "jobTemplate", "spec", "template"},
+}
+
+const annotationAutogenControllers = "pod-policies.kyverno.io/autogen-controllers"
+
+// Scanner evaluates the validate rules of the Kyverno policies against the manifests without a cluster
+// and synthesizes the policy reports of the results.
+// Only pattern, anyPattern and deny of the validate rules are evaluated. Rules using context, foreach, podSecurity, cel, manifests,
+// verifyImages or variables other than the request result in error, and rules whose match or exclude depends on user info or
+// namespace selector result in skip. Rules result in error also for the resources without a namespace whose kinds have unknown scopes.
+type Scanner struct {
+	logger       *zap.Logger
+	policiesDir  string
+	manifestsDir string
+	timestamp    metav1.Timestamp
+	stamper      oscal.Stamper
+	restMapper   meta.RESTMapper
+}
+
+// scanTarget is a resource evaluated by a rule
+type scanTarget struct {
+	// Name of the rule (autogen-<rule> for the Pod controllers)
+	rule string
+	// Resource reported as the subject
+	resource *unstructured.Unstructured
+	// Object evaluated by the rule (the Pod of the pod template for the Pod controllers)
+	object map[string]interface{}
+	// Path to the object from the resource
+	path string
+	// Reason why the rule is skipped without being evaluated
+	skipReason string
+	// Reason why the rule cannot be evaluated
+	errorReason string
+}
+
+// matchResult is the result of matching a resource with the match or the exclude of a rule
+type matchResult int
+
+const (
+	matchNo matchResult = iota
+	matchYes
+	// matchUnknown is the result of the filters by user info or namespace selector, which cannot be decided
+	// because the requests and the labels of the namespaces are unknown without a cluster
+	matchUnknown
+)
+
+func NewScanner(policiesDir string, manifestsDir string) *Scanner {
+	return &Scanner{
+		logger:       pkg.GetLogger("kyverno/scan"),
+		policiesDir:  policiesDir,
+		manifestsDir: manifestsDir,
+		stamper:      oscal.NewStamper(true),
+	}
+}
+
+// SetTimestamp sets the timestamp of the results. The results have no timestamp if it is not set.
+func (s *Scanner) SetTimestamp(timestamp time.Time) {
+	s.timestamp = metav1.Timestamp{Seconds: timestamp.Unix(), Nanos: int32(timestamp.Nanosecond())}
+}
+
+// SetRESTMapper sets the RESTMapper (e.g. NewDiscoveryRESTMapper) resolving the scopes of the kinds of the manifests
+// in preference to the kinds built into the API server and the CustomResourceDefinitions of the manifests.
+func (s *Scanner) SetRESTMapper(restMapper meta.RESTMapper) {
+	s.restMapper = restMapper
+}
+
+// Load evaluates the policies against the manifests and returns the policies and the synthesized policy reports.
+func (s *Scanner) Load() (*PolicyResults, error) {
+	results := PolicyResults{}
+	policies := []kyvernov1.PolicyInterface{}
+	policyObjs, err := loadObjects(s.policiesDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, obj := range policyObjs {
+		if obj.GroupVersionKind().Group != kyvernov1.GroupName {
+			continue
+		}
+		switch obj.GetKind() {
+		case "ClusterPolicy":
+			var policy kyvernov1.ClusterPolicy
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &policy); err != nil {
+				return nil, fmt.Errorf("failed to load ClusterPolicy %s: %w", obj.GetName(), err)
+			}
+			results.ClusterPolicyList.Items = append(results.ClusterPolicyList.Items, policy)
+		case "Policy":
+			var policy kyvernov1.Policy
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &policy); err != nil {
+				return nil, fmt.Errorf("failed to load Policy %s: %w", obj.GetName(), err)
+			}
+			if policy.Namespace == "" {
+				policy.Namespace = DefaultNamespace
+			}
+			results.PolicyList.Items = append(results.PolicyList.Items, policy)
+		}
+	}
+	for i := range results.ClusterPolicyList.Items {
+		policies = append(policies, &results.ClusterPolicyList.Items[i])
+	}
+	for i := range results.PolicyList.Items {
+		policies = append(policies, &results.PolicyList.Items[i])
+	}
+
+	resources, err := loadObjects(s.manifestsDir)
+	if err != nil {
+		return nil, err
+	}
+	unresolved, err := s.resolveScopes(resources)
+	if err != nil {
+		return nil, err
+	}
+
+	policyReports := map[string]*typepolr.PolicyReport{}
+	clusterPolicyReport := typepolr.ClusterPolicyReport{
+		TypeMeta:   metav1.TypeMeta{APIVersion: PolicyReportGVR.GroupVersion().String(), Kind: "ClusterPolicyReport"},
+		ObjectMeta: metav1.ObjectMeta{Name: ScanReportName},
+	}
+	for _, policy := range policies {
+		for _, rule := range policy.GetSpec().Rules {
+			if !rule.HasValidate() && !rule.HasVerifyImages() {
+				continue
+			}
+			for _, resource := range resources {
+				for _, target := range s.targets(policy, rule, resource) {
+					target.errorReason = unresolved[resource]
+					prr := s.evaluate(policy, rule, target)
+					if resource.GetNamespace() == "" {
+						clusterPolicyReport.Results = append(clusterPolicyReport.Results, prr)
+						continue
+					}
+					polr, ok := policyReports[resource.GetNamespace()]
+					if !ok {
+						polr = &typepolr.PolicyReport{
+							TypeMeta:   metav1.TypeMeta{APIVersion: PolicyReportGVR.GroupVersion().String(), Kind: "PolicyReport"},
+							ObjectMeta: metav1.ObjectMeta{Name: ScanReportName, Namespace: resource.GetNamespace()},
+						}
+						policyReports[resource.GetNamespace()] = polr
+					}
+					polr.Results = append(polr.Results, prr)
+				}
+			}
+		}
+	}
+
+	namespaces := []string{}
+	for namespace := range policyReports {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+	for _, namespace := range namespaces {
+		polr := policyReports[namespace]
+		polr.Summary = summarize(polr.Results)
+		results.PolicyReportList.Items = append(results.PolicyReportList.Items, *polr)
+	}
+	if len(clusterPolicyReport.Results) > 0 {
+		clusterPolicyReport.Summary = summarize(clusterPolicyReport.Results)
+		results.ClusterPolicyReportList.Items = append(results.ClusterPolicyReportList.Items, clusterPolicyReport)
+	}
+	return &results, nil
+}
+
+// resolveScopes puts the namespaced resources without a namespace into the default namespace and removes the namespace
+// of the cluster-scoped resources by the scopes of their kinds resolved by the RESTMappers. It returns the reasons why the scopes
+// of the resources without a namespace are unknown. The resources with a namespace are namespaced if the scopes are unknown.
+func (s *Scanner) resolveScopes(resources []*unstructured.Unstructured) (map[*unstructured.Unstructured]string, error) {
+	manifestRESTMapper, err := NewManifestRESTMapper(resources)
+	if err != nil {
+		return nil, err
+	}
+	mappers := []meta.RESTMapper{}
+	if s.restMapper != nil {
+		mappers = append(mappers, s.restMapper)
+	}
+	mappers = append(mappers, manifestRESTMapper, NewBuiltinRESTMapper())
+	unresolved := map[*unstructured.Unstructured]string{}
+	for _, resource := range resources {
+		namespaced, err := isNamespaced(mappers, resource.GroupVersionKind())
+		switch {
+		case err != nil && resource.GetNamespace() == "":
+			s.logger.Warn(fmt.Sprintf("Scope of %s %s %s is unknown: %v", resource.GetAPIVersion(), resource.GetKind(), resource.GetName(), err))
+			unresolved[resource] = fmt.Sprintf("the scope of %s %s is unknown", resource.GetAPIVersion(), resource.GetKind())
+		case err != nil:
+		case namespaced && resource.GetNamespace() == "":
+			resource.SetNamespace(DefaultNamespace)
+		case !namespaced && resource.GetNamespace() != "":
+			resource.SetNamespace("")
+		}
+	}
+	return unresolved, nil
+}
+
+// loadObjects loads the Kubernetes objects from the yaml and json files in the directory. Lists are expanded into the items.
+func loadObjects(dir string) ([]*unstructured.Unstructured, error) {
+	objs := []*unstructured.Unstructured{}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if path != dir && strings.HasPrefix(info.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		ext := filepath.Ext(info.Name())
+		if ext != ".yaml" && ext != ".yml" && ext != ".json" {
+			return nil
+		}
+		loaded, err := pkg.LoadYaml(path)
+		if err != nil {
+			return fmt.Errorf("failed to load %s: %w", path, err)
+		}
+		for _, obj := range loaded {
+			if obj.IsList() {
+				list, err := obj.ToList()
+				if err != nil {
+					return fmt.Errorf("failed to load %s: %w", path, err)
+				}
+				for i := range list.Items {
+					objs = append(objs, &list.Items[i])
+				}
+				continue
+			}
+			objs = append(objs, obj)
+		}
+		return nil
+	})
+	return objs, err
+}
+
+// targets returns the targets of the rule for the resource. A Pod controller is the target of the rules matching Pods
+// by the rule autogen-<rule> (autogen-cronjob-<rule> for CronJobs) unless it is disabled by the annotation of the policy.
+func (s *Scanner) targets(policy kyvernov1.PolicyInterface, rule kyvernov1.Rule, resource *unstructured.Unstructured) []scanTarget {
+	if policy.IsNamespaced() && resource.GetNamespace() != policy.GetNamespace() {
+		return nil
+	}
+	if matched, reason := matchesRule(rule, resource, resource.GroupVersionKind()); matched != matchNo {
+		return []scanTarget{{rule: rule.Name, resource: resource, object: resource.Object, skipReason: reason}}
+	}
+	templatePath, isPodController := podControllers[resource.GetKind()]
+	if !isPodController || !autogenEnabled(policy, resource.GetKind()) {
+		return nil
+	}
+	matched, reason := matchesRule(rule, resource, corev1.SchemeGroupVersion.WithKind("Pod"))
+	if matched == matchNo {
+		return nil
+	}
+	template, found, err := unstructured.NestedMap(resource.Object, templatePath...)
+	if !found || err != nil {
+		return nil
+	}
+	metadata, _, _ := unstructured.NestedMap(template, "metadata")
+	if metadata == nil {
+		metadata = map[string]interface{}{}
+	}
+	metadata["namespace"] = resource.GetNamespace()
+	pod := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata":   metadata,
+		"spec":       template["spec"],
+	}
+	ruleName := "autogen-" + rule.Name
+	if resource.GetKind() == "CronJob" {
+		ruleName = "autogen-cronjob-" + rule.Name
+	}
+	return []scanTarget{{rule: ruleName, resource: resource, object: pod, path: "/" + strings.Join(templatePath, "/"), skipReason: reason}}
+}
+
+func autogenEnabled(policy kyvernov1.PolicyInterface, kind string) bool {
+	controllers, ok := policy.GetAnnotations()[annotationAutogenControllers]
+	if !ok {
+		return true
+	}
+	for _, controller := range strings.Split(controllers, ",") {
+		if strings.TrimSpace(controller) == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesRule decides whether the rule is applied to the resource of the kind by the match and the exclude of the rule.
+// With matchUnknown, it returns which of the match and the exclude cannot be decided by which filter.
+func matchesRule(rule kyvernov1.Rule, resource *unstructured.Unstructured, gvk schema.GroupVersionKind) (matchResult, string) {
+	matched, reason := matchesResources(rule.MatchResources, resource, gvk)
+	if matched == matchNo {
+		return matchNo, ""
+	}
+	exclude := rule.ExcludeResources
+	if len(exclude.Any) > 0 || len(exclude.All) > 0 || !exclude.ResourceDescription.IsEmpty() || !exclude.UserInfo.IsEmpty() {
+		excluded, excludeReason := matchesResources(exclude, resource, gvk)
+		switch {
+		case excluded == matchYes:
+			return matchNo, ""
+		case excluded == matchUnknown && matched == matchYes:
+			return matchUnknown, "exclude by " + excludeReason
+		}
+	}
+	if matched == matchUnknown {
+		return matchUnknown, "match by " + reason
+	}
+	return matchYes, ""
+}
+
+func matchesResources(m kyvernov1.MatchResources, resource *unstructured.Unstructured, gvk schema.GroupVersionKind) (matchResult, string) {
+	switch {
+	case len(m.Any) > 0:
+		result, reason := matchNo, ""
+		for _, filter := range m.Any {
+			matched, filterReason := matchesResourceFilter(filter, resource, gvk)
+			if matched == matchYes {
+				return matchYes, ""
+			}
+			if matched == matchUnknown && result == matchNo {
+				result, reason = matchUnknown, filterReason
+			}
+		}
+		return result, reason
+	case len(m.All) > 0:
+		result, reason := matchYes, ""
+		for _, filter := range m.All {
+			matched, filterReason := matchesResourceFilter(filter, resource, gvk)
+			if matched == matchNo {
+				return matchNo, ""
+			}
+			if matched == matchUnknown && result == matchYes {
+				result, reason = matchUnknown, filterReason
+			}
+		}
+		return result, reason
+	default:
+		return matchesResourceFilter(kyvernov1.ResourceFilter{UserInfo: m.UserInfo, ResourceDescription: m.ResourceDescription}, resource, gvk)
+	}
+}
+
+// matchesResourceFilter matches the resource with the filter. A filter by user info or namespace selector results in
+// matchUnknown with the field of the filter if the resource matches the rest of the filter.
+func matchesResourceFilter(filter kyvernov1.ResourceFilter, resource *unstructured.Unstructured, gvk schema.GroupVersionKind) (matchResult, string) {
+	if filter.ResourceDescription.IsEmpty() && filter.UserInfo.IsEmpty() {
+		return matchNo, ""
+	}
+	if !matchesResourceDescription(filter.ResourceDescription, resource, gvk) {
+		return matchNo, ""
+	}
+	switch {
+	case filter.NamespaceSelector != nil:
+		return matchUnknown, "namespaceSelector"
+	case !filter.UserInfo.IsEmpty():
+		return matchUnknown, "userInfo"
+	}
+	return matchYes, ""
+}
+
+// matchesResourceDescription matches the resource with the resource description except for the namespace selector
+func matchesResourceDescription(rd kyvernov1.ResourceDescription, resource *unstructured.Unstructured, gvk schema.GroupVersionKind) bool {
+	if len(rd.Kinds) > 0 && !matchesKinds(rd.Kinds, gvk) {
+		return false
+	}
+	if rd.Name != "" && !matchWildcard(resource.GetName(), rd.Name) {
+		return false
+	}
+	if len(rd.Names) > 0 && !matchesAnyWildcard(resource.GetName(), rd.Names) {
+		return false
+	}
+	if len(rd.Namespaces) > 0 {
+		namespace := resource.GetNamespace()
+		if gvk.Kind == "Namespace" {
+			namespace = resource.GetName()
+		}
+		if !matchesAnyWildcard(namespace, rd.Namespaces) {
+			return false
+		}
+	}
+	for key, value := range rd.Annotations {
+		if !matchWildcard(resource.GetAnnotations()[key], value) {
+			return false
+		}
+	}
+	if rd.Selector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(rd.Selector)
+		if err != nil || !selector.Matches(labels.Set(resource.GetLabels())) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesKinds matches the kind with the kinds of the form Kind, Group/Version/Kind, Version/Kind or with wildcards
+func matchesKinds(kinds []string, gvk schema.GroupVersionKind) bool {
+	for _, kind := range kinds {
+		elements := strings.Split(kind, "/")
+		var group, version string
+		switch len(elements) {
+		case 1:
+			group, version = "*", "*"
+		case 2:
+			group, version = "*", elements[0]
+		default:
+			group, version = strings.Join(elements[:len(elements)-2], "/"), elements[len(elements)-2]
+		}
+		k := elements[len(elements)-1]
+		if matchWildcard(gvk.Group, group) && matchWildcard(gvk.Version, version) && matchWildcard(gvk.Kind, k) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAnyWildcard(text string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matchWildcard(text, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// evaluate evaluates the validate rule against the target and makes the result
+func (s *Scanner) evaluate(policy kyvernov1.PolicyInterface, rule kyvernov1.Rule, target scanTarget) typepolr.PolicyReportResult {
+	resource := target.resource
+	uid := string(resource.GetUID())
+	if uid == "" {
+		uid = s.stamper.UUID("resource", resource.GetAPIVersion(), resource.GetKind(), resource.GetNamespace(), resource.GetName())
+	}
+	annotations := policy.GetAnnotations()
+	// Kyverno writes the namespaced Policies as <namespace>/<name>
+	policyName := policy.GetName()
+	if policy.IsNamespaced() {
+		policyName = policy.GetNamespace() + "/" + policy.GetName()
+	}
+	prr := typepolr.PolicyReportResult{
+		Source:    "kyverno",
+		Policy:    policyName,
+		Rule:      target.rule,
+		Category:  annotations["policies.kyverno.io/category"],
+		Severity:  typepolr.PolicyResultSeverity(annotations["policies.kyverno.io/severity"]),
+		Scored:    annotations["policies.kyverno.io/scored"] != "false",
+		Timestamp: s.timestamp,
+		Subjects: []corev1.ObjectReference{{
+			APIVersion: resource.GetAPIVersion(),
+			Kind:       resource.GetKind(),
+			Namespace:  resource.GetNamespace(),
+			Name:       resource.GetName(),
+			UID:        types.UID(uid),
+		}},
+	}
+	if target.errorReason != "" {
+		prr.Result = "error"
+		prr.Description = fmt.Sprintf("rule %s is not evaluated because %s", target.rule, target.errorReason)
+		return prr
+	}
+	if target.skipReason != "" {
+		prr.Result = "skip"
+		prr.Description = fmt.Sprintf("rule %s is not evaluated because %s cannot be decided without a cluster", target.rule, target.skipReason)
+		return prr
+	}
+	result, message := s.validate(rule, target)
+	prr.Result = result
+	prr.Description = message
+	return prr
+}
+
+func (s *Scanner) validate(rule kyvernov1.Rule, target scanTarget) (typepolr.PolicyResult, string) {
+	validation := rule.Validation
+	switch {
+	case rule.HasVerifyImages():
+		return "error", errUnsupported{feature: "verifyImages"}.Error()
+	case len(rule.Context) > 0:
+		return "error", errUnsupported{feature: "context"}.Error()
+	case len(rule.CELPreconditions) > 0:
+		return "error", errUnsupported{feature: "celPreconditions"}.Error()
+	case len(validation.ForEachValidation) > 0:
+		return "error", errUnsupported{feature: "foreach"}.Error()
+	case validation.PodSecurity != nil:
+		return "error", errUnsupported{feature: "podSecurity"}.Error()
+	case validation.CEL != nil:
+		return "error", errUnsupported{feature: "cel"}.Error()
+	case validation.Manifests != nil:
+		return "error", errUnsupported{feature: "manifests"}.Error()
+	}
+
+	variables := map[string]interface{}{
+		"request": map[string]interface{}{
+			"operation": "CREATE",
+			"object":    target.object,
+			"namespace": target.resource.GetNamespace(),
+			"name":      target.resource.GetName(),
+		},
+	}
+	preconditions, err := decodeRawJSON(rule.RawAnyAllConditions)
+	if err != nil {
+		return "error", err.Error()
+	}
+	if ok, err := evaluateConditions(variables, preconditions); err != nil {
+		return "error", err.Error()
+	} else if !ok {
+		return "skip", "preconditions not met"
+	}
+
+	message := validation.Message
+	if substituted, err := substituteVariables(variables, message); err == nil {
+		message = toString(substituted)
+	}
+
+	if validation.Deny != nil {
+		conditions, err := decodeRawJSON(validation.Deny.RawAnyAllConditions)
+		if err != nil {
+			return "error", err.Error()
+		}
+		denied, err := evaluateConditions(variables, conditions)
+		if err != nil {
+			return "error", err.Error()
+		}
+		if denied {
+			return "fail", message
+		}
+		return "pass", fmt.Sprintf("validation rule '%s' passed.", target.rule)
+	}
+
+	patterns := []interface{}{}
+	if validation.RawPattern != nil {
+		pattern, err := decodeRawJSON(validation.RawPattern)
+		if err != nil {
+			return "error", err.Error()
+		}
+		patterns = append(patterns, pattern)
+	} else if validation.RawAnyPattern != nil {
+		anyPattern, err := decodeRawJSON(validation.RawAnyPattern)
+		if err != nil {
+			return "error", err.Error()
+		}
+		anyPatterns, ok := anyPattern.([]interface{})
+		if !ok {
+			return "error", "anyPattern must be a list of patterns"
+		}
+		patterns = anyPatterns
+	} else {
+		return "error", fmt.Sprintf("rule %s has no pattern, anyPattern or deny", rule.Name)
+	}
+
+	failures := []string{}
+	skipped := 0
+	for i, pattern := range patterns {
+		mismatch := matchPattern(target.object, pattern, target.path+"/")
+		switch mismatch.result {
+		case patternMatched:
+			return "pass", fmt.Sprintf("validation rule '%s' passed.", target.rule)
+		case patternSkipped:
+			skipped++
+		default:
+			name := target.rule
+			if validation.RawAnyPattern != nil {
+				name = fmt.Sprintf("%s[%d]", target.rule, i)
+			}
+			failures = append(failures, fmt.Sprintf("rule %s failed at path %s", name, mismatch.path))
+		}
+	}
+	if skipped == len(patterns) {
+		return "skip", "conditional anchor mismatch"
+	}
+	return "fail", fmt.Sprintf("validation error: %s. %s", message, strings.Join(failures, " "))
+}
+
+func summarize(results []typepolr.PolicyReportResult) typepolr.PolicyReportSummary {
+	summary := typepolr.PolicyReportSummary{}
+	for _, result := range results {
+		switch result.Result {
+		case "pass":
+			summary.Pass++
+		case "fail":
+			summary.Fail++
+		case "warn":
+			summary.Warn++
+		case "error":
+			summary.Error++
+		case "skip":
+			summary.Skip++
+		}
+	}
+	return summary
+}
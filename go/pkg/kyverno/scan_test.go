@@ -0,0 +1,243 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kyverno
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/oscal-compass/compliance-to-policy/go/pkg"
+	typec2pcr "github.com/oscal-compass/compliance-to-policy/go/pkg/types/c2pcr"
+	"github.com/stretchr/testify/assert"
+	typepolr "sigs.k8s.io/wg-policy-prototypes/policy-report/pkg/api/wgpolicyk8s.io/v1beta1"
+)
+
+func TestMatchPattern(t *testing.T) {
+	pod := map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "pod", "labels": map[string]interface{}{"app": "web"}},
+		"spec": map[string]interface{}{
+			"hostNetwork": false,
+			"containers": []interface{}{
+				map[string]interface{}{"name": "web", "image": "nginx:1.25", "resources": map[string]interface{}{"limits": map[string]interface{}{"memory": "256Mi"}}},
+				map[string]interface{}{"name": "sidecar", "image": "envoy:latest"},
+			},
+		},
+	}
+	tests := []struct {
+		name    string
+		pattern interface{}
+		result  patternResult
+		path    string
+	}{
+		{"wildcard", map[string]interface{}{"metadata": map[string]interface{}{"labels": map[string]interface{}{"app": "?*"}}}, patternMatched, ""},
+		{"missing field", map[string]interface{}{"metadata": map[string]interface{}{"labels": map[string]interface{}{"team": "?*"}}}, patternFailed, "/metadata/labels/team/"},
+		{"every element", map[string]interface{}{"spec": map[string]interface{}{"containers": []interface{}{map[string]interface{}{"image": "!*:latest"}}}}, patternFailed, "/spec/containers/1/image/"},
+		{"alternatives", map[string]interface{}{"spec": map[string]interface{}{"containers": []interface{}{map[string]interface{}{"image": "nginx:*|envoy:*"}}}}, patternMatched, ""},
+		{"boolean", map[string]interface{}{"spec": map[string]interface{}{"hostNetwork": false}}, patternMatched, ""},
+		{"equality anchor", map[string]interface{}{"spec": map[string]interface{}{"=(hostPID)": true}}, patternMatched, ""},
+		{"negation anchor", map[string]interface{}{"spec": map[string]interface{}{"X(hostNetwork)": "null"}}, patternFailed, "/spec/hostNetwork/"},
+		{"quantity", map[string]interface{}{"spec": map[string]interface{}{"containers": []interface{}{map[string]interface{}{"(name)": "web", "resources": map[string]interface{}{"limits": map[string]interface{}{"memory": "<=512Mi"}}}}}}, patternMatched, ""},
+		{"conditional anchor", map[string]interface{}{"spec": map[string]interface{}{"containers": []interface{}{map[string]interface{}{"(name)": "sidecar", "image": "envoy:1.*"}}}}, patternFailed, "/spec/containers/1/image/"},
+		{"conditional anchor mismatch", map[string]interface{}{"(metadata)": map[string]interface{}{"name": "other"}, "spec": map[string]interface{}{"hostNetwork": true}}, patternSkipped, "/metadata/"},
+		{"existence anchor", map[string]interface{}{"spec": map[string]interface{}{"<(containers)": []interface{}{map[string]interface{}{"image": "envoy:*"}}}}, patternMatched, ""},
+	}
+	for _, test := range tests {
+		mismatch := matchPattern(pod, test.pattern, "/")
+		assert.Equal(t, test.result, mismatch.result, test.name)
+		assert.Equal(t, test.path, mismatch.path, test.name)
+	}
+}
+
+func TestEvaluateConditions(t *testing.T) {
+	variables := map[string]interface{}{
+		"request": map[string]interface{}{
+			"object": map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "web", "labels": map[string]interface{}{"app.kubernetes.io/name": "web"}},
+				"spec":     map[string]interface{}{"replicas": int64(2), "ports": []interface{}{int64(80), int64(443)}},
+			},
+		},
+	}
+	tests := []struct {
+		conditions interface{}
+		expected   bool
+	}{
+		{[]interface{}{map[string]interface{}{"key": "{{ request.object.spec.replicas }}", "operator": "LessThan", "value": "3"}}, true},
+		{[]interface{}{map[string]interface{}{"key": "{{ request.object.spec.replicas }}", "operator": "GreaterThanOrEquals", "value": int64(3)}}, false},
+		{[]interface{}{map[string]interface{}{"key": "{{ request.object.metadata.name }}", "operator": "Equals", "value": "w*"}}, true},
+		{[]interface{}{map[string]interface{}{"key": `{{ request.object.metadata.labels."app.kubernetes.io/name" }}`, "operator": "Equals", "value": "web"}}, true},
+		{[]interface{}{map[string]interface{}{"key": "{{ request.object.spec.ports }}", "operator": "AllIn", "value": []interface{}{int64(80), int64(443), int64(8080)}}}, true},
+		{[]interface{}{map[string]interface{}{"key": "{{ request.object.spec.ports[1] }}", "operator": "NotIn", "value": []interface{}{int64(80)}}}, true},
+		{map[string]interface{}{
+			"any": []interface{}{map[string]interface{}{"key": "a", "operator": "Equals", "value": "b"}, map[string]interface{}{"key": "a", "operator": "Equals", "value": "a"}},
+			"all": []interface{}{map[string]interface{}{"key": "{{ request.object.metadata.name }}", "operator": "NotEquals", "value": "db"}},
+		}, true},
+	}
+	for i, test := range tests {
+		actual, err := evaluateConditions(variables, test.conditions)
+		assert.NoError(t, err, fmt.Sprintf("conditions %d", i))
+		assert.Equal(t, test.expected, actual, fmt.Sprintf("conditions %d", i))
+	}
+
+	_, err := evaluateConditions(variables, []interface{}{map[string]interface{}{"key": "{{ request.object.spec.containers[].image }}", "operator": "AnyIn", "value": "nginx"}})
+	assert.Error(t, err)
+}
+
+func collectScanResults(policyResults *PolicyResults) map[string]typepolr.PolicyReportResult {
+	results := map[string]typepolr.PolicyReportResult{}
+	collect := func(prrs []typepolr.PolicyReportResult) {
+		for _, prr := range prrs {
+			subject := prr.Subjects[0]
+			results[fmt.Sprintf("%s/%s/%s/%s", prr.Rule, subject.Kind, subject.Namespace, subject.Name)] = prr
+		}
+	}
+	for _, polr := range policyResults.PolicyReportList.Items {
+		collect(polr.Results)
+	}
+	for _, cpolr := range policyResults.ClusterPolicyReportList.Items {
+		collect(cpolr.Results)
+	}
+	return results
+}
+
+func TestScanner(t *testing.T) {
+	scanner := NewScanner(pkg.PathFromPkgDirectory("./testdata/kyverno/scan/policies"), pkg.PathFromPkgDirectory("./testdata/kyverno/scan/manifests"))
+	scanner.SetTimestamp(time.Unix(1700000000, 0))
+	policyResults, err := scanner.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, 4, len(policyResults.ClusterPolicyList.Items))
+	assert.Equal(t, []string{"app", "default"}, []string{policyResults.PolicyReportList.Items[0].Namespace, policyResults.PolicyReportList.Items[1].Namespace})
+	assert.Equal(t, 1, len(policyResults.ClusterPolicyReportList.Items))
+
+	results := collectScanResults(policyResults)
+	expected := map[string]typepolr.PolicyResult{
+		"require-image-tag/Pod/default/debug":                    "fail",
+		"autogen-require-image-tag/Deployment/app/web":           "pass",
+		"autogen-require-image-tag/Deployment/app/worker":        "pass",
+		"autogen-cronjob-require-image-tag/CronJob/app/backup":   "pass",
+		"validate-image-tag/Pod/default/debug":                   "pass",
+		"autogen-validate-image-tag/Deployment/app/web":          "pass",
+		"autogen-validate-image-tag/Deployment/app/worker":       "fail",
+		"autogen-cronjob-validate-image-tag/CronJob/app/backup":  "pass",
+		"require-owner-label/Namespace//app":                     "pass",
+		"require-owner-label/Namespace//sandbox":                 "fail",
+		"require-owner-label/Namespace//kube-system":             "skip",
+		"validate-registries/Pod/default/debug":                  "error",
+		"autogen-validate-registries/Deployment/app/web":         "error",
+		"autogen-validate-registries/Deployment/app/worker":      "error",
+		"autogen-cronjob-validate-registries/CronJob/app/backup": "error",
+		"run-as-non-root/Pod/default/debug":                      "skip",
+		"autogen-run-as-non-root/Deployment/app/web":             "skip",
+		"autogen-run-as-non-root/Deployment/app/worker":          "skip",
+		"require-owner-on-create/Namespace//sandbox":             "skip",
+	}
+	actual := map[string]typepolr.PolicyResult{}
+	for key, prr := range results {
+		actual[key] = prr.Result
+	}
+	assert.Equal(t, expected, actual)
+
+	worker := results["autogen-validate-image-tag/Deployment/app/worker"]
+	assert.Equal(t, "disallow-latest-tag", worker.Policy)
+	assert.Equal(t, "Best Practices", worker.Category)
+	assert.Equal(t, typepolr.PolicyResultSeverity("medium"), worker.Severity)
+	assert.True(t, worker.Scored)
+	assert.Equal(t, int64(1700000000), worker.Timestamp.Seconds)
+	assert.Equal(t, "validation error: Using a mutable image tag e.g. 'latest' is not allowed.. rule autogen-validate-image-tag failed at path /spec/template/spec/containers/0/image/", worker.Description)
+	assert.NotEmpty(t, worker.Subjects[0].UID)
+
+	sandbox := results["require-owner-label/Namespace//sandbox"]
+	assert.Equal(t, "Namespace sandbox must have the owner label.", sandbox.Description)
+	assert.False(t, sandbox.Scored)
+	assert.Contains(t, results["validate-registries/Pod/default/debug"].Description, "context is not supported")
+	assert.Equal(t, "rule autogen-run-as-non-root is not evaluated because match by namespaceSelector cannot be decided without a cluster", results["autogen-run-as-non-root/Deployment/app/web"].Description)
+	assert.Equal(t, "rule require-owner-on-create is not evaluated because exclude by userInfo cannot be decided without a cluster", results["require-owner-on-create/Namespace//sandbox"].Description)
+}
+
+func TestScannerWithScopesOfKinds(t *testing.T) {
+	scanner := NewScanner(pkg.PathFromPkgDirectory("./testdata/kyverno/scan-scopes/policies"), pkg.PathFromPkgDirectory("./testdata/kyverno/scan-scopes/manifests"))
+	policyResults, err := scanner.Load()
+	assert.NoError(t, err)
+
+	results := collectScanResults(policyResults)
+	actual := map[string]typepolr.PolicyResult{}
+	for key, prr := range results {
+		actual[key] = prr.Result
+	}
+	assert.Equal(t, map[string]typepolr.PolicyResult{
+		"require-owner-label/CustomResourceDefinition//widgets.example.com": "pass",
+		// The scope of Widget is given by the CustomResourceDefinition of the manifests
+		"require-owner-label/Widget//cluster-widget": "fail",
+		// The scope of Gadget is unknown without a namespace
+		"require-owner-label/Gadget//unknown-gadget": "error",
+		"require-owner-label/Gadget/app/app-gadget":  "pass",
+		// ConfigMap is namespaced and ClusterRole is cluster-scoped by the kinds built into the API server
+		"require-owner-label/ConfigMap/default/settings": "pass",
+		"require-owner-label/ClusterRole//reader":        "pass",
+		"deny-by-user/ConfigMap/default/settings":        "error",
+		"verify-images/ConfigMap/default/settings":       "error",
+	}, actual)
+	assert.Equal(t, "rule require-owner-label is not evaluated because the scope of example.com/v1 Gadget is unknown", results["require-owner-label/Gadget//unknown-gadget"].Description)
+	assert.Equal(t, "variable 'request.userInfo.username' is not supported by the offline scan", results["deny-by-user/ConfigMap/default/settings"].Description)
+	assert.Equal(t, "verifyImages is not supported by the offline scan", results["verify-images/ConfigMap/default/settings"].Description)
+}
+
+func TestResultToOscalWithScanner(t *testing.T) {
+	tempDirPath := pkg.PathFromPkgDirectory("./testdata/_test")
+	err := os.MkdirAll(tempDirPath, os.ModePerm)
+	assert.NoError(t, err, "Should not happen")
+
+	c2pcrSpec := typec2pcr.Spec{
+		Compliance: typec2pcr.Compliance{
+			Name: "Test Compliance",
+			ComponentDefinition: typec2pcr.ResourceRef{
+				Url: pkg.PathFromPkgDirectory("./testdata/kyverno/parameterized/component-definition.json"),
+			},
+		},
+		PolicyResources: typec2pcr.ResourceRef{
+			Url: pkg.PathFromPkgDirectory("./testdata/kyverno/parameterized/policy-resources"),
+		},
+	}
+	c2pcrParser := NewParser(pkg.NewGitUtils(pkg.NewTempDirectory(tempDirPath)))
+	c2pcrParsed, err := c2pcrParser.Parse(c2pcrSpec)
+	assert.NoError(t, err, "Should not happen")
+
+	policiesDir := pkg.NewTempDirectory(tempDirPath)
+	err = NewOscal2Policy(c2pcrParsed.PolicyResoureDir, policiesDir).Generate(c2pcrParsed)
+	assert.NoError(t, err, "Should not happen")
+
+	r := NewResultToOscal(c2pcrParsed, "")
+	r.SetResultsSource(NewScanner(policiesDir.GetTempDir(), pkg.PathFromPkgDirectory("./testdata/kyverno/scan/manifests")))
+	r.SetDeterministic(true)
+	arRoot, err := r.GenerateAssessmentResults()
+	assert.NoError(t, err, "Should not happen")
+
+	result := arRoot.AssessmentResults.Results[0]
+	assert.Equal(t, 1, len(result.Observations))
+	subjects := map[string]string{}
+	for _, subject := range result.Observations[0].Subjects {
+		subjects[subject.Title] = findProp(subject.Props, "result")
+	}
+	// The minimum replicas is 3 by the set-parameters of the component-definition
+	assert.Equal(t, map[string]string{
+		"ApiVersion: apps/v1, Kind: Deployment, Namespace: app, Name: web":    "pass",
+		"ApiVersion: apps/v1, Kind: Deployment, Namespace: app, Name: worker": "fail",
+	}, subjects)
+	assert.Equal(t, 1, len(result.Findings))
+	assert.Equal(t, "not-satisfied", result.Findings[0].Target.Status.State)
+}
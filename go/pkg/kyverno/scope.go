@@ -0,0 +1,205 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kyverno
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal"
+	"gopkg.in/yaml.v3"
+)
+
+// Enforcement modes of the rules given by Enforcement_Mode, which are the validationFailureAction of the policies
+var enforcementModes = map[string]string{
+	"audit":   "Audit",
+	"enforce": "Enforce",
+}
+
+// applyScope patches the Kyverno policies in the yaml files of the directory:
+// validationFailureAction by the enforcement mode, the namespaces of the match of the rules by the target namespaces,
+// and the exclude of the rules by the excluded namespaces.
+func applyScope(dir string, policyName string, scope oscal.PolicyScope) error {
+	if scope.IsEmpty() {
+		return nil
+	}
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !(strings.HasSuffix(info.Name(), ".yaml") || strings.HasSuffix(info.Name(), ".yml")) {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		patched, changed, err := patchPolicies(data, scope)
+		if err != nil {
+			return fmt.Errorf("failed to apply Enforcement_Mode, Target_Namespaces or Excluded_Namespaces of the rules of policy %s to %s: %w", policyName, path, err)
+		}
+		if !changed {
+			return nil
+		}
+		return os.WriteFile(path, patched, info.Mode())
+	})
+}
+
+func patchPolicies(data []byte, scope oscal.PolicyScope) ([]byte, bool, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	buffer := bytes.NewBuffer([]byte{})
+	encoder := yaml.NewEncoder(buffer)
+	encoder.SetIndent(2)
+	changed := false
+	for {
+		var node yaml.Node
+		err := decoder.Decode(&node)
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, false, err
+		}
+		if len(node.Content) > 0 && isPolicyNode(node.Content[0]) {
+			if err := patchPolicy(node.Content[0], scope); err != nil {
+				return nil, false, err
+			}
+			changed = true
+		}
+		if err := encoder.Encode(&node); err != nil {
+			return nil, false, err
+		}
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, false, err
+	}
+	return buffer.Bytes(), changed, nil
+}
+
+func isPolicyNode(node *yaml.Node) bool {
+	apiVersion, kind := mappingValue(node, "apiVersion"), mappingValue(node, "kind")
+	if apiVersion == nil || kind == nil || !strings.HasPrefix(apiVersion.Value, "kyverno.io/") {
+		return false
+	}
+	return kind.Value == "ClusterPolicy" || kind.Value == "Policy"
+}
+
+func patchPolicy(policy *yaml.Node, scope oscal.PolicyScope) error {
+	spec := ensureMapping(policy, "spec")
+	if scope.EnforcementMode != "" {
+		setMappingValue(spec, "validationFailureAction", scalarNode(enforcementModes[scope.EnforcementMode]))
+	}
+	rules := mappingValue(spec, "rules")
+	if rules == nil || rules.Kind != yaml.SequenceNode {
+		return nil
+	}
+	for _, rule := range rules.Content {
+		if len(scope.TargetNamespaces) > 0 {
+			restrictNamespaces(ensureMapping(rule, "match"), scope.TargetNamespaces)
+		}
+		if len(scope.ExcludedNamespaces) > 0 {
+			if err := excludeNamespaces(ensureMapping(rule, "exclude"), scope.ExcludedNamespaces); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// restrictNamespaces sets the namespaces of the resource filters of the match
+func restrictNamespaces(match *yaml.Node, namespaces []string) {
+	filters := mappingValue(match, "any")
+	if filters == nil {
+		filters = mappingValue(match, "all")
+	}
+	if filters == nil || filters.Kind != yaml.SequenceNode {
+		setMappingValue(ensureMapping(match, "resources"), "namespaces", sequenceNode(namespaces))
+		return
+	}
+	for _, filter := range filters.Content {
+		setMappingValue(ensureMapping(filter, "resources"), "namespaces", sequenceNode(namespaces))
+	}
+}
+
+// excludeNamespaces adds a resource filter of the namespaces to the any of the exclude.
+// The exclude of the form without any and all is moved into the any.
+func excludeNamespaces(exclude *yaml.Node, namespaces []string) error {
+	if mappingValue(exclude, "all") != nil {
+		return errors.New("namespaces cannot be excluded from the rule excluding by 'all'")
+	}
+	filter := &yaml.Node{Kind: yaml.MappingNode}
+	setMappingValue(ensureMapping(filter, "resources"), "namespaces", sequenceNode(namespaces))
+	filters := mappingValue(exclude, "any")
+	if filters == nil {
+		filters = &yaml.Node{Kind: yaml.SequenceNode}
+		if len(exclude.Content) > 0 {
+			filters.Content = append(filters.Content, &yaml.Node{Kind: yaml.MappingNode, Content: exclude.Content})
+		}
+		exclude.Content = nil
+		setMappingValue(exclude, "any", filters)
+	}
+	filters.Content = append(filters.Content, filter)
+	return nil
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func setMappingValue(node *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content[i+1] = value
+			return
+		}
+	}
+	node.Content = append(node.Content, scalarNode(key), value)
+}
+
+// ensureMapping returns the mapping of the key, which is added if the node does not have it
+func ensureMapping(node *yaml.Node, key string) *yaml.Node {
+	value := mappingValue(node, key)
+	if value == nil || value.Kind != yaml.MappingNode {
+		value = &yaml.Node{Kind: yaml.MappingNode}
+		setMappingValue(node, key, value)
+	}
+	return value
+}
+
+func scalarNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+func sequenceNode(values []string) *yaml.Node {
+	node := &yaml.Node{Kind: yaml.SequenceNode}
+	for _, value := range values {
+		node.Content = append(node.Content, scalarNode(value))
+	}
+	return node
+}
@@ -0,0 +1,593 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kyverno
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// This file implements the subset of the validate rules of Kyverno evaluated by the offline scan:
+// pattern and anyPattern with the anchors and the operators of the values, and the conditions of deny and preconditions
+// whose keys and values refer to the resource by variables such as {{ request.object.metadata.name }}.
+
+type patternResult int
+
+const (
+	patternMatched patternResult = iota
+	patternFailed
+	// A conditional anchor did not match so that the pattern is not applicable
+	patternSkipped
+)
+
+// patternMismatch is the result of matching a resource with a pattern
+type patternMismatch struct {
+	result patternResult
+	// Path of the resource where the pattern failed
+	path string
+	// Skip the whole rule (global anchor)
+	global bool
+}
+
+var matched = patternMismatch{result: patternMatched}
+
+// errUnsupported is returned for the features that are not evaluated by the offline scan
+type errUnsupported struct {
+	feature string
+}
+
+func (e errUnsupported) Error() string {
+	return fmt.Sprintf("%s is not supported by the offline scan", e.feature)
+}
+
+func decodeRawJSON(raw *apiextv1.JSON) (interface{}, error) {
+	if raw == nil || len(raw.Raw) == 0 {
+		return nil, nil
+	}
+	var out interface{}
+	if err := json.Unmarshal(raw.Raw, &out); err != nil {
+		return nil, err
+	}
+	return normalizeJSON(out), nil
+}
+
+// normalizeJSON converts the numbers to int64 or float64 as unstructured objects do
+func normalizeJSON(in interface{}) interface{} {
+	switch typed := in.(type) {
+	case map[string]interface{}:
+		for k, v := range typed {
+			typed[k] = normalizeJSON(v)
+		}
+		return typed
+	case []interface{}:
+		for i, v := range typed {
+			typed[i] = normalizeJSON(v)
+		}
+		return typed
+	case float64:
+		if typed == float64(int64(typed)) {
+			return int64(typed)
+		}
+		return typed
+	default:
+		return in
+	}
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+type anchor int
+
+const (
+	anchorNone anchor = iota
+	anchorConditional
+	anchorGlobal
+	anchorEquality
+	anchorNegation
+	anchorExistence
+	anchorAddIfNotPresent
+)
+
+var anchorRegexp = regexp.MustCompile(`^(\^|=|X|<|\+)?\((.+)\)$`)
+
+func parseAnchor(key string) (anchor, string) {
+	matches := anchorRegexp.FindStringSubmatch(key)
+	if matches == nil {
+		return anchorNone, key
+	}
+	switch matches[1] {
+	case "^":
+		return anchorGlobal, matches[2]
+	case "=":
+		return anchorEquality, matches[2]
+	case "X":
+		return anchorNegation, matches[2]
+	case "<":
+		return anchorExistence, matches[2]
+	case "+":
+		return anchorAddIfNotPresent, matches[2]
+	default:
+		return anchorConditional, matches[2]
+	}
+}
+
+// matchPattern matches the resource with the pattern of a validate rule
+func matchPattern(resource interface{}, pattern interface{}, path string) patternMismatch {
+	switch typedPattern := pattern.(type) {
+	case map[string]interface{}:
+		return matchMapPattern(resource, typedPattern, path)
+	case []interface{}:
+		return matchArrayPattern(resource, typedPattern, path)
+	default:
+		if matchValue(resource, pattern) {
+			return matched
+		}
+		return patternMismatch{result: patternFailed, path: path}
+	}
+}
+
+func matchMapPattern(resource interface{}, pattern map[string]interface{}, path string) patternMismatch {
+	resourceMap, ok := resource.(map[string]interface{})
+	if !ok {
+		return patternMismatch{result: patternFailed, path: path}
+	}
+	// Conditional and global anchors are evaluated first since they decide whether the pattern is applicable
+	for _, key := range sortedKeys(pattern) {
+		a, name := parseAnchor(key)
+		if a != anchorConditional && a != anchorGlobal {
+			continue
+		}
+		value, exists := resourceMap[name]
+		if !exists || matchPattern(value, pattern[key], path+name+"/").result != patternMatched {
+			return patternMismatch{result: patternSkipped, path: path + name + "/", global: a == anchorGlobal}
+		}
+	}
+	for _, key := range sortedKeys(pattern) {
+		a, name := parseAnchor(key)
+		value, exists := resourceMap[name]
+		elementPath := path + name + "/"
+		switch a {
+		case anchorConditional, anchorGlobal, anchorAddIfNotPresent:
+			continue
+		case anchorEquality:
+			if !exists {
+				continue
+			}
+		case anchorNegation:
+			if exists {
+				return patternMismatch{result: patternFailed, path: elementPath}
+			}
+			continue
+		case anchorExistence:
+			if mismatch := matchExistence(value, pattern[key], elementPath); mismatch.result != patternMatched {
+				return mismatch
+			}
+			continue
+		}
+		if mismatch := matchPattern(value, pattern[key], elementPath); mismatch.result != patternMatched {
+			return mismatch
+		}
+	}
+	return matched
+}
+
+func matchArrayPattern(resource interface{}, pattern []interface{}, path string) patternMismatch {
+	resourceArray, ok := resource.([]interface{})
+	if !ok {
+		return patternMismatch{result: patternFailed, path: path}
+	}
+	if len(pattern) == 1 {
+		if _, ok := pattern[0].(map[string]interface{}); ok {
+			// Every element must match the pattern unless a conditional anchor skips the element
+			for i, element := range resourceArray {
+				mismatch := matchPattern(element, pattern[0], fmt.Sprintf("%s%d/", path, i))
+				if mismatch.result == patternFailed || (mismatch.result == patternSkipped && mismatch.global) {
+					return mismatch
+				}
+			}
+			return matched
+		}
+	}
+	if len(pattern) > len(resourceArray) {
+		return patternMismatch{result: patternFailed, path: path}
+	}
+	for i, element := range pattern {
+		if mismatch := matchPattern(resourceArray[i], element, fmt.Sprintf("%s%d/", path, i)); mismatch.result != patternMatched {
+			return mismatch
+		}
+	}
+	return matched
+}
+
+// matchExistence requires at least one element of the array to match the pattern
+func matchExistence(resource interface{}, pattern interface{}, path string) patternMismatch {
+	resourceArray, ok := resource.([]interface{})
+	patternArray, isArray := pattern.([]interface{})
+	if !ok || !isArray || len(patternArray) != 1 {
+		return patternMismatch{result: patternFailed, path: path}
+	}
+	for i, element := range resourceArray {
+		if matchPattern(element, patternArray[0], fmt.Sprintf("%s%d/", path, i)).result == patternMatched {
+			return matched
+		}
+	}
+	return patternMismatch{result: patternFailed, path: path}
+}
+
+// matchValue matches a scalar value with the pattern. String patterns are the alternatives separated by '|' of the conditions separated by '&',
+// and a condition is a wildcard or a comparison by the operators (!, >, >=, <, <=, and ranges such as 1-10 or 1!-10).
+func matchValue(value interface{}, pattern interface{}) bool {
+	switch typedPattern := pattern.(type) {
+	case nil:
+		return value == nil || value == "" || value == int64(0) || value == false
+	case bool:
+		typedValue, ok := value.(bool)
+		return ok && typedValue == typedPattern
+	case int64, float64:
+		number, ok := toFloat(value)
+		patternNumber, _ := toFloat(typedPattern)
+		return ok && number == patternNumber
+	case string:
+		for _, alternative := range strings.Split(typedPattern, "|") {
+			allMatched := true
+			for _, condition := range strings.Split(alternative, "&") {
+				if !matchCondition(value, strings.TrimSpace(condition)) {
+					allMatched = false
+					break
+				}
+			}
+			if allMatched {
+				return true
+			}
+		}
+		return false
+	default:
+		return false
+	}
+}
+
+var rangeRegexp = regexp.MustCompile(`^([^!<>=]+?)(!?-)([^!<>=]+)$`)
+
+func matchCondition(value interface{}, condition string) bool {
+	for _, operator := range []string{">=", "<=", ">", "<"} {
+		if strings.HasPrefix(condition, operator) {
+			cmp, ok := compareValues(value, strings.TrimSpace(strings.TrimPrefix(condition, operator)))
+			if !ok {
+				return false
+			}
+			switch operator {
+			case ">=":
+				return cmp >= 0
+			case "<=":
+				return cmp <= 0
+			case ">":
+				return cmp > 0
+			default:
+				return cmp < 0
+			}
+		}
+	}
+	if strings.HasPrefix(condition, "!") {
+		return !matchWildcard(toString(value), strings.TrimPrefix(condition, "!"))
+	}
+	if matches := rangeRegexp.FindStringSubmatch(condition); matches != nil {
+		lower, lowerOk := compareValues(value, strings.TrimSpace(matches[1]))
+		upper, upperOk := compareValues(value, strings.TrimSpace(matches[3]))
+		if lowerOk && upperOk {
+			inRange := lower >= 0 && upper <= 0
+			if matches[2] == "!-" {
+				return !inRange
+			}
+			return inRange
+		}
+	}
+	return matchWildcard(toString(value), condition)
+}
+
+// compareValues compares the value with the operand as numbers, quantities or durations
+func compareValues(value interface{}, operand string) (int, bool) {
+	if number, ok := toFloat(value); ok {
+		if operandNumber, err := strconv.ParseFloat(operand, 64); err == nil {
+			return compareFloats(number, operandNumber), true
+		}
+	}
+	valueString := toString(value)
+	if valueQuantity, err := resource.ParseQuantity(valueString); err == nil {
+		if operandQuantity, err := resource.ParseQuantity(operand); err == nil {
+			return valueQuantity.Cmp(operandQuantity), true
+		}
+	}
+	if valueDuration, err := time.ParseDuration(valueString); err == nil {
+		if operandDuration, err := time.ParseDuration(operand); err == nil {
+			return compareFloats(float64(valueDuration), float64(operandDuration)), true
+		}
+	}
+	return 0, false
+}
+
+func compareFloats(a float64, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch typed := value.(type) {
+	case int64:
+		return float64(typed), true
+	case int:
+		return float64(typed), true
+	case float64:
+		return typed, true
+	case string:
+		number, err := strconv.ParseFloat(typed, 64)
+		return number, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func toString(value interface{}) string {
+	switch typed := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return typed
+	case float64:
+		return strconv.FormatFloat(typed, 'f', -1, 64)
+	default:
+		return fmt.Sprintf("%v", typed)
+	}
+}
+
+// matchWildcard matches the text with the pattern in which '*' matches any characters and '?' matches any single character
+func matchWildcard(text string, pattern string) bool {
+	if !strings.ContainsAny(pattern, "*?") {
+		return text == pattern
+	}
+	expr := regexp.QuoteMeta(pattern)
+	expr = strings.ReplaceAll(expr, `\*`, ".*")
+	expr = strings.ReplaceAll(expr, `\?`, ".")
+	return regexp.MustCompile("^" + expr + "$").MatchString(text)
+}
+
+var variableRegexp = regexp.MustCompile(`\{\{\s*([^{}]*?)\s*\}\}`)
+var pathRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_-]*|\."[^"]+"|\[[0-9]+\])*$`)
+var pathElementRegexp = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_-]*|"[^"]+"|\[[0-9]+\]`)
+
+// Variables of the request known without a cluster
+var requestVariables = []string{"request.object", "request.operation", "request.namespace", "request.name"}
+
+// resolveVariable resolves a variable referring to a field of the request by a simple path such as request.object.spec.replicas.
+// JMESPath expressions other than the paths and the variables other than requestVariables (e.g. request.userInfo or images) are not supported.
+func resolveVariable(variables map[string]interface{}, expr string) (interface{}, error) {
+	if !pathRegexp.MatchString(expr) || !slices.ContainsFunc(requestVariables, func(variable string) bool {
+		return expr == variable || strings.HasPrefix(expr, variable+".") || strings.HasPrefix(expr, variable+"[")
+	}) {
+		return nil, errUnsupported{feature: fmt.Sprintf("variable '%s'", expr)}
+	}
+	var current interface{} = variables
+	for _, element := range pathElementRegexp.FindAllString(expr, -1) {
+		if strings.HasPrefix(element, "[") {
+			index, _ := strconv.Atoi(strings.Trim(element, "[]"))
+			array, ok := current.([]interface{})
+			if !ok || index >= len(array) {
+				return nil, nil
+			}
+			current = array[index]
+			continue
+		}
+		typed, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, nil
+		}
+		current = typed[strings.Trim(element, `"`)]
+	}
+	return current, nil
+}
+
+// substituteVariables replaces the variables in the value. A string consisting of a variable only is replaced by the value of the variable as is.
+func substituteVariables(variables map[string]interface{}, value interface{}) (interface{}, error) {
+	switch typed := value.(type) {
+	case string:
+		if matches := variableRegexp.FindStringSubmatch(typed); matches != nil && matches[0] == strings.TrimSpace(typed) {
+			return resolveVariable(variables, matches[1])
+		}
+		var err error
+		substituted := variableRegexp.ReplaceAllStringFunc(typed, func(variable string) string {
+			resolved, resolveErr := resolveVariable(variables, variableRegexp.FindStringSubmatch(variable)[1])
+			if resolveErr != nil {
+				err = resolveErr
+			}
+			return toString(resolved)
+		})
+		return substituted, err
+	case []interface{}:
+		out := []interface{}{}
+		for _, element := range typed {
+			substituted, err := substituteVariables(variables, element)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, substituted)
+		}
+		return out, nil
+	case map[string]interface{}:
+		out := map[string]interface{}{}
+		for k, v := range typed {
+			substituted, err := substituteVariables(variables, v)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = substituted
+		}
+		return out, nil
+	default:
+		return value, nil
+	}
+}
+
+// evaluateConditions evaluates the conditions of deny or preconditions, which are a list of conditions to be all true
+// or a map of the lists of conditions of which any or all must be true.
+func evaluateConditions(variables map[string]interface{}, conditions interface{}) (bool, error) {
+	switch typed := conditions.(type) {
+	case nil:
+		return true, nil
+	case []interface{}:
+		return evaluateConditionList(variables, typed, true)
+	case map[string]interface{}:
+		if anyConditions, ok := typed["any"].([]interface{}); ok && len(anyConditions) > 0 {
+			result, err := evaluateConditionList(variables, anyConditions, false)
+			if err != nil || !result {
+				return false, err
+			}
+		}
+		if allConditions, ok := typed["all"].([]interface{}); ok {
+			return evaluateConditionList(variables, allConditions, true)
+		}
+		return true, nil
+	default:
+		return false, fmt.Errorf("invalid conditions: %v", conditions)
+	}
+}
+
+func evaluateConditionList(variables map[string]interface{}, conditions []interface{}, all bool) (bool, error) {
+	for _, condition := range conditions {
+		conditionMap, ok := condition.(map[string]interface{})
+		if !ok {
+			return false, fmt.Errorf("invalid condition: %v", condition)
+		}
+		result, err := evaluateCondition(variables, conditionMap)
+		if err != nil {
+			return false, err
+		}
+		if all && !result {
+			return false, nil
+		}
+		if !all && result {
+			return true, nil
+		}
+	}
+	return all, nil
+}
+
+func evaluateCondition(variables map[string]interface{}, condition map[string]interface{}) (bool, error) {
+	key, err := substituteVariables(variables, condition["key"])
+	if err != nil {
+		return false, err
+	}
+	value, err := substituteVariables(variables, condition["value"])
+	if err != nil {
+		return false, err
+	}
+	operator, _ := condition["operator"].(string)
+	switch strings.ToLower(operator) {
+	case "equals", "equal":
+		return equalValues(key, value), nil
+	case "notequals", "notequal":
+		return !equalValues(key, value), nil
+	case "in", "anyin":
+		return anyIn(key, value), nil
+	case "allin":
+		return allIn(key, value), nil
+	case "notin", "allnotin":
+		return !anyIn(key, value), nil
+	case "anynotin":
+		return !allIn(key, value), nil
+	case "greaterthan", "greaterthanorequals", "lessthan", "lessthanorequals":
+		cmp, ok := compareValues(key, toString(value))
+		if !ok {
+			return false, nil
+		}
+		switch strings.ToLower(operator) {
+		case "greaterthan":
+			return cmp > 0, nil
+		case "greaterthanorequals":
+			return cmp >= 0, nil
+		case "lessthan":
+			return cmp < 0, nil
+		default:
+			return cmp <= 0, nil
+		}
+	default:
+		return false, errUnsupported{feature: fmt.Sprintf("operator '%s'", operator)}
+	}
+}
+
+func equalValues(key interface{}, value interface{}) bool {
+	if pattern, ok := value.(string); ok {
+		return matchWildcard(toString(key), pattern)
+	}
+	if keyNumber, ok := toFloat(key); ok {
+		if valueNumber, ok := toFloat(value); ok {
+			return keyNumber == valueNumber
+		}
+	}
+	keyJSON, _ := json.Marshal(key)
+	valueJSON, _ := json.Marshal(value)
+	return string(keyJSON) == string(valueJSON)
+}
+
+func toList(value interface{}) []interface{} {
+	if list, ok := value.([]interface{}); ok {
+		return list
+	}
+	return []interface{}{value}
+}
+
+// anyIn is true if any of the keys is in the values
+func anyIn(key interface{}, value interface{}) bool {
+	for _, k := range toList(key) {
+		for _, v := range toList(value) {
+			if equalValues(k, v) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// allIn is true if all of the keys are in the values
+func allIn(key interface{}, value interface{}) bool {
+	for _, k := range toList(key) {
+		if !anyIn(k, value) {
+			return false
+		}
+	}
+	return true
+}
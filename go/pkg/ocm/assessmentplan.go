@@ -0,0 +1,71 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocm
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal"
+	typec2pcr "github.com/oscal-compass/compliance-to-policy/go/pkg/types/c2pcr"
+	typeap "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/assessmentplan"
+	typecommon "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/common"
+)
+
+// GenerateAssessmentPlan returns the assessment plan of the OCM policies generated from the C2P config.
+// The subjects are the managed clusters selected by the cluster selectors and the namespaces evaluated by the policies.
+func GenerateAssessmentPlan(c2pParsed typec2pcr.C2PCRParsed, cdHref string, stamper oscal.Stamper) (*typeap.AssessmentPlanRoot, error) {
+	policyIds := []string{}
+	for _, componentObject := range c2pParsed.ComponentObjects {
+		if componentObject.ComponentType == "validation" {
+			continue
+		}
+		for _, ruleObject := range componentObject.RuleObjects {
+			policyIds = appendUnique(policyIds, ruleObject.PolicyIds(false)...)
+		}
+	}
+	slices.Sort(policyIds)
+
+	props := []typecommon.Prop{}
+	labels := []string{}
+	for key, value := range c2pParsed.ClusterSelectors {
+		labels = append(labels, fmt.Sprintf("%s=%s", key, value))
+	}
+	slices.Sort(labels)
+	for _, label := range labels {
+		props = append(props, typecommon.Prop{Name: "cluster-selector", Value: label})
+	}
+	for _, namespace := range namespaceSelector.Include {
+		props = append(props, typecommon.Prop{Name: "include-namespace", Value: namespace})
+	}
+	for _, namespace := range namespaceSelector.Exclude {
+		props = append(props, typecommon.Prop{Name: "exclude-namespace", Value: namespace})
+	}
+	subject := typeap.AssessmentSubject{
+		Type:        "inventory-item",
+		Description: "Managed clusters selected by the cluster selectors",
+		Props:       props,
+	}
+
+	task := oscal.AssessmentPlanTask{
+		PVP:         "ocm",
+		Description: fmt.Sprintf("OCM policies in the namespace %s of the hub evaluate the managed clusters", c2pParsed.Namespace),
+		PolicyIds:   policyIds,
+		Subjects:    []typeap.AssessmentSubject{subject},
+	}
+	return oscal.GenerateAssessmentPlan(c2pParsed.ComponentDefinition, c2pParsed.ComponentObjects, cdHref, []oscal.AssessmentPlanTask{task}, stamper)
+}
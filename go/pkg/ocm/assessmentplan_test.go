@@ -0,0 +1,61 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/oscal-compass/compliance-to-policy/go/pkg"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal/validation"
+	typecommon "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/common"
+)
+
+func TestGenerateAssessmentPlan(t *testing.T) {
+	c2pcrParsed, _ := parseTestC2PCR(t)
+
+	apRoot, err := GenerateAssessmentPlan(c2pcrParsed, "./component-definition.json", oscal.NewStamper(true))
+	assert.NoError(t, err, "Should not happen")
+	if validation.HasSchema("assessment-plan") {
+		assert.NoError(t, validation.ValidateObject(apRoot))
+	}
+
+	ap := apRoot.AssessmentPlan
+	assert.Equal(t, "#"+ap.BackMatter.Resources[0].UUID, ap.ImportSsp.Href)
+	assert.Equal(t, []typecommon.Rlink{{Href: "./component-definition.json", MediaType: "application/oscal.component-definition+json"}}, ap.BackMatter.Resources[0].Rlinks)
+	includeControls := ap.ReviewedControls.ControlSelections[0].IncludeControls
+	assert.Equal(t, []string{"ac-6", "cm-2", "cm-6"}, []string{includeControls[0].ControlID, includeControls[1].ControlID, includeControls[2].ControlID})
+	assert.Len(t, ap.AssessmentSubjects, 1)
+	assert.Contains(t, ap.AssessmentSubjects[0].Props, typecommon.Prop{Name: "cluster-selector", Value: "environment=test"})
+	assert.Len(t, ap.Tasks, 1)
+	assert.Contains(t, ap.Tasks[0].Props, typecommon.Prop{Name: "pvp", Value: "ocm"})
+	assert.Contains(t, ap.Tasks[0].Props, typecommon.Prop{Name: "policy-id", Value: "policy-high-scan"})
+
+	reporter := NewResultToOscal(c2pcrParsed, pkg.PathFromPkgDirectory("./testdata/ocm/policy-results"))
+	reporter.SetDeterministic(true)
+	reporter.SetAssessmentPlan("./assessment-plan.json", ap)
+	arRoot, err := reporter.Generate()
+	assert.NoError(t, err, "Should not happen")
+	if validation.HasSchema("assessment-results") {
+		assert.NoError(t, validation.ValidateObject(arRoot))
+	}
+	assert.Equal(t, "./assessment-plan.json", arRoot.AssessmentResults.ImportAp.Href)
+	assert.Equal(t, ap.UUID, arRoot.AssessmentResults.BackMatter.Resources[0].UUID)
+	assert.Equal(t, "application/oscal.ap+json", arRoot.AssessmentResults.BackMatter.Resources[0].Rlinks[0].MediaType)
+}
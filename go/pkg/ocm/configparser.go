@@ -22,6 +22,7 @@ import (
 	"github.com/oscal-compass/compliance-to-policy/go/pkg"
 	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal"
 	"github.com/oscal-compass/compliance-to-policy/go/pkg/types/c2pcr"
+	typesoscal "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal"
 )
 
 type C2PCRParser struct {
@@ -58,15 +59,23 @@ func (p *C2PCRParser) Parse(c2pcrSpec c2pcr.Spec) (c2pcr.C2PCRParsed, error) {
 		}
 	}
 
+	compDef := parsed.ComponentDefinition
 	if c2pcrSpec.Compliance.Profile.Url != "" {
 		logger.Info(fmt.Sprintf("Profile is loaded from %s", c2pcrSpec.Compliance.Profile.Url))
 		if err := p.gitUtils.LoadFromWeb(c2pcrSpec.Compliance.Profile.Url, &parsed.Profile); err != nil {
 			logger.Sugar().Error(err, "Failed to load profile")
 			return parsed, err
 		}
+		resolver := oscal.NewProfileResolver(p.gitUtils)
+		resolved, intersected, err := resolver.IntersectWithCD(parsed.Profile.Profile, c2pcrSpec.Compliance.Profile.Url, compDef.ComponentDefinition)
+		if err != nil {
+			logger.Sugar().Error(err, "Failed to resolve profile")
+			return parsed, err
+		}
+		parsed.ResolvedProfile = typesoscal.CatalogRoot{Catalog: *resolved}
+		compDef.ComponentDefinition = intersected
 	}
-
-	parsed.ComponentObjects = oscal.ParseComponentDefinition(parsed.ComponentDefinition)
+	parsed.ComponentObjects = oscal.ParseComponentDefinition(compDef)
 
 	return parsed, err
 }
@@ -17,7 +17,10 @@ limitations under the License.
 package ocm
 
 import (
+	"encoding/json"
 	"fmt"
+	"reflect"
+	"slices"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
@@ -40,9 +43,28 @@ var logger *zap.Logger = pkg.GetLogger("composer")
 
 var DummyNamespace string = "dummy-namespace-c2p"
 
+// Namespaces of the managed clusters evaluated by the generated policies
+var namespaceSelector = pgtype.NamespaceSelector{
+	Exclude: []string{"kube-system", "open-cluster-management", "open-cluster-management-agent", "open-cluster-management-agent-addon"},
+	Include: []string{"*"},
+}
+
+var ParametersConfigMapName string = "c2p-parameters"
+
+// ParameterMode is how parameter placeholders in policy manifests are resolved.
+type ParameterMode string
+
+const (
+	// Placeholders are rewritten into hub templates reading the parameters configmap
+	ParameterModeHubTemplate ParameterMode = "hub-template"
+	// Placeholders are replaced with the parameter values
+	ParameterModeSubstitute ParameterMode = "substitute"
+)
+
 type Composer struct {
-	policiesDir string
-	tempDir     pkg.TempDirectory
+	policiesDir   string
+	tempDir       pkg.TempDirectory
+	parameterMode ParameterMode
 }
 
 func NewComposer(policiesDir string, tempDir string) *Composer {
@@ -51,8 +73,9 @@ func NewComposer(policiesDir string, tempDir string) *Composer {
 
 func NewComposerByTempDirectory(policiesDir string, tempDir pkg.TempDirectory) *Composer {
 	return &Composer{
-		policiesDir: policiesDir,
-		tempDir:     tempDir,
+		policiesDir:   policiesDir,
+		tempDir:       tempDir,
+		parameterMode: ParameterModeHubTemplate,
 	}
 }
 
@@ -60,6 +83,10 @@ func (c *Composer) GetPoliciesDir() string {
 	return c.policiesDir
 }
 
+func (c *Composer) SetParameterMode(mode ParameterMode) {
+	c.parameterMode = mode
+}
+
 func (c *Composer) ComposeByC2PParsed(c2pParsed typec2pcr.C2PCRParsed) error {
 	return c.Compose(c2pParsed.Namespace, c2pParsed.ComponentObjects, c2pParsed.ClusterSelectors)
 }
@@ -71,63 +98,78 @@ func (c *Composer) Compose(namespace string, componentObjects []oscal.ComponentO
 	}
 
 	logger.Info("Start composing policySets")
-	parameters := map[string]string{}
+	parameters, err := collectParameters(componentObjects)
+	if err != nil {
+		return err
+	}
 	policyConfigMap := map[string]pgtype.PolicyConfig{}
 	policySets := []pgtype.PolicySetConfig{}
 	policySetPatches := []typekustomize.Patch{}
 	for _, componentObject := range componentObjects {
+		// Validation components only map checks to the rules of the target components
+		if componentObject.ComponentType == "validation" {
+			continue
+		}
 		logger := logger.With(zap.Namespace(fmt.Sprintf("component %s", componentObject.ComponentTitle)))
 		logger.Info("Start generating policy")
 		for _, ruleObject := range componentObject.RuleObjects {
-			sourceDir := fmt.Sprintf("%s/%s", c.policiesDir, ruleObject.PolicyId)
-			destDir := fmt.Sprintf("%s/%s", c.tempDir.GetTempDir(), ruleObject.PolicyId)
-			err := cp.Copy(sourceDir, destDir)
-			if err != nil {
-				return err
+			policyIds := ruleObject.PolicyIds(false)
+			if len(policyIds) == 0 {
+				return fmt.Errorf("rule %s of component %s has neither Policy_Id nor Check_Id", ruleObject.RuleId, componentObject.ComponentTitle)
+			}
+			for _, policyId := range policyIds {
+				sourceDir := fmt.Sprintf("%s/%s", c.policiesDir, policyId)
+				destDir := fmt.Sprintf("%s/%s", c.tempDir.GetTempDir(), policyId)
+				err := cp.Copy(sourceDir, destDir)
+				if err != nil {
+					return err
+				}
+				paramIds := parameterIdsOfPolicy(policyId, componentObject.RuleObjects)
+				if err := c.applyParameters(destDir, namespace, paramIds, parameters); err != nil {
+					return err
+				}
 			}
 		}
 
 		for idx, controlImpleObject := range componentObject.ControlImpleObjects {
 			policyListPerControlImple := []string{}
-			for _, param := range controlImpleObject.SetParameters {
-				parameters[param.ParamID] = param.Values[0]
-			}
 			for _, controlObject := range controlImpleObject.ControlObjects {
 				for _, ruleId := range controlObject.RuleIds {
 					ruleObject, ok := oscal.FindRulesByRuleId(ruleId, componentObject.RuleObjects)
 					if ok {
-						policyId := ruleObject.PolicyId
-						destDir := fmt.Sprintf("%s/%s", c.tempDir.GetTempDir(), policyId)
-						policyGeneratorManifestPath := destDir + "/policy-generator.yaml"
-						var policyGeneratorManifest pgtype.PolicyGenerator
-						if err := pkg.LoadYamlFileToObject(policyGeneratorManifestPath, &policyGeneratorManifest); err != nil {
-							return err
-						}
-						policyGeneratorManifest.PolicyDefaults.Namespace = namespace
-						policyGeneratorManifest.PolicyDefaults.PolicyOptions.Standards = []string{""}
-						policyGeneratorManifest.PolicyDefaults.PolicyOptions.Categories = []string{""}
-						policyGeneratorManifest.PolicyDefaults.PolicyOptions.Controls = []string{controlObject.ControlId}
-						policyGeneratorManifest.PolicyDefaults.PolicyOptions.Placement.ClusterSelectors = clusterSelectors
-						if err := pkg.WriteObjToYamlFileByGoYaml(policyGeneratorManifestPath, policyGeneratorManifest); err != nil {
-							return err
-						}
-						// For policySet
-						policyListPerControlImple = appendUnique(policyListPerControlImple, policyId)
-						policyConfig, ok := policyConfigMap[policyId]
-						if ok {
-							policyConfig.Standards = appendUnique(policyConfig.Standards, policyGeneratorManifest.PolicyDefaults.Standards...)
-							policyConfig.Categories = appendUnique(policyConfig.Categories, policyGeneratorManifest.PolicyDefaults.Categories...)
-							policyConfig.Controls = appendUnique(policyConfig.Controls, policyGeneratorManifest.PolicyDefaults.Controls...)
-							policyConfigMap[policyId] = policyConfig
-						} else {
-							policyConfig := policyGeneratorManifest.Policies[0]
-							policyConfig.Standards = policyGeneratorManifest.PolicyDefaults.Standards
-							policyConfig.Categories = policyGeneratorManifest.PolicyDefaults.Categories
-							policyConfig.Controls = policyGeneratorManifest.PolicyDefaults.Controls
-							for idx, manifest := range policyConfig.Manifests {
-								policyConfig.Manifests[idx].Path = strings.Replace(manifest.Path, "./", fmt.Sprintf("./%s/", policyId), 1)
+						for _, policyId := range ruleObject.PolicyIds(false) {
+							destDir := fmt.Sprintf("%s/%s", c.tempDir.GetTempDir(), policyId)
+							policyGeneratorManifestPath := destDir + "/policy-generator.yaml"
+							var policyGeneratorManifest pgtype.PolicyGenerator
+							if err := pkg.LoadYamlFileToObject(policyGeneratorManifestPath, &policyGeneratorManifest); err != nil {
+								return err
+							}
+							policyGeneratorManifest.PolicyDefaults.Namespace = namespace
+							policyGeneratorManifest.PolicyDefaults.PolicyOptions.Standards = []string{""}
+							policyGeneratorManifest.PolicyDefaults.PolicyOptions.Categories = []string{""}
+							policyGeneratorManifest.PolicyDefaults.PolicyOptions.Controls = []string{controlObject.ControlId}
+							policyGeneratorManifest.PolicyDefaults.PolicyOptions.Placement.ClusterSelectors = clusterSelectors
+							if err := pkg.WriteObjToYamlFileByGoYaml(policyGeneratorManifestPath, policyGeneratorManifest); err != nil {
+								return err
+							}
+							// For policySet
+							policyListPerControlImple = appendUnique(policyListPerControlImple, policyId)
+							policyConfig, ok := policyConfigMap[policyId]
+							if ok {
+								policyConfig.Standards = appendUnique(policyConfig.Standards, policyGeneratorManifest.PolicyDefaults.Standards...)
+								policyConfig.Categories = appendUnique(policyConfig.Categories, policyGeneratorManifest.PolicyDefaults.Categories...)
+								policyConfig.Controls = appendUnique(policyConfig.Controls, policyGeneratorManifest.PolicyDefaults.Controls...)
+								policyConfigMap[policyId] = policyConfig
+							} else {
+								policyConfig := policyGeneratorManifest.Policies[0]
+								policyConfig.Standards = policyGeneratorManifest.PolicyDefaults.Standards
+								policyConfig.Categories = policyGeneratorManifest.PolicyDefaults.Categories
+								policyConfig.Controls = policyGeneratorManifest.PolicyDefaults.Controls
+								for idx, manifest := range policyConfig.Manifests {
+									policyConfig.Manifests[idx].Path = strings.Replace(manifest.Path, "./", fmt.Sprintf("./%s/", policyId), 1)
+								}
+								policyConfigMap[policyId] = policyConfig
 							}
-							policyConfigMap[policyId] = policyConfig
 						}
 					}
 				}
@@ -159,15 +201,18 @@ func (c *Composer) Compose(namespace string, componentObjects []oscal.ComponentO
 			},
 		},
 		ConfigurationPolicyOptions: pgtype.ConfigurationPolicyOptions{
-			NamespaceSelector: pgtype.NamespaceSelector{
-				Exclude: []string{"kube-system", "open-cluster-management", "open-cluster-management-agent", "open-cluster-management-agent-addon"},
-				Include: []string{"*"},
-			},
+			NamespaceSelector: namespaceSelector,
 		},
 	}
+	// Sort the policies so that the generated manifests don't depend on the map iteration order
+	policyIds := []string{}
+	for policyId := range policyConfigMap {
+		policyIds = append(policyIds, policyId)
+	}
+	slices.Sort(policyIds)
 	policyConfigs := []pgtype.PolicyConfig{}
-	for _, policyConfig := range policyConfigMap {
-		policyConfigs = append(policyConfigs, policyConfig)
+	for _, policyId := range policyIds {
+		policyConfigs = append(policyConfigs, policyConfigMap[policyId])
 	}
 	policySetGeneratorManifest := policygenerator.BuildPolicyGeneratorManifest("policy-set", policyDefaults, policyConfigs)
 	policySetGeneratorManifest.PlacementBindingDefaults.Name = "policy-set"
@@ -185,17 +230,22 @@ func (c *Composer) Compose(namespace string, componentObjects []oscal.ComponentO
 		return err
 	}
 
-	logger.Info("Create configmapt for templatized parameters")
+	logger.Info("Create configmap for templatized parameters")
+	parametersData, err := toConfigMapData(parameters)
+	if err != nil {
+		return err
+	}
 	parametersConfigmap := corev1.ConfigMap{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "ConfigMap",
 			APIVersion: "v1",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "c2p-parameters",
-			Namespace: namespace,
+			Name: ParametersConfigMapName,
+			// The configmap has to be in the namespace of the policies so that the hub templates can read it.
+			Namespace: policySetGeneratorManifest.PolicyDefaults.Namespace,
 		},
-		Data: parameters,
+		Data: parametersData,
 	}
 	if err := pkg.WriteObjToYamlFile(c.tempDir.GetTempDir()+"/parameters.yaml", parametersConfigmap); err != nil {
 		return err
@@ -241,6 +291,83 @@ func (c *Composer) GeneratePolicySet() (*resmap.ResMap, error) {
 	return &generatedManifests, nil
 }
 
+// collectParameters returns the values of set-parameters of all control implementations.
+func collectParameters(componentObjects []oscal.ComponentObject) (map[string][]string, error) {
+	parameters := map[string][]string{}
+	for _, componentObject := range componentObjects {
+		for _, controlImpleObject := range componentObject.ControlImpleObjects {
+			for _, param := range controlImpleObject.SetParameters {
+				values, ok := parameters[param.ParamID]
+				if ok && !reflect.DeepEqual(values, param.Values) {
+					return nil, fmt.Errorf("parameter %s has conflicting values %v and %v", param.ParamID, values, param.Values)
+				}
+				parameters[param.ParamID] = param.Values
+			}
+		}
+	}
+	return parameters, nil
+}
+
+func parameterIdsOfPolicy(policyId string, ruleObjects []oscal.RuleObject) []string {
+	paramIds := []string{}
+	for _, ruleObject := range ruleObjects {
+		if slices.Contains(ruleObject.PolicyIds(false), policyId) && ruleObject.ParameterId != "" {
+			paramIds = appendUnique(paramIds, ruleObject.ParameterId)
+		}
+	}
+	return paramIds
+}
+
+// applyParameters resolves parameter placeholders in the policy manifests of the directory according to the parameter mode.
+func (c *Composer) applyParameters(dir string, namespace string, paramIds []string, parameters map[string][]string) error {
+	return pkg.ReplaceParametersInDir(dir, func(placeholder pkg.Placeholder) ([]string, string, error) {
+		paramId := placeholder.ParamId
+		if !slices.Contains(paramIds, paramId) {
+			return nil, "", fmt.Errorf("unknown parameter %s: no rule of the policy has such parameter", paramId)
+		}
+		values := parameters[paramId]
+		if len(values) == 0 {
+			return nil, "", fmt.Errorf("parameter %s is not set", paramId)
+		}
+		if c.parameterMode == ParameterModeSubstitute {
+			return values, placeholder.Tag(), nil
+		}
+		// Without the target namespace the policies are generated in a dummy namespace stripped from the output,
+		// so the hub templates would read the configmap from a namespace which does not exist on the hub
+		if namespace == "" {
+			return nil, "", fmt.Errorf("parameter %s cannot be read by hub template without the namespace of the policies: set target.namespace of the c2p config or use the %s parameter mode", paramId, ParameterModeSubstitute)
+		}
+		if len(values) > 1 && placeholder.Embedded {
+			return nil, "", fmt.Errorf("parameter %s has multiple values and cannot be embedded in a string by hub template", paramId)
+		}
+		// e.g. {{hub fromConfigMap "c2p" "c2p-parameters" "minimum_replicas" | toInt hub}}
+		pipeline := []string{fmt.Sprintf(`fromConfigMap "%s" "%s" "%s"`, namespace, ParametersConfigMapName, paramId)}
+		if len(values) > 1 {
+			pipeline = append(pipeline, "toLiteral")
+		} else if placeholder.Function != "" {
+			pipeline = append(pipeline, placeholder.Function)
+		}
+		return []string{fmt.Sprintf("{{hub %s hub}}", strings.Join(pipeline, " | "))}, "!!str", nil
+	})
+}
+
+// toConfigMapData converts parameters to configmap data. Multiple values are stored as a JSON array.
+func toConfigMapData(parameters map[string][]string) (map[string]string, error) {
+	data := map[string]string{}
+	for paramId, values := range parameters {
+		if len(values) == 1 {
+			data[paramId] = values[0]
+			continue
+		}
+		jsonValues, err := json.Marshal(values)
+		if err != nil {
+			return nil, err
+		}
+		data[paramId] = string(jsonValues)
+	}
+	return data, nil
+}
+
 func toDNSCompliant(name string) string {
 	var result string
 	result = strings.ToLower(name)
@@ -21,8 +21,12 @@ import (
 	"testing"
 
 	"github.com/oscal-compass/compliance-to-policy/go/pkg"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal"
 	typec2pcr "github.com/oscal-compass/compliance-to-policy/go/pkg/types/c2pcr"
+	pgtype "github.com/oscal-compass/compliance-to-policy/go/pkg/types/policygenerator"
 	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 )
 
 func TestOscal2Policy(t *testing.T) {
@@ -71,6 +75,105 @@ func TestOscal2Policy(t *testing.T) {
 	assert.NoError(t, err, "Should not happen")
 
 	composer := NewComposerByTempDirectory(c2pcrParsed.PolicyResoureDir, tempDir)
+	// Hub templates require the namespace of the policies
+	composer.SetParameterMode(ParameterModeSubstitute)
 	err = composer.Compose(c2pcrParsed.Namespace, c2pcrParsed.ComponentObjects, c2pcrParsed.ClusterSelectors)
 	assert.NoError(t, err, "Should not happen")
 }
+
+func TestOscal2PolicyWithParameters(t *testing.T) {
+	policyDir := pkg.PathFromPkgDirectory("./testdata/ocm/policies")
+	cdPath := pkg.PathFromPkgDirectory("./testdata/ocm/component-definition.json")
+
+	tempDirPath := pkg.PathFromPkgDirectory("./testdata/_test")
+	err := os.MkdirAll(tempDirPath, os.ModePerm)
+	assert.NoError(t, err, "Should not happen")
+
+	c2pcrSpec := typec2pcr.Spec{
+		Compliance: typec2pcr.Compliance{
+			Name: "Test Compliance",
+			ComponentDefinition: typec2pcr.ResourceRef{
+				Url: cdPath,
+			},
+		},
+		PolicyResources: typec2pcr.ResourceRef{
+			Url: policyDir,
+		},
+		ClusterGroups: []typec2pcr.ClusterGroup{{
+			Name:        "test-group",
+			MatchLabels: &map[string]string{"environment": "test"},
+		}},
+		Binding: typec2pcr.Binding{
+			Compliance:    "Test Compliance",
+			ClusterGroups: []string{"test-group"},
+		},
+		Target: typec2pcr.Target{
+			Namespace: "c2p",
+		},
+	}
+	c2pcrParser := NewParser(pkg.NewGitUtils(pkg.NewTempDirectory(tempDirPath)))
+	c2pcrParsed, err := c2pcrParser.Parse(c2pcrSpec)
+	assert.NoError(t, err, "Should not happen")
+
+	deploymentPath := "/policy-deployment/policy-nginx-deployment/Deployment.nginx-deployment.0.yaml"
+
+	tempDir := pkg.NewTempDirectory(tempDirPath)
+	composer := NewComposerByTempDirectory(c2pcrParsed.PolicyResoureDir, tempDir)
+	err = composer.ComposeByC2PParsed(c2pcrParsed)
+	assert.NoError(t, err, "Should not happen")
+
+	var deployment appsv1.Deployment
+	data, err := os.ReadFile(tempDir.GetTempDir() + deploymentPath)
+	assert.NoError(t, err, "Should not happen")
+	assert.Contains(t, string(data), `replicas: '{{hub fromConfigMap "c2p" "c2p-parameters" "minimum_nginx_deployment_replicas" | toInt hub}}'`)
+
+	var configmap corev1.ConfigMap
+	err = pkg.LoadYamlFileToK8sTypedObject(tempDir.GetTempDir()+"/parameters.yaml", &configmap)
+	assert.NoError(t, err, "Should not happen")
+	assert.Equal(t, "c2p", configmap.Namespace)
+	assert.Equal(t, map[string]string{"minimum_nginx_deployment_replicas": "3"}, configmap.Data)
+
+	tempDir = pkg.NewTempDirectory(tempDirPath)
+	composer = NewComposerByTempDirectory(c2pcrParsed.PolicyResoureDir, tempDir)
+	composer.SetParameterMode(ParameterModeSubstitute)
+	err = composer.ComposeByC2PParsed(c2pcrParsed)
+	assert.NoError(t, err, "Should not happen")
+
+	err = pkg.LoadYamlFileToK8sTypedObject(tempDir.GetTempDir()+deploymentPath, &deployment)
+	assert.NoError(t, err, "Should not happen")
+	assert.Equal(t, int32(3), *deployment.Spec.Replicas)
+
+	tempDir = pkg.NewTempDirectory(tempDirPath)
+	composer = NewComposerByTempDirectory(c2pcrParsed.PolicyResoureDir, tempDir)
+	err = composer.Compose("", c2pcrParsed.ComponentObjects, c2pcrParsed.ClusterSelectors)
+	assert.ErrorContains(t, err, "parameter minimum_nginx_deployment_replicas cannot be read by hub template without the namespace of the policies")
+}
+
+func TestOscal2PolicyWithChecks(t *testing.T) {
+	policyDir := pkg.PathFromPkgDirectory("./testdata/ocm/policies")
+	tempDirPath := pkg.PathFromPkgDirectory("./testdata/_test")
+	err := os.MkdirAll(tempDirPath, os.ModePerm)
+	assert.NoError(t, err, "Should not happen")
+	tempDir := pkg.NewTempDirectory(tempDirPath)
+
+	// The rule without Policy_Id is validated by all of its checks
+	componentObjects := []oscal.ComponentObject{{
+		ComponentTitle: "Managed Kubernetes",
+		ComponentType:  "service",
+		RuleObjects: []oscal.RuleObject{{
+			RuleId: "test_rule",
+			Checks: []oscal.CheckObject{{CheckId: "policy-disallowed-roles"}, {CheckId: "policy-high-scan"}},
+		}},
+		ControlImpleObjects: []oscal.ControlImpleObject{{
+			ControlObjects: []oscal.ControlObject{{ControlId: "ac-6", RuleIds: []string{"test_rule"}}},
+		}},
+	}}
+	composer := NewComposerByTempDirectory(policyDir, tempDir)
+	err = composer.Compose("c2p", componentObjects, nil)
+	assert.NoError(t, err, "Should not happen")
+
+	var policySetGenerator pgtype.PolicyGenerator
+	err = pkg.LoadYamlFileToObject(tempDir.GetTempDir()+"/policy-generator.yaml", &policySetGenerator)
+	assert.NoError(t, err, "Should not happen")
+	assert.Equal(t, []string{"policy-disallowed-roles", "policy-high-scan"}, policySetGenerator.PolicySets[0].Policies)
+}
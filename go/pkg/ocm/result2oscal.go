@@ -18,15 +18,17 @@ package ocm
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/oscal-compass/compliance-to-policy/go/pkg"
-	sigyaml "sigs.k8s.io/yaml"
 
 	"k8s.io/apimachinery/pkg/util/sets"
 
 	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal"
 	typec2pcr "github.com/oscal-compass/compliance-to-policy/go/pkg/types/c2pcr"
+	typeap "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/assessmentplan"
 	typear "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/assessmentresults"
 	typeoscalcommon "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/common"
 	typeplacementdecision "github.com/oscal-compass/compliance-to-policy/go/pkg/types/placementdecision"
@@ -41,6 +43,10 @@ type ResultToOscal struct {
 	policies           []*typepolicy.Policy
 	policySets         []*typepolicy.PolicySet
 	placementDecisions []*typeplacementdecision.PlacementDecision
+	aggregationRule    oscal.AggregationRule
+	stamper            oscal.Stamper
+	assessmentPlanHref string
+	assessmentPlan     *typeap.AssessmentPlan
 }
 
 type Reason struct {
@@ -63,10 +69,28 @@ func NewResultToOscal(c2pParsed typec2pcr.C2PCRParsed, policyResultsDir string)
 		policies:           []*typepolicy.Policy{},
 		policySets:         []*typepolicy.PolicySet{},
 		placementDecisions: []*typeplacementdecision.PlacementDecision{},
+		aggregationRule:    oscal.AggregationRuleAllPass,
+		stamper:            oscal.NewStamper(false),
 	}
 	return &r
 }
 
+// SetAggregationRule sets the rule aggregating the results of the observations into the status of the findings of the controls.
+func (r *ResultToOscal) SetAggregationRule(aggregationRule oscal.AggregationRule) {
+	r.aggregationRule = aggregationRule
+}
+
+// SetDeterministic makes the same inputs produce the same assessment results.
+func (r *ResultToOscal) SetDeterministic(deterministic bool) {
+	r.stamper = oscal.NewStamper(deterministic)
+}
+
+// SetAssessmentPlan makes the assessment results import the assessment plan by the href.
+func (r *ResultToOscal) SetAssessmentPlan(href string, ap typeap.AssessmentPlan) {
+	r.assessmentPlanHref = href
+	r.assessmentPlan = &ap
+}
+
 func (r *ResultToOscal) Generate() (*typear.AssessmentResultsRoot, error) {
 
 	var policyList typepolicy.PolicyList
@@ -102,7 +126,7 @@ func (r *ResultToOscal) Generate() (*typear.AssessmentResultsRoot, error) {
 				if !exist {
 					clusternameIndex[s.ClusterName] = true
 					item := typear.InventoryItem{
-						UUID: oscal.GenerateUUID(),
+						UUID: r.stamper.UUID("inventory-item", s.ClusterName),
 						Props: []typeoscalcommon.Prop{{
 							Name:  "cluster-name",
 							Value: s.ClusterName,
@@ -114,6 +138,8 @@ func (r *ResultToOscal) Generate() (*typear.AssessmentResultsRoot, error) {
 		}
 	}
 	observations := []typear.Observation{}
+	reviewedControls := []oscal.ControlObject{}
+	inputTimestamps := []time.Time{}
 	for _, cdobj := range r.c2pParsed.ComponentObjects {
 		policySets := typeutils.FilterByAnnotation(r.policySets, pkg.ANNOTATION_COMPONENT_TITLE, cdobj.ComponentTitle)
 		clusterNameSets := sets.NewString()
@@ -139,6 +165,9 @@ func (r *ResultToOscal) Generate() (*typear.AssessmentResultsRoot, error) {
 			for _, controlObj := range controlImpleObj.ControlObjects {
 				ruleResults := []typereport.RuleResult{}
 				controlId := controlObj.ControlId
+				if len(controlObj.RuleIds) > 0 {
+					reviewedControls = append(reviewedControls, controlObj)
+				}
 				for _, ruleId := range controlObj.RuleIds {
 					requiredControls.Insert(controlId)
 					rule, ok := oscal.FindRulesByRuleId(ruleId, cdobj.RuleObjects)
@@ -149,105 +178,140 @@ func (r *ResultToOscal) Generate() (*typear.AssessmentResultsRoot, error) {
 							Status:   typereport.RuleStatusUnImplemented,
 						})
 					} else {
-						policyId := rule.PolicyId
-						var policy *typepolicy.Policy
-						if policySet != nil {
-							policy = typeutils.FindByNamespaceName(r.policies, policySet.Namespace, policyId)
-						}
-						var ruleStatus typereport.RuleStatus
-						subjects := []typear.Subject{}
-						if policy != nil {
-							reasons := r.GenerateReasonsFromRawPolicies(*policy)
-							ruleStatus = mapToRuleStatus(policy.Status.ComplianceState)
-							for _, reason := range reasons {
-								clusterName := "N/A"
-								inventoryUuid := ""
-								for _, inventory := range inventories {
-									prop, ok := oscal.FindProp("cluster-name", inventory.Props)
-									if ok && prop.Value == reason.ClusterName {
-										clusterName = prop.Value
-										inventoryUuid = inventory.UUID
-										break
-									}
-								}
-								if inventoryUuid != "" {
-									var message string
-									if messageByte, err := sigyaml.Marshal(reason.Messages); err == nil {
-										message = string(messageByte)
-									} else {
-										message = err.Error()
+						for _, policyId := range rule.PolicyIds(false) {
+							var policy *typepolicy.Policy
+							if policySet != nil {
+								policy = typeutils.FindByNamespaceName(r.policies, policySet.Namespace, policyId)
+							}
+							var ruleStatus typereport.RuleStatus
+							subjects := []typear.Subject{}
+							if policy != nil {
+								reasons := r.GenerateReasonsFromRawPolicies(*policy)
+								ruleStatus = mapToRuleStatus(policy.Status.ComplianceState)
+								for _, reason := range reasons {
+									clusterName := "N/A"
+									inventoryUuid := ""
+									for _, inventory := range inventories {
+										prop, ok := oscal.FindProp("cluster-name", inventory.Props)
+										if ok && prop.Value == reason.ClusterName {
+											clusterName = prop.Value
+											inventoryUuid = inventory.UUID
+											break
+										}
 									}
-									subject := typear.Subject{
-										SubjectUUID: inventoryUuid,
-										Type:        "resource",
-										Title:       "Cluster Name: " + clusterName,
-										Props: []typeoscalcommon.Prop{{
+									if inventoryUuid != "" {
+										messages := []string{}
+										for _, message := range reason.Messages {
+											messages = append(messages, message.Message)
+											inputTimestamps = append(inputTimestamps, message.LastTimestamp.Time)
+										}
+										props := []typeoscalcommon.Prop{{
 											Name:  "result",
 											Value: string(mapToRuleStatus(reason.ComplianceState)),
-										}, {
-											Name:  "reason",
-											Value: message,
-										}},
+										}}
+										if message := oscal.ToPropValue(strings.Join(messages, "; ")); message != "" {
+											props = append(props, typeoscalcommon.Prop{
+												Name:  "reason",
+												Value: message,
+											})
+										}
+										subject := typear.Subject{
+											SubjectUUID: inventoryUuid,
+											Type:        "resource",
+											Title:       "Cluster Name: " + clusterName,
+											Props:       props,
+										}
+										subjects = append(subjects, subject)
 									}
-									subjects = append(subjects, subject)
 								}
+							} else {
+								ruleStatus = typereport.RuleStatusError
 							}
-						} else {
-							ruleStatus = typereport.RuleStatusError
-						}
-						observation := typear.Observation{
-							UUID:        oscal.GenerateUUID(),
-							Description: fmt.Sprintf("Observation of policy %s", policyId),
-							Methods:     []string{"TEST-AUTOMATED"},
-							Props: []typeoscalcommon.Prop{{
-								Name:  "assessment-rule-id",
-								Value: ruleId,
-							}, {
-								Name:  "policy-id",
-								Value: policyId,
-							}, {
-								Name:  "control-id",
-								Value: controlId,
-							}, {
-								Name:  "result",
-								Value: string(ruleStatus),
-							}},
-							Subjects: subjects,
+							observation := typear.Observation{
+								UUID:        r.stamper.UUID("observation", cdobj.ComponentTitle, controlId, controlObj.StatementId, ruleId, policyId),
+								Description: fmt.Sprintf("Observation of policy %s", policyId),
+								Methods:     []string{"TEST-AUTOMATED"},
+								Props: []typeoscalcommon.Prop{{
+									Name:  "assessment-rule-id",
+									Value: ruleId,
+								}, {
+									Name:  "policy-id",
+									Value: policyId,
+								}, {
+									Name:  "control-id",
+									Value: controlId,
+								}, {
+									Name:  "result",
+									Value: string(ruleStatus),
+								}},
+								Subjects: subjects,
+							}
+							if controlObj.StatementId != "" {
+								observation.Props = append(observation.Props, typeoscalcommon.Prop{
+									Name:  "statement-id",
+									Value: controlObj.StatementId,
+								})
+							}
+							// The policy of a check only validates the check
+							checkIds := rule.CheckIds()
+							if slices.Contains(checkIds, policyId) {
+								checkIds = []string{policyId}
+							}
+							for _, checkId := range checkIds {
+								observation.Props = append(observation.Props, typeoscalcommon.Prop{
+									Name:  "check-id",
+									Value: checkId,
+								})
+							}
+							observations = append(observations, observation)
+							checkedControls.Insert(controlId)
 						}
-						observations = append(observations, observation)
-						checkedControls.Insert(controlId)
 					}
 				}
 			}
 		}
 	}
 
+	timestamp, err := r.stamper.Timestamp(inputTimestamps...)
+	if err != nil {
+		return nil, err
+	}
 	metadata := typear.Metadata{
 		Title:        "OSCAL Assessment Results",
-		LastModified: time.Now(),
+		LastModified: timestamp,
 		Version:      "0.0.1",
-		OscalVersion: "1.0.4",
-	}
-	importAp := typear.ImportAp{
-		Href: "http://...",
+		OscalVersion: oscal.OscalVersion,
 	}
 	ar := typear.AssessmentResults{
-		UUID:     oscal.GenerateUUID(),
+		UUID:     r.stamper.UUID("assessment-results", "ocm", r.c2pParsed.Namespace, timestamp.Format(time.RFC3339Nano)),
 		Metadata: metadata,
-		ImportAp: importAp,
 		Results:  []typear.Result{},
 	}
 	result := typear.Result{
-		UUID:        oscal.GenerateUUID(),
+		UUID:        r.stamper.UUID("result", "ocm", r.c2pParsed.Namespace, timestamp.Format(time.RFC3339Nano)),
 		Title:       "Assessment Results by OCM",
 		Description: "Assessment Results by OCM...",
-		Start:       time.Now(),
+		Start:       timestamp,
 		LocalDefinitions: typear.LocalDefinitions{
 			InventoryItems: inventories,
 		},
+		ReviewedControls: typear.ReviewedControl{
+			ControlSelections: []typear.ControlSelection{{
+				IncludeControls: oscal.SelectControls(reviewedControls),
+			}},
+		},
 		Observations: observations,
+		Findings:     oscal.GenerateFindings(r.c2pParsed.ComponentObjects, observations, r.aggregationRule, r.stamper),
 	}
 	ar.Results = append(ar.Results, result)
+	if r.assessmentPlan != nil {
+		oscal.ImportAssessmentPlan(&ar, r.assessmentPlanHref, *r.assessmentPlan)
+	} else {
+		oscal.ImportComponentDefinition(&ar, r.c2pParsed.ComponentDefinition, r.stamper)
+	}
+	if r.stamper.IsDeterministic() {
+		oscal.SortAssessmentResults(&ar)
+	}
 	arRoot := typear.AssessmentResultsRoot{AssessmentResults: ar}
 
 	return &arRoot, nil
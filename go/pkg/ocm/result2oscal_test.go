@@ -19,19 +19,21 @@ package ocm
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/oscal-compass/compliance-to-policy/go/pkg"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal"
 	typec2pcr "github.com/oscal-compass/compliance-to-policy/go/pkg/types/c2pcr"
 	typear "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/assessmentresults"
+	typecommon "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/common"
 	"github.com/stretchr/testify/assert"
 )
 
-func TestResult2Oscal(t *testing.T) {
+func parseTestC2PCR(t *testing.T) (typec2pcr.C2PCRParsed, pkg.TempDirectory) {
 
 	policyDir := pkg.PathFromPkgDirectory("./testdata/ocm/policies")
-	policyResultsDir := pkg.PathFromPkgDirectory("./testdata/ocm/policy-results")
 	catalogPath := pkg.PathFromPkgDirectory("./testdata/ocm/catalog.json")
 	profilePath := pkg.PathFromPkgDirectory("./testdata/ocm/profile.json")
 	cdPath := pkg.PathFromPkgDirectory("./testdata/ocm/component-definition.json")
@@ -71,6 +73,13 @@ func TestResult2Oscal(t *testing.T) {
 	c2pcrParsed, err := c2pcrParser.Parse(c2pcrSpec)
 	assert.NoError(t, err, "Should not happen")
 
+	return c2pcrParsed, tempDir
+}
+
+func TestResult2Oscal(t *testing.T) {
+	c2pcrParsed, tempDir := parseTestC2PCR(t)
+	policyResultsDir := pkg.PathFromPkgDirectory("./testdata/ocm/policy-results")
+
 	reporter := NewResultToOscal(c2pcrParsed, policyResultsDir)
 	arRoot, err := reporter.Generate()
 	assert.NoError(t, err, "Should not happen")
@@ -89,6 +98,48 @@ func TestResult2Oscal(t *testing.T) {
 		cmpopts.IgnoreFields(typear.InventoryItem{}, "UUID"),
 		cmpopts.IgnoreFields(typear.Subject{}, "SubjectUUID"),
 		cmpopts.IgnoreFields(typear.Observation{}, "UUID"),
+		cmpopts.IgnoreFields(typear.Finding{}, "UUID"),
+		cmpopts.IgnoreFields(typear.RelatedObservation{}, "ObservationUUID"),
+		cmpopts.IgnoreFields(typear.ImportAp{}, "Href"),
+		cmpopts.IgnoreFields(typecommon.Resource{}, "UUID"),
 	)
 	assert.Equal(t, diff, "", "assessment-result matched")
+	// Without the assessment plan, the assessment results refer to the component-definition
+	assert.Equal(t, "#"+arRoot.AssessmentResults.BackMatter.Resources[0].UUID, arRoot.AssessmentResults.ImportAp.Href)
+
+	result := arRoot.AssessmentResults.Results[0]
+	for _, finding := range result.Findings {
+		for _, relatedObservation := range finding.RelatedObservations {
+			found := false
+			for _, observation := range result.Observations {
+				if observation.UUID == relatedObservation.ObservationUUID {
+					found = true
+				}
+			}
+			assert.True(t, found, "related observation of %s exists", finding.Target.TargetId)
+		}
+	}
+}
+
+func TestResult2OscalDeterministic(t *testing.T) {
+	c2pcrParsed, _ := parseTestC2PCR(t)
+	policyResultsDir := pkg.PathFromPkgDirectory("./testdata/ocm/policy-results")
+
+	generate := func() *typear.AssessmentResultsRoot {
+		reporter := NewResultToOscal(c2pcrParsed, policyResultsDir)
+		reporter.SetDeterministic(true)
+		arRoot, err := reporter.Generate()
+		assert.NoError(t, err, "Should not happen")
+		return arRoot
+	}
+
+	arRoot := generate()
+	assert.Equal(t, arRoot, generate())
+	expectedTimestamp := time.Date(2023, 7, 5, 23, 53, 37, 0, time.UTC)
+	assert.Equal(t, expectedTimestamp, arRoot.AssessmentResults.Metadata.LastModified)
+	assert.Equal(t, expectedTimestamp, arRoot.AssessmentResults.Results[0].Start)
+
+	t.Setenv(oscal.SourceDateEpochEnv, "1700000000")
+	arRoot = generate()
+	assert.Equal(t, time.Unix(1700000000, 0).UTC(), arRoot.AssessmentResults.Metadata.LastModified)
 }
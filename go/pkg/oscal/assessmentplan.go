@@ -0,0 +1,187 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oscal
+
+import (
+	"fmt"
+	neturl "net/url"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal/format"
+	typeap "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/assessmentplan"
+	typear "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/assessmentresults"
+	typecommon "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/common"
+	typecd "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/componentdefinition"
+)
+
+// AssessmentPlanTask is the assessment of the rules by the policies of a PVP.
+type AssessmentPlanTask struct {
+	// Name of the PVP (e.g. ocm or kyverno)
+	PVP         string
+	Description string
+	// Policies assessing the rules
+	PolicyIds []string
+	// Subjects assessed by the policies (e.g. clusters or namespaces)
+	Subjects []typeap.AssessmentSubject
+}
+
+// GenerateAssessmentPlan returns the assessment plan of the controls implemented by the components, assessed by the tasks.
+// There is no system security plan: import-ssp refers to a resource of the back-matter linking the component-definition (cdHref).
+func GenerateAssessmentPlan(cdRoot typecd.ComponentDefinitionRoot, componentObjects []ComponentObject, cdHref string, tasks []AssessmentPlanTask, stamper Stamper) (*typeap.AssessmentPlanRoot, error) {
+	timestamp, err := stamper.Timestamp(cdRoot.ComponentDefinition.Metadata.LastModified)
+	if err != nil {
+		return nil, err
+	}
+
+	controlObjects := []ControlObject{}
+	for _, componentObject := range componentObjects {
+		if componentObject.ComponentType == "validation" {
+			continue
+		}
+		for _, cio := range componentObject.ControlImpleObjects {
+			controlObjects = append(controlObjects, cio.ControlObjects...)
+		}
+	}
+
+	pvps := []string{}
+	subjects := []typeap.AssessmentSubject{}
+	apTasks := []typeap.Task{}
+	for _, task := range tasks {
+		pvps = append(pvps, task.PVP)
+		props := []typecommon.Prop{{Name: "pvp", Value: task.PVP}}
+		for _, policyId := range task.PolicyIds {
+			props = append(props, typecommon.Prop{Name: "policy-id", Value: policyId})
+		}
+		for _, subject := range task.Subjects {
+			if !slices.ContainsFunc(subjects, func(s typeap.AssessmentSubject) bool { return isSameSubject(s, subject) }) {
+				subjects = append(subjects, subject)
+			}
+		}
+		apTasks = append(apTasks, typeap.Task{
+			UUID:        stamper.UUID("task", cdRoot.ComponentDefinition.UUID, task.PVP),
+			Type:        "action",
+			Title:       fmt.Sprintf("Assessment by %s policies", task.PVP),
+			Description: task.Description,
+			Props:       props,
+			Subjects:    task.Subjects,
+		})
+	}
+
+	cdResource := componentDefinitionResource(cdRoot, cdHref, stamper)
+	ap := typeap.AssessmentPlan{
+		UUID: stamper.UUID(append([]string{"assessment-plan", cdRoot.ComponentDefinition.UUID}, pvps...)...),
+		Metadata: typear.Metadata{
+			Title:        "OSCAL Assessment Plan",
+			LastModified: timestamp,
+			Version:      "0.0.1",
+			OscalVersion: OscalVersion,
+		},
+		ImportSsp: typeap.ImportSsp{
+			Href:    "#" + cdResource.UUID,
+			Remarks: "There is no system security plan. The controls are implemented by the component-definition of the back-matter resource.",
+		},
+		ReviewedControls: typear.ReviewedControl{
+			ControlSelections: []typear.ControlSelection{{
+				IncludeControls: SelectControls(controlObjects),
+			}},
+		},
+		AssessmentSubjects: subjects,
+		Tasks:              apTasks,
+		BackMatter:         &typecommon.BackMatter{Resources: []typecommon.Resource{cdResource}},
+	}
+	return &typeap.AssessmentPlanRoot{AssessmentPlan: ap}, nil
+}
+
+// ImportAssessmentPlan makes the assessment results import the assessment plan by the href.
+// The plan is also referred by its UUID from a resource of the back-matter.
+func ImportAssessmentPlan(ar *typear.AssessmentResults, href string, ap typeap.AssessmentPlan) {
+	ar.ImportAp = typear.ImportAp{Href: href}
+	rlink := typecommon.Rlink{Href: href}
+	if apFormat, ok := format.FromPath(href); ok {
+		rlink.MediaType = fmt.Sprintf("application/oscal.ap+%s", apFormat)
+	}
+	if ar.BackMatter == nil {
+		ar.BackMatter = &typecommon.BackMatter{}
+	}
+	ar.BackMatter.Resources = append(ar.BackMatter.Resources, typecommon.Resource{
+		UUID:   ap.UUID,
+		Title:  ap.Metadata.Title,
+		Rlinks: []typecommon.Rlink{rlink},
+	})
+}
+
+// ImportComponentDefinition makes the assessment results, which are not planned by an assessment plan, refer to
+// the component-definition of the assessed controls by a resource of the back-matter in place of the assessment plan.
+func ImportComponentDefinition(ar *typear.AssessmentResults, cdRoot typecd.ComponentDefinitionRoot, stamper Stamper) {
+	cdResource := componentDefinitionResource(cdRoot, "", stamper)
+	ar.ImportAp = typear.ImportAp{
+		Href:    "#" + cdResource.UUID,
+		Remarks: "There is no assessment plan. The controls are assessed by the policies of the component-definition of the back-matter resource.",
+	}
+	if ar.BackMatter == nil {
+		ar.BackMatter = &typecommon.BackMatter{}
+	}
+	ar.BackMatter.Resources = append(ar.BackMatter.Resources, cdResource)
+}
+
+// componentDefinitionResource returns the resource of the component-definition, linked by the href if it is known.
+func componentDefinitionResource(cdRoot typecd.ComponentDefinitionRoot, cdHref string, stamper Stamper) typecommon.Resource {
+	resource := typecommon.Resource{
+		UUID:        stamper.UUID("component-definition", cdRoot.ComponentDefinition.UUID),
+		Title:       cdRoot.ComponentDefinition.Metadata.Title,
+		Description: fmt.Sprintf("Component-definition %s", cdRoot.ComponentDefinition.UUID),
+	}
+	if cdHref != "" {
+		rlink := typecommon.Rlink{Href: cdHref}
+		if cdFormat, ok := format.FromPath(cdHref); ok {
+			rlink.MediaType = fmt.Sprintf("application/oscal.component-definition+%s", cdFormat)
+		}
+		resource.Rlinks = []typecommon.Rlink{rlink}
+	}
+	return resource
+}
+
+// AssessmentPlanHref returns the href of the assessment plan (apPath) relative to the directory of the assessment results (arPath).
+// URLs are returned as they are.
+func AssessmentPlanHref(arPath string, apPath string) string {
+	if u, err := neturl.Parse(apPath); err == nil && u.Scheme != "" {
+		return apPath
+	}
+	arDir, err := filepath.Abs(filepath.Dir(arPath))
+	if err != nil {
+		return filepath.ToSlash(apPath)
+	}
+	apAbsPath, err := filepath.Abs(apPath)
+	if err != nil {
+		return filepath.ToSlash(apPath)
+	}
+	href, err := filepath.Rel(arDir, apAbsPath)
+	if err != nil {
+		return filepath.ToSlash(apPath)
+	}
+	href = filepath.ToSlash(href)
+	if !strings.HasPrefix(href, "../") {
+		href = "./" + href
+	}
+	return href
+}
+
+func isSameSubject(s1 typeap.AssessmentSubject, s2 typeap.AssessmentSubject) bool {
+	return s1.Type == s2.Type && s1.Description == s2.Description && propsKey(s1.Props) == propsKey(s2.Props)
+}
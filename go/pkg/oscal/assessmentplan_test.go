@@ -0,0 +1,45 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oscal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	typear "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/assessmentresults"
+	typecd "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/componentdefinition"
+)
+
+func TestAssessmentPlanHref(t *testing.T) {
+	assert.Equal(t, "./assessment-plan.json", AssessmentPlanHref("./out/assessment-results.json", "./out/assessment-plan.json"))
+	assert.Equal(t, "./plans/assessment-plan.yaml", AssessmentPlanHref("assessment-results.json", "plans/assessment-plan.yaml"))
+	assert.Equal(t, "../assessment-plan.json", AssessmentPlanHref("./out/assessment-results.json", "./assessment-plan.json"))
+	assert.Equal(t, "https://example.com/assessment-plan.json", AssessmentPlanHref("./assessment-results.json", "https://example.com/assessment-plan.json"))
+}
+
+func TestImportComponentDefinition(t *testing.T) {
+	var cdRoot typecd.ComponentDefinitionRoot
+	cdRoot.ComponentDefinition.UUID = "082c095d-52f3-430e-b6f9-1ce8026b8dc0"
+	cdRoot.ComponentDefinition.Metadata.Title = "OCM"
+
+	ar := typear.AssessmentResults{}
+	ImportComponentDefinition(&ar, cdRoot, NewStamper(true))
+	assert.Len(t, ar.BackMatter.Resources, 1)
+	assert.Equal(t, "#"+ar.BackMatter.Resources[0].UUID, ar.ImportAp.Href)
+	assert.Equal(t, "OCM", ar.BackMatter.Resources[0].Title)
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oscal
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal"
+	cd "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/componentdefinition"
+)
+
+// ControlDescription describes a control (or a statement of a control) by the catalog for the readers of the reports.
+type ControlDescription struct {
+	ControlId  string
+	Title      string
+	GroupId    string
+	GroupTitle string
+	// Prose of the statement (or of the whole statement part of the control) with the values of the parameters inserted
+	Statement string
+}
+
+var insertParamPattern = regexp.MustCompile(`\{\{\s*insert:\s*param,\s*([^\s}]+)\s*\}\}`)
+
+// DescribeControl finds the control in the catalog and describes it.
+// The values of the set-parameters of the component-definition take precedence over the ones in the catalog.
+func DescribeControl(catalog oscal.Catalog, controlId string, statementId string, setParameters []cd.SetParameter) (ControlDescription, bool) {
+	control, ok := FindControl(catalog, controlId)
+	if !ok {
+		return ControlDescription{}, false
+	}
+	description := ControlDescription{
+		ControlId: controlId,
+		Title:     control.Title,
+	}
+	if group, ok := findControlGroup(controlId, catalog); ok {
+		description.GroupId = group.ID
+		description.GroupTitle = group.Title
+	}
+	values := parameterValues(catalog, setParameters)
+	lines := []string{}
+	for _, part := range control.Parts {
+		if part.Name != "statement" {
+			continue
+		}
+		if statementId == "" {
+			renderPart(part, values, &lines)
+		} else if found := findPart([]oscal.Part{part}, statementId); found != nil {
+			renderPart(*found, values, &lines)
+		}
+	}
+	description.Statement = strings.Join(lines, "\n")
+	return description, true
+}
+
+func findPart(parts []oscal.Part, id string) *oscal.Part {
+	for idx := range parts {
+		if parts[idx].ID == id {
+			return &parts[idx]
+		}
+		if found := findPart(parts[idx].Parts, id); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// renderPart renders the prose of the part and its sub-parts, one line per part prefixed by its label (e.g. "a.").
+func renderPart(part oscal.Part, values map[string]string, lines *[]string) {
+	prose := insertParamPattern.ReplaceAllStringFunc(part.Prose, func(insert string) string {
+		paramId := insertParamPattern.FindStringSubmatch(insert)[1]
+		if value, ok := values[paramId]; ok {
+			return value
+		}
+		return "[Assignment: " + paramId + "]"
+	})
+	if label, ok := FindProp("label", part.Props); ok && prose != "" {
+		prose = label.Value + " " + prose
+	}
+	if prose != "" {
+		*lines = append(*lines, prose)
+	}
+	for _, subPart := range part.Parts {
+		renderPart(subPart, values, lines)
+	}
+}
+
+// parameterValues returns the text inserted into the prose for each parameter of the catalog.
+// Parameters without values are rendered as the assignment or the selection to be made as in the published catalogs.
+func parameterValues(catalog oscal.Catalog, setParameters []cd.SetParameter) map[string]string {
+	values := map[string]string{}
+	add := func(params []oscal.Parameter) {
+		for _, param := range params {
+			switch {
+			case len(param.Values) > 0:
+				values[param.ID] = strings.Join(param.Values, ", ")
+			case param.Select != nil:
+				selection := "Selection"
+				if param.Select.HowMany == "one-or-more" {
+					selection = "Selection (one or more)"
+				}
+				values[param.ID] = "[" + selection + ": " + strings.Join(param.Select.Choice, "; ") + "]"
+			case param.Label != "":
+				values[param.ID] = "[Assignment: " + param.Label + "]"
+			}
+		}
+	}
+	add(catalog.Params)
+	walkGroups(catalog.Groups, func(group oscal.Group) {
+		add(group.Params)
+	})
+	walkControls(catalog, func(control oscal.Control) {
+		add(control.Params)
+	})
+	for _, setParameter := range setParameters {
+		if len(setParameter.Values) > 0 {
+			values[setParameter.ParamID] = strings.Join(setParameter.Values, ", ")
+		}
+	}
+	return values
+}
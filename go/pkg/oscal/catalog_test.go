@@ -0,0 +1,66 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oscal
+
+import (
+	"testing"
+
+	"github.com/oscal-compass/compliance-to-policy/go/pkg"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal"
+	cd "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/componentdefinition"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDescribeControl(t *testing.T) {
+	var catalogRoot oscal.CatalogRoot
+	err := pkg.LoadJsonFileToObject(pkg.PathFromPkgDirectory("./oscal/testdata/profile-resolution/catalog.json"), &catalogRoot)
+	assert.NoError(t, err, "Should not happen")
+
+	description, found := DescribeControl(catalogRoot.Catalog, "ac-2", "", nil)
+	assert.True(t, found)
+	assert.Equal(t, ControlDescription{
+		ControlId:  "ac-2",
+		Title:      "Account Management",
+		GroupId:    "ac",
+		GroupTitle: "Access Control",
+		Statement:  "Review accounts every [Assignment: time period].",
+	}, description)
+
+	setParameters := []cd.SetParameter{{ParamID: "ac-02_odp.01", Values: []string{"90 days"}}}
+	description, found = DescribeControl(catalogRoot.Catalog, "ac-2", "ac-2_smt", setParameters)
+	assert.True(t, found)
+	assert.Equal(t, "Review accounts every 90 days.", description.Statement)
+
+	description, found = DescribeControl(catalogRoot.Catalog, "ac-2.1", "", nil)
+	assert.True(t, found)
+	assert.Equal(t, "Automated System Account Management", description.Title)
+	assert.Equal(t, "ac", description.GroupId)
+	assert.Empty(t, description.Statement)
+
+	_, found = DescribeControl(catalogRoot.Catalog, "sc-7", "", nil)
+	assert.False(t, found)
+}
+
+func TestDescribeControlOfResolvedProfile(t *testing.T) {
+	resolver := newTestProfileResolver()
+	resolved, err := resolver.Resolve(pkg.PathFromPkgDirectory("./oscal/testdata/profile-resolution/profile.json"))
+	assert.NoError(t, err, "Should not happen")
+
+	description, found := DescribeControl(*resolved, "ac-2", "", nil)
+	assert.True(t, found)
+	assert.Equal(t, "Review accounts every 30 days.\nTailored statement.", description.Statement)
+}
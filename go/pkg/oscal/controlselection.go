@@ -0,0 +1,56 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oscal
+
+import (
+	"sort"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	typear "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/assessmentresults"
+)
+
+// SelectControls returns the selections of the controls implemented by the control objects, sorted by control ID.
+// A control implemented by statements is selected with the statement IDs, unless the whole control is also implemented.
+func SelectControls(controlObjects []ControlObject) []typear.SelectControlById {
+	statementIds := map[string]sets.String{}
+	wholeControls := sets.NewString()
+	for _, co := range controlObjects {
+		if _, ok := statementIds[co.ControlId]; !ok {
+			statementIds[co.ControlId] = sets.NewString()
+		}
+		if co.StatementId == "" {
+			wholeControls.Insert(co.ControlId)
+		} else {
+			statementIds[co.ControlId].Insert(co.StatementId)
+		}
+	}
+	controlIds := []string{}
+	for controlId := range statementIds {
+		controlIds = append(controlIds, controlId)
+	}
+	sort.Strings(controlIds)
+	selections := []typear.SelectControlById{}
+	for _, controlId := range controlIds {
+		selection := typear.SelectControlById{ControlID: controlId}
+		if !wholeControls.Has(controlId) {
+			selection.StatementIds = statementIds[controlId].List()
+		}
+		selections = append(selections, selection)
+	}
+	return selections
+}
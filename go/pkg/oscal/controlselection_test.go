@@ -0,0 +1,42 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oscal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	typear "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/assessmentresults"
+)
+
+func TestSelectControls(t *testing.T) {
+	controlObjects := []ControlObject{
+		{ControlId: "cm-6"},
+		{ControlId: "ac-1", StatementId: "ac-1_smt.b"},
+		{ControlId: "ac-1", StatementId: "ac-1_smt.a"},
+		{ControlId: "ac-2", StatementId: "ac-2_smt.a"},
+		{ControlId: "ac-2"},
+		{ControlId: "cm-6"},
+	}
+	assert.Equal(t, []typear.SelectControlById{
+		{ControlID: "ac-1", StatementIds: []string{"ac-1_smt.a", "ac-1_smt.b"}},
+		// The whole control is implemented
+		{ControlID: "ac-2"},
+		{ControlID: "cm-6"},
+	}, SelectControls(controlObjects))
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oscal
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal"
+	cd "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/componentdefinition"
+)
+
+type CoverageOptions struct {
+	// PVP (ocm or kyverno) whose conventions map the rules to the policies
+	PVP string
+	// Directory containing a directory of the policy resources for each policy (ocm) or rule (kyverno). Empty if not checked.
+	PolicyDir string
+}
+
+// FamilyCoverage is the coverage of the controls of a group (family) of the catalog required by the profile.
+type FamilyCoverage struct {
+	GroupId    string `json:"groupId"`
+	GroupTitle string `json:"groupTitle,omitempty"`
+	// Number of the controls required by the profile
+	Required int `json:"required"`
+	// Number of the required controls implemented in the component-definition
+	Implemented int `json:"implemented"`
+	// Number of the required controls implemented by rules
+	WithRules int `json:"withRules"`
+	// Percentage of the implemented controls
+	ImplementedPercent float64 `json:"implementedPercent"`
+	// Percentage of the controls implemented by rules
+	WithRulesPercent float64 `json:"withRulesPercent"`
+}
+
+// RuleWithoutPolicy is a rule of the component-definition whose policies are not in the policy directory.
+type RuleWithoutPolicy struct {
+	Component string `json:"component"`
+	RuleId    string `json:"ruleId"`
+	// IDs of the policies of the rule missing in the policy directory. Empty if the rule has no policy ID.
+	PolicyIds []string `json:"policyIds"`
+}
+
+type CoverageReport struct {
+	Families []FamilyCoverage `json:"families"`
+	Total    FamilyCoverage   `json:"total"`
+	// Controls required by the profile but not implemented in the component-definition
+	NotImplementedControls []string `json:"notImplementedControls"`
+	// Controls implemented in the component-definition with no rules
+	ControlsWithoutRules []string            `json:"controlsWithoutRules"`
+	RulesWithoutPolicy   []RuleWithoutPolicy `json:"rulesWithoutPolicy"`
+	// Policies in the policy directory referred by no rules. Not checked if the policy directory is not specified.
+	UnreferencedPolicies []string `json:"unreferencedPolicies"`
+}
+
+// AnalyzeCoverage reports the gaps between the controls required by the profile, the controls and rules of the component-definition and the policies.
+// The families of the controls are looked up in the catalog, or in the resolved profile if the catalog is nil.
+func AnalyzeCoverage(resolvedProfile oscal.Catalog, catalog *oscal.Catalog, cdRoot cd.ComponentDefinitionRoot, options CoverageOptions) (CoverageReport, error) {
+	report := CoverageReport{
+		Families:               []FamilyCoverage{},
+		NotImplementedControls: []string{},
+		ControlsWithoutRules:   []string{},
+		RulesWithoutPolicy:     []RuleWithoutPolicy{},
+		UnreferencedPolicies:   []string{},
+	}
+	if catalog == nil {
+		catalog = &resolvedProfile
+	}
+
+	implemented := map[string]bool{}
+	withRules := map[string]bool{}
+	implementedControlIds := []string{}
+	policyIds := map[string]bool{}
+	componentObjects := ParseComponentDefinition(cdRoot)
+	for _, componentObject := range componentObjects {
+		if componentObject.ComponentType == "validation" {
+			continue
+		}
+		for _, cio := range componentObject.ControlImpleObjects {
+			for _, co := range cio.ControlObjects {
+				if !implemented[co.ControlId] {
+					implementedControlIds = append(implementedControlIds, co.ControlId)
+				}
+				implemented[co.ControlId] = true
+				if len(co.RuleIds) > 0 {
+					withRules[co.ControlId] = true
+				}
+			}
+		}
+		for _, rule := range componentObject.RuleObjects {
+			if rule.RuleId == "" {
+				continue
+			}
+			ids := policyIdsOfRule(options.PVP, rule)
+			missing := []string{}
+			for _, policyId := range ids {
+				policyIds[policyId] = true
+				if options.PolicyDir != "" && !isDirectory(filepath.Join(options.PolicyDir, policyId)) {
+					missing = append(missing, policyId)
+				}
+			}
+			if len(ids) == 0 || len(missing) > 0 {
+				report.RulesWithoutPolicy = append(report.RulesWithoutPolicy, RuleWithoutPolicy{
+					Component: componentObject.ComponentTitle,
+					RuleId:    rule.RuleId,
+					PolicyIds: missing,
+				})
+			}
+		}
+	}
+	for _, controlId := range implementedControlIds {
+		if !withRules[controlId] {
+			report.ControlsWithoutRules = append(report.ControlsWithoutRules, controlId)
+		}
+	}
+
+	families := map[string]*FamilyCoverage{}
+	for _, controlId := range ListControlIds(resolvedProfile) {
+		group, ok := findControlGroup(controlId, *catalog)
+		if !ok {
+			group = oscal.Group{ID: "unknown", Title: "unknown"}
+		}
+		family, ok := families[group.ID]
+		if !ok {
+			family = &FamilyCoverage{GroupId: group.ID, GroupTitle: group.Title}
+			families[group.ID] = family
+		}
+		family.Required++
+		report.Total.Required++
+		if !implemented[controlId] {
+			report.NotImplementedControls = append(report.NotImplementedControls, controlId)
+			continue
+		}
+		family.Implemented++
+		report.Total.Implemented++
+		if withRules[controlId] {
+			family.WithRules++
+			report.Total.WithRules++
+		}
+	}
+	for _, family := range families {
+		family.computePercents()
+		report.Families = append(report.Families, *family)
+	}
+	sort.Slice(report.Families, func(i, j int) bool {
+		return report.Families[i].GroupId < report.Families[j].GroupId
+	})
+	report.Total.GroupId = "total"
+	report.Total.computePercents()
+
+	if options.PolicyDir != "" {
+		entries, err := os.ReadDir(options.PolicyDir)
+		if err != nil {
+			return report, err
+		}
+		for _, entry := range entries {
+			if entry.IsDir() && !policyIds[entry.Name()] {
+				report.UnreferencedPolicies = append(report.UnreferencedPolicies, entry.Name())
+			}
+		}
+		slices.Sort(report.UnreferencedPolicies)
+	}
+	return report, nil
+}
+
+func (f *FamilyCoverage) computePercents() {
+	if f.Required == 0 {
+		return
+	}
+	f.ImplementedPercent = percent(f.Implemented, f.Required)
+	f.WithRulesPercent = percent(f.WithRules, f.Required)
+}
+
+// percent rounds the percentage to one decimal place.
+func percent(count int, total int) float64 {
+	return math.Round(float64(count)*1000/float64(total)) / 10
+}
+
+func isDirectory(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oscal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/oscal-compass/compliance-to-policy/go/pkg"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal"
+	cd "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/componentdefinition"
+)
+
+func TestAnalyzeCoverage(t *testing.T) {
+	resolver := newTestProfileResolver()
+	resolved, err := resolver.Resolve(pkg.PathFromPkgDirectory("./oscal/testdata/profile-resolution/profile.json"))
+	assert.NoError(t, err, "Should not happen")
+	var catalogRoot oscal.CatalogRoot
+	err = pkg.LoadOscalFileToObject(pkg.PathFromPkgDirectory("./oscal/testdata/profile-resolution/catalog.json"), &catalogRoot)
+	assert.NoError(t, err, "Should not happen")
+	var cdRoot cd.ComponentDefinitionRoot
+	err = pkg.LoadOscalFileToObject(pkg.PathFromPkgDirectory("./oscal/testdata/coverage/component-definition.json"), &cdRoot)
+	assert.NoError(t, err, "Should not happen")
+
+	report, err := AnalyzeCoverage(*resolved, &catalogRoot.Catalog, cdRoot, CoverageOptions{
+		PVP:       LintPVPOcm,
+		PolicyDir: pkg.PathFromPkgDirectory("./oscal/testdata/coverage/policies"),
+	})
+	assert.NoError(t, err, "Should not happen")
+
+	assert.Equal(t, []FamilyCoverage{
+		{GroupId: "ac", GroupTitle: "Access Control", Required: 2, Implemented: 1, WithRules: 1, ImplementedPercent: 50, WithRulesPercent: 50},
+		{GroupId: "cm", GroupTitle: "Configuration Management", Required: 1, Implemented: 1, WithRules: 0, ImplementedPercent: 100, WithRulesPercent: 0},
+	}, report.Families)
+	assert.Equal(t, FamilyCoverage{GroupId: "total", Required: 3, Implemented: 2, WithRules: 1, ImplementedPercent: 66.7, WithRulesPercent: 33.3}, report.Total)
+	assert.Equal(t, []string{"ac-2.1"}, report.NotImplementedControls)
+	assert.Equal(t, []string{"cm-6"}, report.ControlsWithoutRules)
+	assert.Equal(t, []RuleWithoutPolicy{
+		{Component: "Coverage Test", RuleId: "rule_b", PolicyIds: []string{"policy-b"}},
+		{Component: "Coverage Test", RuleId: "rule_c", PolicyIds: []string{}},
+	}, report.RulesWithoutPolicy)
+	assert.Equal(t, []string{"policy-unused"}, report.UnreferencedPolicies)
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oscal
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	typear "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/assessmentresults"
+	typecommon "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/common"
+)
+
+// SourceDateEpochEnv is the environment variable overriding the timestamps of the generated documents
+// by seconds since the Unix epoch (https://reproducible-builds.org/specs/source-date-epoch/).
+const SourceDateEpochEnv = "SOURCE_DATE_EPOCH"
+
+// Namespace of the UUIDs derived from the keys of the OSCAL objects
+var uuidNamespace = uuid.NewSHA1(uuid.NameSpaceURL, []byte("https://github.com/oscal-compass/compliance-to-policy"))
+
+// Stamper makes the UUIDs and timestamps of the generated OSCAL documents.
+// In deterministic mode, the same inputs produce the same document: UUIDs are derived (version 5) from stable keys
+// identifying the objects (e.g. component, control, rule and subject), and timestamps are taken from the inputs.
+type Stamper struct {
+	deterministic bool
+}
+
+func NewStamper(deterministic bool) Stamper {
+	return Stamper{deterministic: deterministic}
+}
+
+func (s Stamper) IsDeterministic() bool {
+	return s.deterministic
+}
+
+// UUID returns the UUID of the object identified by the keys, or a random UUID if not deterministic.
+func (s Stamper) UUID(keys ...string) string {
+	if !s.deterministic {
+		return GenerateUUID()
+	}
+	return uuid.NewSHA1(uuidNamespace, []byte(strings.Join(keys, "\x00"))).String()
+}
+
+// Timestamp returns the timestamp of the generated document.
+// SOURCE_DATE_EPOCH takes precedence if set. Otherwise it is the latest of the timestamps of the inputs
+// (or the Unix epoch if there is none) in deterministic mode, or the current time.
+func (s Stamper) Timestamp(inputTimestamps ...time.Time) (time.Time, error) {
+	if epoch, ok := os.LookupEnv(SourceDateEpochEnv); ok && epoch != "" {
+		seconds, err := strconv.ParseInt(epoch, 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid %s '%s': must be seconds since the Unix epoch", SourceDateEpochEnv, epoch)
+		}
+		return time.Unix(seconds, 0).UTC(), nil
+	}
+	if !s.deterministic {
+		return time.Now(), nil
+	}
+	latest := time.Unix(0, 0).UTC()
+	for _, timestamp := range inputTimestamps {
+		if timestamp.After(latest) {
+			latest = timestamp.UTC()
+		}
+	}
+	return latest, nil
+}
+
+// SortAssessmentResults sorts the collections of the results so that they are emitted in a stable order.
+func SortAssessmentResults(ar *typear.AssessmentResults) {
+	for idx := range ar.Results {
+		result := &ar.Results[idx]
+		items := result.LocalDefinitions.InventoryItems
+		sort.SliceStable(items, func(i, j int) bool {
+			return less(propsKey(items[i].Props), propsKey(items[j].Props), items[i].UUID, items[j].UUID)
+		})
+		for idx := range result.ReviewedControls.ControlSelections {
+			controls := result.ReviewedControls.ControlSelections[idx].IncludeControls
+			sort.SliceStable(controls, func(i, j int) bool {
+				return controls[i].ControlID < controls[j].ControlID
+			})
+		}
+		observations := result.Observations
+		for idx := range observations {
+			subjects := observations[idx].Subjects
+			sort.SliceStable(subjects, func(i, j int) bool {
+				return less(subjects[i].Title, subjects[j].Title, subjects[i].SubjectUUID, subjects[j].SubjectUUID)
+			})
+		}
+		sort.SliceStable(observations, func(i, j int) bool {
+			return less(observations[i].Description, observations[j].Description, propsKey(observations[i].Props), propsKey(observations[j].Props))
+		})
+		findings := result.Findings
+		for idx := range findings {
+			relatedObservations := findings[idx].RelatedObservations
+			sort.SliceStable(relatedObservations, func(i, j int) bool {
+				return relatedObservations[i].ObservationUUID < relatedObservations[j].ObservationUUID
+			})
+		}
+		sort.SliceStable(findings, func(i, j int) bool {
+			return less(findings[i].Target.TargetId, findings[j].Target.TargetId, findings[i].Description, findings[j].Description)
+		})
+	}
+}
+
+func less(primary1 string, primary2 string, secondary1 string, secondary2 string) bool {
+	if primary1 != primary2 {
+		return primary1 < primary2
+	}
+	return secondary1 < secondary2
+}
+
+func propsKey(props []typecommon.Prop) string {
+	values := []string{}
+	for _, prop := range props {
+		values = append(values, prop.Name+"="+prop.Value)
+	}
+	return strings.Join(values, ",")
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oscal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	typear "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/assessmentresults"
+	typecommon "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/common"
+)
+
+func TestStamperUUID(t *testing.T) {
+	stamper := NewStamper(true)
+	id := stamper.UUID("observation", "component", "ac-1", "rule")
+	assert.Equal(t, id, stamper.UUID("observation", "component", "ac-1", "rule"))
+	assert.NotEqual(t, id, stamper.UUID("observation", "component", "ac-2", "rule"))
+	assert.NotEqual(t, stamper.UUID("ab", "c"), stamper.UUID("a", "bc"))
+	parsed, err := uuid.Parse(id)
+	assert.NoError(t, err, "Should not happen")
+	assert.Equal(t, uuid.Version(5), parsed.Version())
+
+	stamper = NewStamper(false)
+	assert.NotEqual(t, stamper.UUID("observation"), stamper.UUID("observation"))
+}
+
+func TestStamperTimestamp(t *testing.T) {
+	t1 := time.Date(2023, 7, 5, 23, 53, 37, 0, time.UTC)
+	t2 := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	timestamp, err := NewStamper(true).Timestamp(t2, time.Time{}, t1)
+	assert.NoError(t, err, "Should not happen")
+	assert.Equal(t, t2, timestamp)
+
+	timestamp, err = NewStamper(true).Timestamp()
+	assert.NoError(t, err, "Should not happen")
+	assert.Equal(t, time.Unix(0, 0).UTC(), timestamp)
+
+	t.Setenv(SourceDateEpochEnv, "1700000000")
+	timestamp, err = NewStamper(false).Timestamp(t1)
+	assert.NoError(t, err, "Should not happen")
+	assert.Equal(t, time.Unix(1700000000, 0).UTC(), timestamp)
+
+	t.Setenv(SourceDateEpochEnv, "yesterday")
+	_, err = NewStamper(true).Timestamp(t1)
+	assert.Error(t, err)
+}
+
+func TestSortAssessmentResults(t *testing.T) {
+	ar := typear.AssessmentResults{
+		Results: []typear.Result{{
+			ReviewedControls: typear.ReviewedControl{
+				ControlSelections: []typear.ControlSelection{{
+					IncludeControls: []typear.SelectControlById{{ControlID: "cm-6"}, {ControlID: "ac-1"}},
+				}},
+			},
+			Observations: []typear.Observation{{
+				Description: "Observation of rule b",
+				Subjects:    []typear.Subject{{Title: "cluster2"}, {Title: "cluster1"}},
+			}, {
+				Description: "Observation of rule a",
+				Props:       []typecommon.Prop{{Name: "control-id", Value: "cm-6"}},
+			}, {
+				Description: "Observation of rule a",
+				Props:       []typecommon.Prop{{Name: "control-id", Value: "ac-1"}},
+			}},
+			Findings: []typear.Finding{{
+				Target: typear.FindingTarget{TargetId: "cm-6_smt"},
+			}, {
+				Target: typear.FindingTarget{TargetId: "ac-1_smt"},
+			}},
+		}},
+	}
+	SortAssessmentResults(&ar)
+
+	result := ar.Results[0]
+	assert.Equal(t, []typear.SelectControlById{{ControlID: "ac-1"}, {ControlID: "cm-6"}}, result.ReviewedControls.ControlSelections[0].IncludeControls)
+	assert.Equal(t, "ac-1", result.Observations[0].Props[0].Value)
+	assert.Equal(t, "cm-6", result.Observations[1].Props[0].Value)
+	assert.Equal(t, "Observation of rule b", result.Observations[2].Description)
+	assert.Equal(t, "cluster1", result.Observations[2].Subjects[0].Title)
+	assert.Equal(t, "ac-1_smt", result.Findings[0].Target.TargetId)
+}
@@ -0,0 +1,267 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oscal
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	typear "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/assessmentresults"
+)
+
+// AssessmentResultsDiff is the drift of compliance between two assessment results.
+type AssessmentResultsDiff struct {
+	// Controls which are not satisfied in the new assessment results but were satisfied (or not assessed) in the old one
+	NewlyFailingControls []ControlDiff `json:"newly-failing-controls"`
+	// Controls which are satisfied in the new assessment results but were not satisfied in the old one
+	FixedControls   []ControlDiff `json:"fixed-controls"`
+	NewSubjects     []SubjectDiff `json:"new-subjects"`
+	RemovedSubjects []SubjectDiff `json:"removed-subjects"`
+	// Subjects whose result, reason or controls are changed
+	ChangedSubjects []SubjectDiff `json:"changed-subjects"`
+}
+
+type ControlDiff struct {
+	ControlId   string `json:"control-id"`
+	StatementId string `json:"statement-id,omitempty"`
+	OldState    string `json:"old-state,omitempty"`
+	NewState    string `json:"new-state"`
+}
+
+type SubjectDiff struct {
+	RuleId   string `json:"rule-id"`
+	CheckId  string `json:"check-id,omitempty"`
+	PolicyId string `json:"policy-id,omitempty"`
+	Subject  string `json:"subject"`
+	// Controls (or statements) of the observations of the subject, in the new assessment results unless the subject is removed
+	Controls []string `json:"controls,omitempty"`
+	// Controls of the observations of the subject in the old assessment results if they are changed
+	OldControls []string `json:"old-controls,omitempty"`
+	OldResult   string   `json:"old-result,omitempty"`
+	NewResult   string   `json:"new-result,omitempty"`
+	OldReason   string   `json:"old-reason,omitempty"`
+	NewReason   string   `json:"new-reason,omitempty"`
+	// The subject newly fails
+	Regression bool `json:"regression"`
+}
+
+// Regressions returns the number of the newly failing controls and subjects.
+func (d AssessmentResultsDiff) Regressions() int {
+	regressions := len(d.NewlyFailingControls)
+	for _, subjects := range [][]SubjectDiff{d.NewSubjects, d.ChangedSubjects} {
+		for _, subject := range subjects {
+			if subject.Regression {
+				regressions++
+			}
+		}
+	}
+	return regressions
+}
+
+type controlKey struct {
+	controlId   string
+	statementId string
+}
+
+// subjectKey identifies a subject evaluated by a rule (or check or policy) across assessment results.
+// The subject is identified by the title, or by the UUID if the subject has no title, because the UUIDs of some subjects
+// (e.g. the inventory items of OCM) are regenerated by each run unless --deterministic is set.
+type subjectKey struct {
+	ruleId   string
+	checkId  string
+	policyId string
+	subject  string
+}
+
+type subjectState struct {
+	result   string
+	reason   string
+	controls []string
+}
+
+// DiffAssessmentResults compares the results of two assessment results.
+// Controls are matched by the control-id and statement-id of the findings, and the subjects of the observations are matched
+// by the rule, check and policy of the observation and the title (or UUID) of the subject, rather than by the UUIDs of
+// the observations. The controls of the observations of a subject are compared separately.
+func DiffAssessmentResults(oldAr typear.AssessmentResultsRoot, newAr typear.AssessmentResultsRoot) AssessmentResultsDiff {
+	diff := AssessmentResultsDiff{
+		NewlyFailingControls: []ControlDiff{},
+		FixedControls:        []ControlDiff{},
+		NewSubjects:          []SubjectDiff{},
+		RemovedSubjects:      []SubjectDiff{},
+		ChangedSubjects:      []SubjectDiff{},
+	}
+
+	oldControls, newControls := controlStates(oldAr), controlStates(newAr)
+	for _, key := range sortedControlKeys(newControls) {
+		newState := newControls[key]
+		oldState, ok := oldControls[key]
+		controlDiff := ControlDiff{ControlId: key.controlId, StatementId: key.statementId, OldState: oldState, NewState: newState}
+		if newState == FindingStateNotSatisfied && (!ok || oldState == FindingStateSatisfied) {
+			diff.NewlyFailingControls = append(diff.NewlyFailingControls, controlDiff)
+		} else if newState == FindingStateSatisfied && oldState == FindingStateNotSatisfied {
+			diff.FixedControls = append(diff.FixedControls, controlDiff)
+		}
+	}
+
+	oldSubjects, newSubjects := subjectStates(oldAr), subjectStates(newAr)
+	for _, key := range sortedSubjectKeys(newSubjects) {
+		newState := newSubjects[key]
+		subjectDiff := toSubjectDiff(key, newState)
+		subjectDiff.NewResult, subjectDiff.NewReason = newState.result, newState.reason
+		oldState, ok := oldSubjects[key]
+		if !ok {
+			subjectDiff.Regression = newState.result == "fail"
+			diff.NewSubjects = append(diff.NewSubjects, subjectDiff)
+			continue
+		}
+		controlsChanged := !slices.Equal(oldState.controls, newState.controls)
+		if oldState.result != newState.result || oldState.reason != newState.reason || controlsChanged {
+			subjectDiff.OldResult, subjectDiff.OldReason = oldState.result, oldState.reason
+			if controlsChanged {
+				subjectDiff.OldControls = oldState.controls
+			}
+			subjectDiff.Regression = newState.result == "fail" && oldState.result != "fail"
+			diff.ChangedSubjects = append(diff.ChangedSubjects, subjectDiff)
+		}
+	}
+	for _, key := range sortedSubjectKeys(oldSubjects) {
+		if _, ok := newSubjects[key]; !ok {
+			oldState := oldSubjects[key]
+			subjectDiff := toSubjectDiff(key, oldState)
+			subjectDiff.OldResult, subjectDiff.OldReason = oldState.result, oldState.reason
+			diff.RemovedSubjects = append(diff.RemovedSubjects, subjectDiff)
+		}
+	}
+	return diff
+}
+
+// controlStates returns the states of the controls (or statements). A control is not satisfied if any of its findings is not satisfied.
+func controlStates(arRoot typear.AssessmentResultsRoot) map[controlKey]string {
+	states := map[controlKey]string{}
+	for _, result := range arRoot.AssessmentResults.Results {
+		for _, finding := range result.Findings {
+			key := controlKey{controlId: strings.TrimSuffix(finding.Target.TargetId, "_smt")}
+			if prop, ok := FindProp("control-id", finding.Props); ok {
+				key.controlId = prop.Value
+				if prop, ok := FindProp("statement-id", finding.Props); ok {
+					key.statementId = prop.Value
+				}
+			}
+			if states[key] != FindingStateNotSatisfied {
+				states[key] = finding.Target.Status.State
+			}
+		}
+	}
+	return states
+}
+
+// subjectStates returns the states of the subjects. The subjects of the observations of the same rule, check and policy
+// (e.g. the observations of a rule per control) are merged: a subject fails if it fails in any of them.
+func subjectStates(arRoot typear.AssessmentResultsRoot) map[subjectKey]subjectState {
+	states := map[subjectKey]subjectState{}
+	for _, result := range arRoot.AssessmentResults.Results {
+		for _, observation := range result.Observations {
+			propValue := func(name string) string {
+				prop, _ := FindProp(name, observation.Props)
+				return prop.Value
+			}
+			key := subjectKey{
+				ruleId:   propValue("assessment-rule-id"),
+				checkId:  propValue("check-id"),
+				policyId: propValue("policy-id"),
+			}
+			controls := observationControls(propValue)
+			for _, subject := range observation.Subjects {
+				key.subject = subject.Title
+				if key.subject == "" {
+					key.subject = subject.SubjectUUID
+				}
+				state := subjectState{result: propValue("result"), reason: propValue("reason")}
+				if prop, ok := FindProp("result", subject.Props); ok {
+					state.result = prop.Value
+				}
+				if prop, ok := FindProp("reason", subject.Props); ok {
+					state.reason = prop.Value
+				}
+				state.controls = controls
+				if merged, ok := states[key]; ok {
+					if merged.result == "fail" && state.result != "fail" {
+						state.result, state.reason = merged.result, merged.reason
+					}
+					state.controls = append(append([]string{}, merged.controls...), controls...)
+				}
+				slices.Sort(state.controls)
+				state.controls = slices.Compact(state.controls)
+				states[key] = state
+			}
+		}
+	}
+	return states
+}
+
+// observationControls returns the statement or control of the observation (OCM), or the controls of the rule (Kyverno and VAP).
+func observationControls(propValue func(name string) string) []string {
+	if statementId := propValue("statement-id"); statementId != "" {
+		return []string{statementId}
+	}
+	if controlId := propValue("control-id"); controlId != "" {
+		return []string{controlId}
+	}
+	controls := []string{}
+	for _, controlId := range strings.Split(propValue("controls"), ",") {
+		if controlId != "" {
+			controls = append(controls, controlId)
+		}
+	}
+	return controls
+}
+
+func toSubjectDiff(key subjectKey, state subjectState) SubjectDiff {
+	return SubjectDiff{
+		RuleId:   key.ruleId,
+		CheckId:  key.checkId,
+		PolicyId: key.policyId,
+		Subject:  key.subject,
+		Controls: state.controls,
+	}
+}
+
+func sortedControlKeys(states map[controlKey]string) []controlKey {
+	keys := []controlKey{}
+	for key := range states {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return less(keys[i].controlId, keys[j].controlId, keys[i].statementId, keys[j].statementId)
+	})
+	return keys
+}
+
+func sortedSubjectKeys(states map[subjectKey]subjectState) []subjectKey {
+	keys := []subjectKey{}
+	for key := range states {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		ki, kj := keys[i], keys[j]
+		return strings.Join([]string{ki.ruleId, ki.checkId, ki.policyId, ki.subject}, "\x00") <
+			strings.Join([]string{kj.ruleId, kj.checkId, kj.policyId, kj.subject}, "\x00")
+	})
+	return keys
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oscal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	typear "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/assessmentresults"
+	typecommon "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/common"
+)
+
+func TestDiffAssessmentResults(t *testing.T) {
+	controls := []typear.SelectControlById{{ControlID: "ac-1"}, {ControlID: "cm-6"}}
+	oldAr := makeTestAssessmentResults("ar-1", "inventory-1", "observation-1", "pass", controls, []typear.Finding{
+		makeTestFinding("ac-1_smt", FindingStateSatisfied, "pass", "observation-1"),
+		makeTestFinding("cm-6_smt", FindingStateNotSatisfied, "fail", "observation-1"),
+	})
+	oldAr.AssessmentResults.Results[0].Observations[0].Props = append(oldAr.AssessmentResults.Results[0].Observations[0].Props, typecommon.Prop{Name: "controls", Value: "ac-1"})
+	oldAr.AssessmentResults.Results[0].Observations[0].Subjects = append(oldAr.AssessmentResults.Results[0].Observations[0].Subjects, typear.Subject{
+		Title: "Cluster Name: cluster3",
+		Props: []typecommon.Prop{{Name: "result", Value: "fail"}},
+	})
+	newAr := makeTestAssessmentResults("ar-2", "inventory-2", "observation-2", "fail", controls, []typear.Finding{
+		makeTestFinding("ac-1_smt", FindingStateNotSatisfied, "fail", "observation-2"),
+		makeTestFinding("cm-6_smt", FindingStateSatisfied, "pass", "observation-2"),
+		makeTestFinding("cm-8_smt", FindingStateSatisfied, "pass", "observation-2"),
+	})
+	newAr.AssessmentResults.Results[0].Observations[0].Props = append(newAr.AssessmentResults.Results[0].Observations[0].Props, typecommon.Prop{Name: "controls", Value: "ac-1,cm-6"})
+	newAr.AssessmentResults.Results[0].Observations[0].Subjects = append(newAr.AssessmentResults.Results[0].Observations[0].Subjects, typear.Subject{
+		Title: "Cluster Name: cluster2",
+		Props: []typecommon.Prop{{Name: "result", Value: "pass"}},
+	})
+
+	diff := DiffAssessmentResults(oldAr, newAr)
+	assert.Equal(t, []ControlDiff{{ControlId: "ac-1", OldState: FindingStateSatisfied, NewState: FindingStateNotSatisfied}}, diff.NewlyFailingControls)
+	assert.Equal(t, []ControlDiff{{ControlId: "cm-6", OldState: FindingStateNotSatisfied, NewState: FindingStateSatisfied}}, diff.FixedControls)
+	assert.Equal(t, []SubjectDiff{{RuleId: "rule_a", Subject: "Cluster Name: cluster2", Controls: []string{"ac-1", "cm-6"}, NewResult: "pass"}}, diff.NewSubjects)
+	assert.Equal(t, []SubjectDiff{{RuleId: "rule_a", Subject: "Cluster Name: cluster3", Controls: []string{"ac-1"}, OldResult: "fail"}}, diff.RemovedSubjects)
+	assert.Equal(t, []SubjectDiff{{
+		RuleId: "rule_a", Subject: "Cluster Name: cluster1", Controls: []string{"ac-1", "cm-6"}, OldControls: []string{"ac-1"},
+		OldResult: "pass", NewResult: "fail", Regression: true,
+	}}, diff.ChangedSubjects)
+	assert.Equal(t, 2, diff.Regressions())
+
+	diff = DiffAssessmentResults(newAr, newAr)
+	assert.Empty(t, diff.NewlyFailingControls)
+	assert.Empty(t, diff.ChangedSubjects)
+	assert.Equal(t, 0, diff.Regressions())
+}
+
+func TestDiffAssessmentResultsWithObservationsPerControl(t *testing.T) {
+	// OCM makes an observation of a rule per control
+	makeAr := func(results ...string) typear.AssessmentResultsRoot {
+		ar := makeTestAssessmentResults("ar", "inventory-1", "observation-1", results[0], nil, nil)
+		observation := ar.AssessmentResults.Results[0].Observations[0]
+		ar.AssessmentResults.Results[0].Observations = []typear.Observation{}
+		for idx, controlId := range []string{"ac-6", "cm-2"} {
+			o := observation
+			o.Props = []typecommon.Prop{{Name: "assessment-rule-id", Value: "rule_a"}, {Name: "control-id", Value: controlId}}
+			o.Subjects = []typear.Subject{{SubjectUUID: "inventory-1", Title: "Cluster Name: cluster1", Props: []typecommon.Prop{{Name: "result", Value: results[idx]}}}}
+			ar.AssessmentResults.Results[0].Observations = append(ar.AssessmentResults.Results[0].Observations, o)
+		}
+		return ar
+	}
+
+	diff := DiffAssessmentResults(makeAr("pass", "pass"), makeAr("pass", "fail"))
+	assert.Empty(t, diff.NewSubjects)
+	assert.Empty(t, diff.RemovedSubjects)
+	assert.Equal(t, []SubjectDiff{{
+		RuleId: "rule_a", Subject: "Cluster Name: cluster1", Controls: []string{"ac-6", "cm-2"},
+		OldResult: "pass", NewResult: "fail", Regression: true,
+	}}, diff.ChangedSubjects)
+}
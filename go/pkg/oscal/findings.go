@@ -0,0 +1,173 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oscal
+
+import (
+	"fmt"
+
+	typear "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/assessmentresults"
+	typecommon "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/common"
+)
+
+// AggregationRule determines the status of a control from the results of the observations of its rules.
+type AggregationRule string
+
+const (
+	// The control is satisfied if all the results are pass
+	AggregationRuleAllPass AggregationRule = "all-pass"
+	// The control is satisfied if any of the results is pass
+	AggregationRuleAnyPass AggregationRule = "any-pass"
+	// The control is satisfied if none of the results is fail (e.g. error or warn results are not counted)
+	AggregationRuleNoFail AggregationRule = "no-fail"
+)
+
+var AggregationRules = []AggregationRule{AggregationRuleAllPass, AggregationRuleAnyPass, AggregationRuleNoFail}
+
+const (
+	FindingStateSatisfied    = "satisfied"
+	FindingStateNotSatisfied = "not-satisfied"
+)
+
+func ParseAggregationRule(s string) (AggregationRule, error) {
+	for _, rule := range AggregationRules {
+		if string(rule) == s {
+			return rule, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported aggregation rule %s: must be one of %v", s, AggregationRules)
+}
+
+// Aggregate returns the state of the objective status ("satisfied" or "not-satisfied") and the reason ("pass", "fail" or "other").
+// Skipped results are not counted. The reason is "other" if there is no result to be aggregated.
+func (a AggregationRule) Aggregate(results []string) (string, string) {
+	pass, fail, other := 0, 0, 0
+	for _, result := range results {
+		switch result {
+		case "pass":
+			pass++
+		case "fail":
+			fail++
+		case "skip":
+		default:
+			other++
+		}
+	}
+	if pass+fail+other == 0 {
+		return FindingStateNotSatisfied, "other"
+	}
+	var satisfied bool
+	switch a {
+	case AggregationRuleAnyPass:
+		satisfied = pass > 0
+	case AggregationRuleNoFail:
+		satisfied = fail == 0
+	default:
+		satisfied = fail == 0 && other == 0
+	}
+	if satisfied {
+		return FindingStateSatisfied, "pass"
+	}
+	return FindingStateNotSatisfied, "fail"
+}
+
+// observationResults returns the results of the subjects of the observation, or the result of the observation itself if the subjects have no result.
+func observationResults(observation typear.Observation) []string {
+	results := []string{}
+	for _, subject := range observation.Subjects {
+		if prop, ok := FindProp("result", subject.Props); ok {
+			results = append(results, prop.Value)
+		}
+	}
+	if len(results) == 0 {
+		if prop, ok := FindProp("result", observation.Props); ok {
+			results = append(results, prop.Value)
+		}
+	}
+	return results
+}
+
+// IsObservationOf returns true if the observation is of a rule of the control object.
+// The control-id and statement-id props of the observation, if any, must match the control object.
+func IsObservationOf(observation typear.Observation, controlObject ControlObject) bool {
+	ruleProp, ok := FindProp("assessment-rule-id", observation.Props)
+	if !ok {
+		return false
+	}
+	if controlProp, ok := FindProp("control-id", observation.Props); ok && controlProp.Value != controlObject.ControlId {
+		return false
+	}
+	if statementProp, ok := FindProp("statement-id", observation.Props); ok && statementProp.Value != controlObject.StatementId {
+		return false
+	}
+	for _, ruleId := range controlObject.RuleIds {
+		if ruleId == ruleProp.Value {
+			return true
+		}
+	}
+	return false
+}
+
+// GenerateFindings returns a finding for each control (or statement) implemented by the components.
+// The status of the finding is aggregated from the results of the observations of the rules of the control.
+func GenerateFindings(componentObjects []ComponentObject, observations []typear.Observation, aggregationRule AggregationRule, stamper Stamper) []typear.Finding {
+	findings := []typear.Finding{}
+	for _, componentObject := range componentObjects {
+		if componentObject.ComponentType == "validation" {
+			continue
+		}
+		for _, cio := range componentObject.ControlImpleObjects {
+			for _, co := range cio.ControlObjects {
+				relatedObservations := []typear.RelatedObservation{}
+				results := []string{}
+				for _, observation := range observations {
+					if IsObservationOf(observation, co) {
+						relatedObservations = append(relatedObservations, typear.RelatedObservation{ObservationUUID: observation.UUID})
+						results = append(results, observationResults(observation)...)
+					}
+				}
+				state, reason := aggregationRule.Aggregate(results)
+				targetId := co.StatementId
+				if targetId == "" {
+					targetId = co.ControlId + "_smt"
+				}
+				// The parent control of the statement
+				props := []typecommon.Prop{{Name: "control-id", Value: co.ControlId}}
+				if co.StatementId != "" {
+					props = append(props, typecommon.Prop{Name: "statement-id", Value: co.StatementId})
+				}
+				finding := typear.Finding{
+					UUID:        stamper.UUID("finding", componentObject.ComponentTitle, co.ControlId, co.StatementId),
+					Title:       fmt.Sprintf("Finding of %s", co.GetControlId()),
+					Description: fmt.Sprintf("Finding of %s implemented by %s", co.GetControlId(), componentObject.ComponentTitle),
+					Props:       props,
+					Target: typear.FindingTarget{
+						Type:     "statement-id",
+						TargetId: targetId,
+						Status: typear.ObjectiveStatus{
+							State:  state,
+							Reason: reason,
+						},
+					},
+					ImplementationStatementUUID: co.ImplementationUUID,
+					RelatedObservations:         relatedObservations,
+				}
+				findings = append(findings, finding)
+			}
+		}
+	}
+	return findings
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oscal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	typear "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/assessmentresults"
+	typecommon "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/common"
+)
+
+func TestAggregate(t *testing.T) {
+	results := []string{"pass", "error", "skip"}
+	state, reason := AggregationRuleAllPass.Aggregate(results)
+	assert.Equal(t, FindingStateNotSatisfied, state)
+	assert.Equal(t, "fail", reason)
+	state, _ = AggregationRuleAnyPass.Aggregate(results)
+	assert.Equal(t, FindingStateSatisfied, state)
+	state, _ = AggregationRuleNoFail.Aggregate(results)
+	assert.Equal(t, FindingStateSatisfied, state)
+	state, _ = AggregationRuleNoFail.Aggregate([]string{"pass", "fail"})
+	assert.Equal(t, FindingStateNotSatisfied, state)
+
+	state, reason = AggregationRuleAllPass.Aggregate([]string{"skip"})
+	assert.Equal(t, FindingStateNotSatisfied, state)
+	assert.Equal(t, "other", reason)
+
+	_, err := ParseAggregationRule("majority")
+	assert.Error(t, err)
+}
+
+func TestGenerateFindings(t *testing.T) {
+	componentObjects := []ComponentObject{{
+		ComponentTitle: "Kubernetes",
+		ComponentType:  "Service",
+		ControlImpleObjects: []ControlImpleObject{{
+			ControlObjects: []ControlObject{{
+				ControlId:          "cm-6",
+				RuleIds:            []string{"rule-1", "rule-2"},
+				ImplementationUUID: "8ec6a80a-1cf6-4eb4-a0fa-1e5d5a4f8f1a",
+			}, {
+				ControlId:          "ac-1",
+				StatementId:        "ac-1_smt.a",
+				RuleIds:            []string{"rule-3"},
+				ImplementationUUID: "0d1c7f38-7ac3-4b2b-bd94-6b4a9c6a1e3f",
+			}},
+		}},
+	}, {
+		ComponentTitle: "Validator",
+		ComponentType:  "validation",
+	}}
+	prop := func(name, value string) typecommon.Prop {
+		return typecommon.Prop{Name: name, Value: value}
+	}
+	observations := []typear.Observation{{
+		UUID:  "f1b4b0a4-1c2d-4e5f-8a9b-0c1d2e3f4a5b",
+		Props: []typecommon.Prop{prop("assessment-rule-id", "rule-1")},
+		Subjects: []typear.Subject{
+			{Props: []typecommon.Prop{prop("result", "pass")}},
+			{Props: []typecommon.Prop{prop("result", "pass")}},
+		},
+	}, {
+		UUID:  "a2c4e6f8-0b1d-4f3a-9c5e-7d9f1b3d5f7a",
+		Props: []typecommon.Prop{prop("assessment-rule-id", "rule-2"), prop("result", "fail")},
+	}, {
+		UUID:  "b3d5f7a9-1c2e-4a4b-8d6f-8e0a2c4e6a8c",
+		Props: []typecommon.Prop{prop("assessment-rule-id", "rule-3"), prop("control-id", "ac-2")},
+	}}
+
+	findings := GenerateFindings(componentObjects, observations, AggregationRuleAllPass, NewStamper(false))
+	assert.Len(t, findings, 2)
+
+	assert.Equal(t, "cm-6_smt", findings[0].Target.TargetId)
+	assert.Equal(t, FindingStateNotSatisfied, findings[0].Target.Status.State)
+	assert.Equal(t, "8ec6a80a-1cf6-4eb4-a0fa-1e5d5a4f8f1a", findings[0].ImplementationStatementUUID)
+	assert.Equal(t, []typear.RelatedObservation{
+		{ObservationUUID: "f1b4b0a4-1c2d-4e5f-8a9b-0c1d2e3f4a5b"},
+		{ObservationUUID: "a2c4e6f8-0b1d-4f3a-9c5e-7d9f1b3d5f7a"},
+	}, findings[0].RelatedObservations)
+
+	// The observation of rule-3 is of another control
+	assert.Equal(t, "ac-1_smt.a", findings[1].Target.TargetId)
+	assert.Empty(t, findings[1].RelatedObservations)
+	assert.Equal(t, "other", findings[1].Target.Status.Reason)
+
+	findings = GenerateFindings(componentObjects, observations, AggregationRuleAnyPass, NewStamper(false))
+	assert.Equal(t, FindingStateSatisfied, findings[0].Target.Status.State)
+
+	// The finding of the statement refers to the parent control
+	assert.Equal(t, []typecommon.Prop{prop("control-id", "ac-1"), prop("statement-id", "ac-1_smt.a")}, findings[1].Props)
+	observations = []typear.Observation{{
+		UUID:  "c4e6a8b0-2d3f-4b5c-9e7a-9f1b3d5f7b9d",
+		Props: []typecommon.Prop{prop("assessment-rule-id", "rule-3"), prop("control-id", "ac-1"), prop("statement-id", "ac-1_smt.b")},
+	}, {
+		UUID:  "d5f7b9c1-3e4a-4c6d-8f8b-0a2c4e6a8c0e",
+		Props: []typecommon.Prop{prop("assessment-rule-id", "rule-3"), prop("control-id", "ac-1"), prop("statement-id", "ac-1_smt.a")},
+	}}
+	findings = GenerateFindings(componentObjects, observations, AggregationRuleAllPass, NewStamper(false))
+	assert.Equal(t, []typear.RelatedObservation{{ObservationUUID: "d5f7b9c1-3e4a-4c6d-8f8b-0a2c4e6a8c0e"}}, findings[1].RelatedObservations)
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package format
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	neturl "net/url"
+	"path/filepath"
+	"strings"
+
+	sigyaml "sigs.k8s.io/yaml"
+)
+
+// Format is a serialization of OSCAL documents.
+type Format string
+
+const (
+	JSON Format = "json"
+	YAML Format = "yaml"
+	XML  Format = "xml"
+)
+
+var Formats = []Format{JSON, YAML, XML}
+
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(s) {
+	case "json":
+		return JSON, nil
+	case "yaml", "yml":
+		return YAML, nil
+	case "xml":
+		return XML, nil
+	}
+	return "", fmt.Errorf("unsupported format %s: must be one of %v", s, Formats)
+}
+
+// FromPath returns the format given by the file extension of the path or url.
+func FromPath(path string) (Format, bool) {
+	if u, err := neturl.Parse(path); err == nil && u.Path != "" {
+		path = u.Path
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return JSON, true
+	case ".yaml", ".yml":
+		return YAML, true
+	case ".xml":
+		return XML, true
+	}
+	return "", false
+}
+
+// Sniff guesses the format from the content.
+func Sniff(data []byte) Format {
+	trimmed := bytes.TrimLeft(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")), " \t\r\n")
+	if len(trimmed) > 0 {
+		switch trimmed[0] {
+		case '{', '[':
+			return JSON
+		case '<':
+			return XML
+		}
+	}
+	return YAML
+}
+
+// Detect returns the format by the file extension of the path, or by the content if the extension is unknown.
+func Detect(path string, data []byte) Format {
+	if format, ok := FromPath(path); ok {
+		return format
+	}
+	return Sniff(data)
+}
+
+// Unmarshal decodes an OSCAL document into the types having json tags (e.g. typesoscal.CatalogRoot).
+func Unmarshal(data []byte, format Format, out interface{}) error {
+	switch format {
+	case JSON:
+		return json.Unmarshal(data, out)
+	case YAML:
+		return sigyaml.Unmarshal(data, out)
+	case XML:
+		jsonData, err := xmlToJson(data)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(jsonData, out)
+	}
+	return fmt.Errorf("unsupported format %s", format)
+}
+
+// ToJson converts the OSCAL document in the format to JSON.
+func ToJson(data []byte, format Format) ([]byte, error) {
+	switch format {
+	case JSON:
+		return data, nil
+	case YAML:
+		return sigyaml.YAMLToJSON(data)
+	case XML:
+		return xmlToJson(data)
+	}
+	return nil, fmt.Errorf("unsupported format %s", format)
+}
+
+// Marshal encodes an OSCAL document root (e.g. typear.AssessmentResultsRoot) in the format.
+func Marshal(in interface{}, format Format) ([]byte, error) {
+	switch format {
+	case JSON:
+		return json.MarshalIndent(in, "", "\t")
+	case YAML:
+		return sigyaml.Marshal(in)
+	case XML:
+		jsonData, err := json.Marshal(in)
+		if err != nil {
+			return nil, err
+		}
+		return jsonToXml(jsonData)
+	}
+	return nil, fmt.Errorf("unsupported format %s", format)
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package format
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal"
+	typear "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/assessmentresults"
+)
+
+func TestDetect(t *testing.T) {
+	assert.Equal(t, JSON, Detect("catalog.json", []byte("<catalog/>")))
+	assert.Equal(t, YAML, Detect("https://example.com/profile.yml?ref=main", nil))
+	assert.Equal(t, XML, Detect("https://example.com/catalog", []byte("\n  <?xml version=\"1.0\"?><catalog/>")))
+	assert.Equal(t, JSON, Detect("catalog", []byte("\xef\xbb\xbf{\"catalog\": {}}")))
+	assert.Equal(t, YAML, Detect("catalog", []byte("catalog:\n  uuid: xxx")))
+
+	_, err := ParseFormat("toml")
+	assert.Error(t, err)
+}
+
+func TestUnmarshalXml(t *testing.T) {
+	data, err := os.ReadFile("./testdata/catalog.xml")
+	assert.NoError(t, err, "Should not happen")
+
+	var catalogRoot oscal.CatalogRoot
+	err = Unmarshal(data, XML, &catalogRoot)
+	assert.NoError(t, err, "Should not happen")
+
+	catalog := catalogRoot.Catalog
+	assert.Equal(t, "9a1b3c5d-2e4f-4a6b-8c0d-1e2f3a4b5c6d", catalog.UUID)
+	assert.Equal(t, "Test Catalog in *XML*", catalog.Metadata.Title)
+	assert.Equal(t, "Test Organization", catalog.Metadata.Parties[0].Name)
+	assert.Equal(t, []string{"test@example.com"}, catalog.Metadata.Parties[0].EmailAddresses)
+	assert.Equal(t, []string{"3c2a1d5f-6b7e-4c8d-9e0f-1a2b3c4d5e6f"}, catalog.Metadata.ResponsibleParties[0].PartyUuids)
+	assert.Len(t, catalog.Groups, 2)
+
+	ac1 := catalog.Groups[0].Controls[0]
+	assert.Equal(t, "ac-1", ac1.ID)
+	assert.Equal(t, "Policy and Procedures", ac1.Title)
+	assert.Equal(t, "one-or-more", ac1.Params[1].Select.HowMany)
+	assert.Equal(t, []string{"organization-level", "mission/business process-level"}, ac1.Params[1].Select.Choice)
+	assert.Equal(t, "reference", ac1.Links[0].Rel)
+
+	item := ac1.Parts[0].Parts[0]
+	assert.Equal(t, "ac-1_smt.a", item.ID)
+	assert.Equal(t, "a.", item.Props[0].Value)
+	assert.Equal(t, "Develop, document, and disseminate to {{ insert: param, ac-1_prm_1 }}:", item.Prose)
+	assert.Equal(t, "{{ insert: param, ac-1_prm_2 }} access control policy that:", item.Parts[0].Prose)
+	assert.Equal(t, "Access control policy and procedures address the controls in the AC family.\n\n"+
+		"Security and privacy program policies and procedures at the organization level may make the need for system-specific policies and procedures **unnecessary**.\n\n"+
+		"- first item\n- second item", ac1.Parts[1].Prose)
+
+	ac2 := catalog.Groups[0].Controls[1]
+	assert.Equal(t, []string{"30 days"}, ac2.Params[0].Values)
+	assert.Equal(t, "the time period is defined;", ac2.Params[0].Guidelines[0].Prose)
+	assert.Equal(t, "ac-2.1", ac2.Controls[0].ID)
+
+	assert.Equal(t, "OMB A-130", catalog.BackMatter.Resources[0].Citation.Text)
+	assert.Equal(t, "https://example.com/a-130.pdf", catalog.BackMatter.Resources[0].Rlinks[0].Href)
+}
+
+func TestUnmarshalYaml(t *testing.T) {
+	data, err := os.ReadFile("./testdata/profile.yaml")
+	assert.NoError(t, err, "Should not happen")
+
+	var profileRoot oscal.ProfileRoot
+	err = Unmarshal(data, Detect("./testdata/profile.yaml", data), &profileRoot)
+	assert.NoError(t, err, "Should not happen")
+	assert.Equal(t, "./catalog.xml", profileRoot.Profile.Imports[0].Href)
+	assert.Equal(t, []string{"ac-1", "ac-2.1", "cm-6"}, profileRoot.Profile.Imports[0].IncludeControls[0].WithIds)
+	assert.True(t, profileRoot.Profile.Merge.AsIs)
+}
+
+func TestMarshalXml(t *testing.T) {
+	data, err := os.ReadFile("../../testdata/ocm/assessment-results.json")
+	assert.NoError(t, err, "Should not happen")
+	var arRoot typear.AssessmentResultsRoot
+	err = json.Unmarshal(data, &arRoot)
+	assert.NoError(t, err, "Should not happen")
+
+	xmlData, err := Marshal(arRoot, XML)
+	assert.NoError(t, err, "Should not happen")
+	assert.Contains(t, string(xmlData), `<assessment-results xmlns="http://csrc.nist.gov/ns/oscal/1.0" uuid="`)
+
+	var decoded typear.AssessmentResultsRoot
+	err = Unmarshal(xmlData, XML, &decoded)
+	assert.NoError(t, err, "Should not happen")
+	assert.Equal(t, arRoot, decoded)
+
+	yamlData, err := Marshal(arRoot, YAML)
+	assert.NoError(t, err, "Should not happen")
+	decoded = typear.AssessmentResultsRoot{}
+	err = Unmarshal(yamlData, YAML, &decoded)
+	assert.NoError(t, err, "Should not happen")
+	assert.Equal(t, arRoot, decoded)
+}
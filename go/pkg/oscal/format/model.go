@@ -0,0 +1,227 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package format
+
+// The tables below follow the OSCAL metaschema for the mapping between XML and JSON serializations.
+
+const oscalNamespace = "http://csrc.nist.gov/ns/oscal/1.0"
+
+// XML element name -> JSON property name of the array grouping the elements
+var groupAs = map[string]string{
+	"activity":                    "activities",
+	"actor":                       "actors",
+	"add":                         "adds",
+	"addr-line":                   "addr-lines",
+	"address":                     "addresses",
+	"alter":                       "alters",
+	"assessment-subject":          "assessment-subjects",
+	"associated-activity":         "associated-activities",
+	"associated-risk":             "associated-risks",
+	"attestation":                 "attestations",
+	"by-component":                "by-components",
+	"capability":                  "capabilities",
+	"characterization":            "characterizations",
+	"choice":                      "choice",
+	"component":                   "components",
+	"control":                     "controls",
+	"control-implementation":      "control-implementations",
+	"control-objective-selection": "control-objective-selections",
+	"control-selection":           "control-selections",
+	"dependency":                  "dependencies",
+	"document-id":                 "document-ids",
+	"email-address":               "email-addresses",
+	"entry":                       "entries",
+	"exclude-controls":            "exclude-controls",
+	"exclude-objective":           "exclude-objectives",
+	"exclude-subject":             "exclude-subjects",
+	"external-id":                 "external-ids",
+	"facet":                       "facets",
+	"finding":                     "findings",
+	"group":                       "groups",
+	"guideline":                   "guidelines",
+	"hash":                        "hashes",
+	"implemented-requirement":     "implemented-requirements",
+	"import":                      "imports",
+	"import-component-definition": "import-component-definitions",
+	"include-controls":            "include-controls",
+	"include-objective":           "include-objectives",
+	"include-subject":             "include-subjects",
+	"incorporates-component":      "incorporates-components",
+	"inventory-item":              "inventory-items",
+	"link":                        "links",
+	"location":                    "locations",
+	"location-uuid":               "location-uuids",
+	"matching":                    "matching",
+	"member-of-organization":      "member-of-organizations",
+	"method":                      "methods",
+	"mitigating-factor":           "mitigating-factors",
+	"observation":                 "observations",
+	"origin":                      "origins",
+	"param":                       "params",
+	"part":                        "parts",
+	"party":                       "parties",
+	"party-uuid":                  "party-uuids",
+	"poam-item":                   "poam-items",
+	"port-range":                  "port-ranges",
+	"prop":                        "props",
+	"protocol":                    "protocols",
+	"related-finding":             "related-findings",
+	"related-observation":         "related-observations",
+	"related-risk":                "related-risks",
+	"related-task":                "related-tasks",
+	"relevant-evidence":           "relevant-evidence",
+	"remediation":                 "remediations",
+	"remove":                      "removes",
+	"required-asset":              "required-assets",
+	"resource":                    "resources",
+	"responsible-party":           "responsible-parties",
+	"responsible-role":            "responsible-roles",
+	"result":                      "results",
+	"revision":                    "revisions",
+	"risk":                        "risks",
+	"rlink":                       "rlinks",
+	"role":                        "roles",
+	"role-id":                     "role-ids",
+	"set-parameter":               "set-parameters",
+	"statement":                   "statements",
+	"statement-id":                "statement-ids",
+	"step":                        "steps",
+	"subject":                     "subjects",
+	"task":                        "tasks",
+	"telephone-number":            "telephone-numbers",
+	"threat-id":                   "threat-ids",
+	"type":                        "types",
+	"url":                         "urls",
+	"user":                        "users",
+	"value":                       "values",
+	"with-id":                     "with-ids",
+}
+
+// JSON property name of an array -> XML element name of the items
+var groupedBy = func() map[string]string {
+	m := map[string]string{}
+	for elem, key := range groupAs {
+		m[key] = elem
+	}
+	return m
+}()
+
+// JSON properties serialized as XML attributes (flags)
+var flags = map[string]bool{
+	"actor-uuid":          true,
+	"algorithm":           true,
+	"by-id":               true,
+	"class":               true,
+	"component-uuid":      true,
+	"control-id":          true,
+	"depends-on":          true,
+	"finding-uuid":        true,
+	"how-many":            true,
+	"href":                true,
+	"id":                  true,
+	"media-type":          true,
+	"method":              true,
+	"name":                true,
+	"ns":                  true,
+	"observation-uuid":    true,
+	"param-id":            true,
+	"pattern":             true,
+	"position":            true,
+	"rel":                 true,
+	"response-uuid":       true,
+	"risk-uuid":           true,
+	"role-id":             true,
+	"scheme":              true,
+	"statement-id":        true,
+	"subject-uuid":        true,
+	"system":              true,
+	"task-uuid":           true,
+	"type":                true,
+	"uuid":                true,
+	"value":               true,
+	"with-child-controls": true,
+}
+
+// Flags that are serialized as XML elements in the specific XML elements
+var fieldsOverridingFlags = map[string]map[string]bool{
+	"party": {"name": true},
+}
+
+// JSON properties holding the text content of XML elements having flags
+var valueKeys = map[string]string{
+	"hash":        "value",
+	"document-id": "identifier",
+}
+
+// JSON properties of markup-multiline type
+var markupMultiline = map[string]bool{
+	"description": true,
+	"guidance":    true,
+	"prose":       true,
+	"purpose":     true,
+	"remarks":     true,
+	"usage":       true,
+}
+
+// XML elements whose prose is written inline (without a wrapper element)
+var proseContainers = map[string]bool{
+	"part":            true,
+	"guideline":       true,
+	"assessment-part": true,
+}
+
+// XML elements that are objects even if empty
+var emptyObjects = map[string]bool{
+	"include-all": true,
+	"flat":        true,
+}
+
+// Boolean-typed fields
+var booleans = map[string]bool{
+	"as-is": true,
+}
+
+var blockMarkup = map[string]bool{
+	"p":          true,
+	"h1":         true,
+	"h2":         true,
+	"h3":         true,
+	"h4":         true,
+	"h5":         true,
+	"h6":         true,
+	"ul":         true,
+	"ol":         true,
+	"pre":        true,
+	"blockquote": true,
+	"table":      true,
+	"hr":         true,
+}
+
+var inlineMarkup = map[string]bool{
+	"a":      true,
+	"b":      true,
+	"br":     true,
+	"code":   true,
+	"em":     true,
+	"i":      true,
+	"img":    true,
+	"insert": true,
+	"q":      true,
+	"strong": true,
+	"sub":    true,
+	"sup":    true,
+}
@@ -0,0 +1,557 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package format
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type xmlElement struct {
+	name     string
+	attrs    []xml.Attr
+	children []xmlNode
+}
+
+// xmlNode is either a text or an element
+type xmlNode struct {
+	text    string
+	element *xmlElement
+}
+
+var whitespaces = regexp.MustCompile(`\s+`)
+
+// xmlToJson converts an OSCAL XML document to the JSON serialization.
+// Markup contents (prose, description, remarks, etc.) are converted to markdown.
+func xmlToJson(data []byte) ([]byte, error) {
+	root, err := parseXml(data)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(map[string]interface{}{root.name: toJsonValue(root)})
+}
+
+func parseXml(data []byte) (*xmlElement, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	var root *xmlElement
+	stack := []*xmlElement{}
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			element := &xmlElement{name: t.Name.Local}
+			for _, attr := range t.Attr {
+				// skip namespace declarations and qualified attributes like xsi:schemaLocation
+				if attr.Name.Space != "" || attr.Name.Local == "xmlns" {
+					continue
+				}
+				element.attrs = append(element.attrs, attr)
+			}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, xmlNode{element: element})
+			} else if root == nil {
+				root = element
+			}
+			stack = append(stack, element)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, xmlNode{text: string(t)})
+			}
+		}
+	}
+	if root == nil {
+		return nil, errors.New("no root element is found")
+	}
+	return root, nil
+}
+
+func toJsonValue(e *xmlElement) interface{} {
+	if len(e.attrs) > 0 || proseContainers[e.name] || emptyObjects[e.name] || hasStructuralChildren(e) {
+		return toJsonObject(e)
+	}
+	for _, child := range e.children {
+		if child.element != nil && blockMarkup[child.element.name] {
+			return blocksToMarkdown(e.children)
+		}
+	}
+	return typed(e.name, inlineToMarkdown(e.children))
+}
+
+func toJsonObject(e *xmlElement) map[string]interface{} {
+	obj := map[string]interface{}{}
+	for _, attr := range e.attrs {
+		obj[attr.Name.Local] = typed(attr.Name.Local, attr.Value)
+	}
+	markup := []xmlNode{}
+	hasMarkupElement := false
+	for _, child := range e.children {
+		if child.element == nil {
+			markup = append(markup, child)
+			continue
+		}
+		c := child.element
+		if blockMarkup[c.name] || inlineMarkup[c.name] {
+			markup = append(markup, child)
+			hasMarkupElement = true
+			continue
+		}
+		value := toJsonValue(c)
+		if key, ok := groupAs[c.name]; ok {
+			values, _ := obj[key].([]interface{})
+			obj[key] = append(values, value)
+		} else {
+			obj[c.name] = value
+		}
+	}
+	if hasMarkupElement {
+		obj["prose"] = blocksToMarkdown(markup)
+	} else if text := strings.TrimSpace(inlineToMarkdown(markup)); text != "" {
+		key, ok := valueKeys[e.name]
+		if !ok {
+			key = "value"
+		}
+		obj[key] = text
+	}
+	return obj
+}
+
+func hasStructuralChildren(e *xmlElement) bool {
+	for _, child := range e.children {
+		if child.element != nil && !blockMarkup[child.element.name] && !inlineMarkup[child.element.name] {
+			return true
+		}
+	}
+	return false
+}
+
+func typed(name string, value string) interface{} {
+	if booleans[name] {
+		if boolean, err := strconv.ParseBool(value); err == nil {
+			return boolean
+		}
+	}
+	return value
+}
+
+func attr(e *xmlElement, name string) string {
+	for _, attr := range e.attrs {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+func blocksToMarkdown(nodes []xmlNode) string {
+	blocks := []string{}
+	inline := []xmlNode{}
+	flush := func() {
+		if text := inlineToMarkdown(inline); text != "" {
+			blocks = append(blocks, text)
+		}
+		inline = []xmlNode{}
+	}
+	for _, node := range nodes {
+		if node.element != nil && blockMarkup[node.element.name] {
+			flush()
+			blocks = append(blocks, blockToMarkdown(node.element))
+			continue
+		}
+		inline = append(inline, node)
+	}
+	flush()
+	return strings.Join(blocks, "\n\n")
+}
+
+func blockToMarkdown(e *xmlElement) string {
+	switch e.name {
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		level, _ := strconv.Atoi(e.name[1:])
+		return strings.Repeat("#", level) + " " + inlineToMarkdown(e.children)
+	case "ul", "ol":
+		return listToMarkdown(e, "")
+	case "pre":
+		return "```\n" + rawText(e.children) + "\n```"
+	case "blockquote":
+		lines := strings.Split(blocksToMarkdown(e.children), "\n")
+		for idx, line := range lines {
+			lines[idx] = "> " + line
+		}
+		return strings.Join(lines, "\n")
+	case "table":
+		return tableToMarkdown(e)
+	case "hr":
+		return "---"
+	}
+	return inlineToMarkdown(e.children)
+}
+
+func listToMarkdown(e *xmlElement, indent string) string {
+	lines := []string{}
+	for _, child := range e.children {
+		if child.element == nil || child.element.name != "li" {
+			continue
+		}
+		marker := "- "
+		if e.name == "ol" {
+			marker = "1. "
+		}
+		inline := []xmlNode{}
+		nested := []string{}
+		for _, node := range child.element.children {
+			if node.element != nil && (node.element.name == "ul" || node.element.name == "ol") {
+				nested = append(nested, listToMarkdown(node.element, indent+"  "))
+				continue
+			}
+			inline = append(inline, node)
+		}
+		lines = append(lines, indent+marker+inlineToMarkdown(inline))
+		lines = append(lines, nested...)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func tableToMarkdown(e *xmlElement) string {
+	rows := []*xmlElement{}
+	var collect func(e *xmlElement)
+	collect = func(e *xmlElement) {
+		for _, child := range e.children {
+			if child.element == nil {
+				continue
+			}
+			if child.element.name == "tr" {
+				rows = append(rows, child.element)
+			} else {
+				collect(child.element)
+			}
+		}
+	}
+	collect(e)
+	lines := []string{}
+	for idx, row := range rows {
+		cells := []string{}
+		header := false
+		for _, child := range row.children {
+			if child.element != nil && (child.element.name == "th" || child.element.name == "td") {
+				header = header || child.element.name == "th"
+				cells = append(cells, inlineToMarkdown(child.element.children))
+			}
+		}
+		lines = append(lines, "| "+strings.Join(cells, " | ")+" |")
+		if idx == 0 && header {
+			separators := make([]string, len(cells))
+			for i := range separators {
+				separators[i] = "---"
+			}
+			lines = append(lines, "| "+strings.Join(separators, " | ")+" |")
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func inlineToMarkdown(nodes []xmlNode) string {
+	var builder strings.Builder
+	for _, node := range nodes {
+		if node.element == nil {
+			builder.WriteString(whitespaces.ReplaceAllString(node.text, " "))
+			continue
+		}
+		e := node.element
+		switch e.name {
+		case "em", "i":
+			builder.WriteString("*" + inlineToMarkdown(e.children) + "*")
+		case "strong", "b":
+			builder.WriteString("**" + inlineToMarkdown(e.children) + "**")
+		case "code":
+			builder.WriteString("`" + rawText(e.children) + "`")
+		case "q":
+			builder.WriteString("\"" + inlineToMarkdown(e.children) + "\"")
+		case "sub":
+			builder.WriteString("~" + inlineToMarkdown(e.children) + "~")
+		case "sup":
+			builder.WriteString("^" + inlineToMarkdown(e.children) + "^")
+		case "a":
+			builder.WriteString("[" + inlineToMarkdown(e.children) + "](" + attr(e, "href") + ")")
+		case "img":
+			builder.WriteString("![" + attr(e, "alt") + "](" + attr(e, "src") + ")")
+		case "insert":
+			builder.WriteString(fmt.Sprintf("{{ insert: %s, %s }}", attr(e, "type"), attr(e, "id-ref")))
+		case "br":
+			builder.WriteString(" ")
+		default:
+			builder.WriteString(inlineToMarkdown(e.children))
+		}
+	}
+	return strings.TrimSpace(whitespaces.ReplaceAllString(builder.String(), " "))
+}
+
+func rawText(nodes []xmlNode) string {
+	var builder strings.Builder
+	for _, node := range nodes {
+		if node.element == nil {
+			builder.WriteString(node.text)
+		} else {
+			builder.WriteString(rawText(node.element.children))
+		}
+	}
+	return builder.String()
+}
+
+// orderedObject keeps the order of JSON properties so that XML elements are written in the order of the model.
+type orderedObject struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+// jsonToXml converts a JSON serialization of an OSCAL document to XML.
+// Markdown in markup contents is converted to paragraphs and lists.
+func jsonToXml(data []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	value, err := decodeOrdered(decoder)
+	if err != nil {
+		return nil, err
+	}
+	root, ok := value.(*orderedObject)
+	if !ok || len(root.keys) != 1 {
+		return nil, errors.New("OSCAL document must be an object having a single root property")
+	}
+	buffer := bytes.NewBufferString(xml.Header)
+	writer := &xmlWriter{buffer: buffer}
+	writer.writeElement(0, root.keys[0], root.values[root.keys[0]], true)
+	return buffer.Bytes(), nil
+}
+
+func decodeOrdered(decoder *json.Decoder) (interface{}, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := token.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			obj := &orderedObject{values: map[string]interface{}{}}
+			for decoder.More() {
+				keyToken, err := decoder.Token()
+				if err != nil {
+					return nil, err
+				}
+				key := keyToken.(string)
+				value, err := decodeOrdered(decoder)
+				if err != nil {
+					return nil, err
+				}
+				if _, found := obj.values[key]; !found {
+					obj.keys = append(obj.keys, key)
+				}
+				obj.values[key] = value
+			}
+			_, err := decoder.Token()
+			return obj, err
+		case '[':
+			values := []interface{}{}
+			for decoder.More() {
+				value, err := decodeOrdered(decoder)
+				if err != nil {
+					return nil, err
+				}
+				values = append(values, value)
+			}
+			_, err := decoder.Token()
+			return values, err
+		}
+	}
+	return token, nil
+}
+
+type xmlWriter struct {
+	buffer *bytes.Buffer
+}
+
+func (w *xmlWriter) writeElement(depth int, name string, value interface{}, root bool) {
+	indent := strings.Repeat("  ", depth)
+	obj, ok := value.(*orderedObject)
+	if !ok {
+		if text, ok := scalarText(value); ok {
+			w.buffer.WriteString(fmt.Sprintf("%s<%s>%s</%s>\n", indent, name, escape(text), name))
+		}
+		return
+	}
+	var start strings.Builder
+	start.WriteString("<" + name)
+	if root {
+		start.WriteString(fmt.Sprintf(` xmlns="%s"`, oscalNamespace))
+	}
+	children := []string{}
+	text := ""
+	for _, key := range obj.keys {
+		value := obj.values[key]
+		if valueKeys[name] == key {
+			text, _ = scalarText(value)
+			continue
+		}
+		if attrText, ok := scalarText(value); ok && flags[key] && !fieldsOverridingFlags[name][key] {
+			start.WriteString(fmt.Sprintf(` %s="%s"`, key, escape(attrText)))
+			continue
+		}
+		if value != nil {
+			children = append(children, key)
+		}
+	}
+	if len(children) == 0 && text == "" {
+		w.buffer.WriteString(indent + start.String() + "/>\n")
+		return
+	}
+	if len(children) == 0 {
+		w.buffer.WriteString(fmt.Sprintf("%s%s>%s</%s>\n", indent, start.String(), escape(text), name))
+		return
+	}
+	w.buffer.WriteString(indent + start.String() + ">\n")
+	for _, key := range children {
+		w.writeProperty(depth+1, key, obj.values[key])
+	}
+	w.buffer.WriteString(fmt.Sprintf("%s</%s>\n", indent, name))
+}
+
+func (w *xmlWriter) writeProperty(depth int, key string, value interface{}) {
+	indent := strings.Repeat("  ", depth)
+	switch v := value.(type) {
+	case []interface{}:
+		name, ok := groupedBy[key]
+		if !ok {
+			name = key
+		}
+		for _, item := range v {
+			w.writeElement(depth, name, item, false)
+		}
+	case string:
+		if !markupMultiline[key] {
+			w.writeElement(depth, key, v, false)
+			return
+		}
+		if key == "prose" {
+			w.writeMarkdown(depth, v)
+			return
+		}
+		if strings.TrimSpace(v) == "" {
+			w.buffer.WriteString(fmt.Sprintf("%s<%s/>\n", indent, key))
+			return
+		}
+		w.buffer.WriteString(fmt.Sprintf("%s<%s>\n", indent, key))
+		w.writeMarkdown(depth+1, v)
+		w.buffer.WriteString(fmt.Sprintf("%s</%s>\n", indent, key))
+	default:
+		w.writeElement(depth, key, v, false)
+	}
+}
+
+var listItem = regexp.MustCompile(`^\s*(?:[-*]|\d+\.)\s+`)
+
+var blankLines = regexp.MustCompile(`\n\s*\n`)
+
+func (w *xmlWriter) writeMarkdown(depth int, markdown string) {
+	indent := strings.Repeat("  ", depth)
+	markdown = strings.ReplaceAll(markdown, "\r\n", "\n")
+	for _, block := range blankLines.Split(strings.TrimSpace(markdown), -1) {
+		lines := strings.Split(block, "\n")
+		switch {
+		case strings.HasPrefix(block, "```"):
+			code := strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(block, "```"), "\n"), "```")
+			w.buffer.WriteString(fmt.Sprintf("%s<pre>%s</pre>\n", indent, escape(strings.TrimSuffix(code, "\n"))))
+		case isList(lines):
+			tag := "ul"
+			if !strings.HasPrefix(strings.TrimSpace(lines[0]), "-") && !strings.HasPrefix(strings.TrimSpace(lines[0]), "*") {
+				tag = "ol"
+			}
+			w.buffer.WriteString(fmt.Sprintf("%s<%s>\n", indent, tag))
+			for _, line := range lines {
+				w.buffer.WriteString(fmt.Sprintf("%s  <li>%s</li>\n", indent, inlineToXml(listItem.ReplaceAllString(line, ""))))
+			}
+			w.buffer.WriteString(fmt.Sprintf("%s</%s>\n", indent, tag))
+		case strings.HasPrefix(block, "#"):
+			level := len(block) - len(strings.TrimLeft(block, "#"))
+			if level > 6 {
+				level = 6
+			}
+			w.buffer.WriteString(fmt.Sprintf("%s<h%d>%s</h%d>\n", indent, level, inlineToXml(strings.TrimSpace(strings.TrimLeft(block, "#"))), level))
+		default:
+			w.buffer.WriteString(fmt.Sprintf("%s<p>%s</p>\n", indent, inlineToXml(strings.Join(lines, " "))))
+		}
+	}
+}
+
+func isList(lines []string) bool {
+	for _, line := range lines {
+		if !listItem.MatchString(line) {
+			return false
+		}
+	}
+	return true
+}
+
+var inlineMarkdown = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`\{\{\s*insert:\s*([\w-]+),\s*([^\s}]+)\s*\}\}`), `<insert type="$1" id-ref="$2"/>`},
+	{regexp.MustCompile("`([^`]+)`"), `<code>$1</code>`},
+	{regexp.MustCompile(`\*\*([^*]+)\*\*`), `<strong>$1</strong>`},
+	{regexp.MustCompile(`\*([^*\s][^*]*)\*`), `<em>$1</em>`},
+}
+
+func inlineToXml(markdown string) string {
+	text := escape(markdown)
+	for _, m := range inlineMarkdown {
+		text = m.pattern.ReplaceAllString(text, m.replacement)
+	}
+	return text
+}
+
+func scalarText(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	case bool:
+		return strconv.FormatBool(v), true
+	}
+	return "", false
+}
+
+func escape(text string) string {
+	var buffer bytes.Buffer
+	_ = xml.EscapeText(&buffer, []byte(text))
+	return buffer.String()
+}
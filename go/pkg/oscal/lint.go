@@ -0,0 +1,332 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oscal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/oscal-compass/compliance-to-policy/go/pkg"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal"
+	cd "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/componentdefinition"
+)
+
+type LintSeverity string
+
+const (
+	LintSeverityError   LintSeverity = "error"
+	LintSeverityWarning LintSeverity = "warning"
+)
+
+// Checks of the component-definition linter
+const (
+	LintCheckUnknownRule         = "unknown-rule"
+	LintCheckDuplicateRule       = "duplicate-rule"
+	LintCheckInconsistentRemarks = "inconsistent-remarks"
+	LintCheckMissingPolicyId     = "missing-policy-id"
+	LintCheckMissingPolicy       = "missing-policy"
+	LintCheckUnknownParameter    = "unknown-parameter"
+	LintCheckUnknownControl      = "unknown-control"
+	LintCheckCatalogUnavailable  = "catalog-unavailable"
+)
+
+// PVPs whose conventions the linter checks the component-definition against
+const (
+	LintPVPOcm     = "ocm"
+	LintPVPKyverno = "kyverno"
+)
+
+// Props of a component grouped into a rule by their remarks
+var ruleProps = []string{
+	"Rule_Id", "Rule_Description", "Policy_Id", "Parameter_Id", "Parameter_Description", "Parameter_Value_Alternatives", "Check_Id", "Check_Description",
+	"Enforcement_Mode", "Target_Namespaces", "Excluded_Namespaces",
+}
+
+type LintIssue struct {
+	Severity  LintSeverity `json:"severity"`
+	Check     string       `json:"check"`
+	Component string       `json:"component,omitempty"`
+	// Location of the issue in the component (e.g. "props[rule_set_00]", "ac-1", "ac-1_smt.a")
+	Location string `json:"location,omitempty"`
+	Message  string `json:"message"`
+}
+
+func (i LintIssue) String() string {
+	location := i.Component
+	if i.Location != "" {
+		location = location + ": " + i.Location
+	}
+	return fmt.Sprintf("%s: [%s] %s: %s", i.Severity, i.Check, location, i.Message)
+}
+
+// CatalogResolver returns the catalog of the controls implemented by a control implementation from its source.
+type CatalogResolver func(source string) (*oscal.Catalog, error)
+
+type LintOptions struct {
+	// PVP (ocm or kyverno) whose conventions are checked. Empty if not checked.
+	PVP string
+	// Directory containing a directory of the policy resources for each policy (ocm) or rule (kyverno). Empty if not checked.
+	PolicyDir string
+	// Control IDs are not checked if nil.
+	ResolveCatalog CatalogResolver
+}
+
+// LintComponentDefinition checks the consistency of the rules, parameters and controls of the component-definition.
+func LintComponentDefinition(cdRoot cd.ComponentDefinitionRoot, options LintOptions) []LintIssue {
+	issues := []LintIssue{}
+	catalogs := map[string]*oscal.Catalog{}
+	// Rules linked to the checks of the validation components
+	componentObjects := ParseComponentDefinition(cdRoot)
+	for idx, component := range cdRoot.Components {
+		l := componentLinter{component: component, rules: componentObjects[idx].RuleObjects, options: options, catalogs: catalogs}
+		issues = append(issues, l.lint()...)
+	}
+	return issues
+}
+
+// HasLintErrors returns true if any of the issues is an error.
+func HasLintErrors(issues []LintIssue) bool {
+	for _, issue := range issues {
+		if issue.Severity == LintSeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+type componentLinter struct {
+	component cd.Component
+	rules     []RuleObject
+	options   LintOptions
+	// Catalogs resolved from the sources, shared by the components. Nil if the catalog cannot be resolved.
+	catalogs map[string]*oscal.Catalog
+	issues   []LintIssue
+}
+
+func (l *componentLinter) report(severity LintSeverity, check string, location string, format string, args ...interface{}) {
+	l.issues = append(l.issues, LintIssue{
+		Severity:  severity,
+		Check:     check,
+		Component: l.component.Title,
+		Location:  location,
+		Message:   fmt.Sprintf(format, args...),
+	})
+}
+
+func (l *componentLinter) lint() []LintIssue {
+	l.lintRemarks()
+	rules := l.rules
+	ruleIds := []string{}
+	for _, rule := range rules {
+		if rule.RuleId != "" {
+			ruleIds = append(ruleIds, rule.RuleId)
+		}
+	}
+	validation := l.component.Type == "validation"
+	if !validation {
+		l.lintPolicies(rules)
+	}
+	for _, controlImpl := range l.component.ControlImplementations {
+		catalog := l.resolveCatalog(controlImpl.Source)
+		referredRuleIds := []string{}
+		for _, implReq := range controlImpl.ImplementedRequirements {
+			if catalog != nil {
+				if _, ok := FindControl(*catalog, implReq.ControlID); !ok {
+					l.report(LintSeverityError, LintCheckUnknownControl, implReq.ControlID, "control %s is not in the catalog of %s", implReq.ControlID, controlImpl.Source)
+				}
+			}
+			referredRuleIds = append(referredRuleIds, l.lintRuleReferences(implReq.ControlID, implReq.Props, ruleIds)...)
+			for _, statement := range implReq.Statements {
+				referredRuleIds = append(referredRuleIds, l.lintRuleReferences(statement.StatementId, statement.Props, ruleIds)...)
+			}
+		}
+		l.lintSetParameters(controlImpl, rules, referredRuleIds)
+	}
+	return l.issues
+}
+
+// lintRemarks checks that the props of each rule are grouped by the same remarks.
+func (l *componentLinter) lintRemarks() {
+	remarksList := []string{}
+	groups := map[string][]cd.Prop{}
+	for _, prop := range l.component.Props {
+		if !slices.Contains(ruleProps, prop.Name) {
+			continue
+		}
+		if prop.Remarks == "" {
+			l.report(LintSeverityError, LintCheckInconsistentRemarks, "props", "%s %s has no remarks grouping it into a rule", prop.Name, prop.Value)
+			continue
+		}
+		if _, ok := groups[prop.Remarks]; !ok {
+			remarksList = append(remarksList, prop.Remarks)
+		}
+		groups[prop.Remarks] = append(groups[prop.Remarks], prop)
+	}
+	remarksOfRule := map[string]string{}
+	for _, remarks := range remarksList {
+		location := fmt.Sprintf("props[%s]", remarks)
+		names := map[string]int{}
+		ruleId := ""
+		for _, prop := range groups[remarks] {
+			names[prop.Name]++
+			if prop.Name == "Rule_Id" {
+				ruleId = prop.Value
+			}
+		}
+		for _, name := range ruleProps {
+			if names[name] > 1 {
+				l.report(LintSeverityError, LintCheckInconsistentRemarks, location, "%d %s props are grouped by the same remarks", names[name], name)
+			}
+		}
+		if names["Rule_Id"] == 0 {
+			l.report(LintSeverityError, LintCheckInconsistentRemarks, location, "props grouped by the remarks have no Rule_Id")
+			continue
+		}
+		// Validation components map a rule to each check by props of different remarks
+		if other, ok := remarksOfRule[ruleId]; ok && l.component.Type != "validation" {
+			l.report(LintSeverityError, LintCheckDuplicateRule, location, "rule %s is also defined by props[%s]", ruleId, other)
+			continue
+		}
+		remarksOfRule[ruleId] = remarks
+	}
+}
+
+// lintRuleReferences checks that the rules referred by the props exist and returns their IDs.
+func (l *componentLinter) lintRuleReferences(location string, props []cd.Prop, ruleIds []string) []string {
+	referred := []string{}
+	for _, prop := range listRules(props) {
+		if !slices.Contains(ruleIds, prop.Value) {
+			l.report(LintSeverityError, LintCheckUnknownRule, location, "rule %s is not defined in the props of the component", prop.Value)
+			continue
+		}
+		referred = append(referred, prop.Value)
+	}
+	return referred
+}
+
+// lintPolicies checks that the rules have the policy resources for the PVP and the parameters referred by them are defined.
+func (l *componentLinter) lintPolicies(rules []RuleObject) {
+	checked := []string{}
+	for _, rule := range rules {
+		// Duplicate rules are reported by lintRemarks
+		if rule.RuleId == "" || slices.Contains(checked, rule.RuleId) {
+			continue
+		}
+		checked = append(checked, rule.RuleId)
+		policyIds := policyIdsOfRule(l.options.PVP, rule)
+		if l.options.PVP == LintPVPOcm && len(policyIds) == 0 {
+			l.report(LintSeverityError, LintCheckMissingPolicyId, rule.RuleId, "rule %s has neither Policy_Id nor Check_Id", rule.RuleId)
+			continue
+		}
+		if l.options.PolicyDir == "" || l.options.PVP == "" {
+			continue
+		}
+		for _, policyId := range policyIds {
+			policyDir := filepath.Join(l.options.PolicyDir, policyId)
+			if info, err := os.Stat(policyDir); err != nil || !info.IsDir() {
+				l.report(LintSeverityError, LintCheckMissingPolicy, rule.RuleId, "policy directory %s of rule %s does not exist", policyDir, rule.RuleId)
+				continue
+			}
+			paramIds, err := findParameterIdsInDir(policyDir)
+			if err != nil {
+				l.report(LintSeverityError, LintCheckMissingPolicy, rule.RuleId, "failed to read policy directory %s: %v", policyDir, err)
+				continue
+			}
+			for _, paramId := range paramIds {
+				if !slices.Contains(parameterIdsOfPolicy(l.options.PVP, policyId, rules), paramId) {
+					l.report(LintSeverityError, LintCheckUnknownParameter, rule.RuleId, "parameter %s referred in %s is not the Parameter_Id of the rule", paramId, policyDir)
+				}
+			}
+		}
+	}
+}
+
+// lintSetParameters checks that the set-parameters are the parameters of the rules referred by the control implementation.
+func (l *componentLinter) lintSetParameters(controlImpl cd.ControlImplementation, rules []RuleObject, referredRuleIds []string) {
+	paramIds := []string{}
+	for _, rule := range rules {
+		if rule.ParameterId != "" && slices.Contains(referredRuleIds, rule.RuleId) {
+			paramIds = append(paramIds, rule.ParameterId)
+		}
+	}
+	for _, setParameter := range controlImpl.SetParameters {
+		if !slices.Contains(paramIds, setParameter.ParamID) {
+			l.report(LintSeverityError, LintCheckUnknownParameter, "set-parameters", "parameter %s is not the Parameter_Id of any rule of the control implementation of %s", setParameter.ParamID, controlImpl.Source)
+		}
+	}
+}
+
+func (l *componentLinter) resolveCatalog(source string) *oscal.Catalog {
+	if l.options.ResolveCatalog == nil {
+		return nil
+	}
+	catalog, ok := l.catalogs[source]
+	if ok {
+		return catalog
+	}
+	catalog, err := l.options.ResolveCatalog(source)
+	if err != nil {
+		l.report(LintSeverityWarning, LintCheckCatalogUnavailable, "", "control IDs are not checked: failed to load the catalog of %s: %v", source, err)
+		catalog = nil
+	}
+	l.catalogs[source] = catalog
+	return catalog
+}
+
+// policyIdsOfRule returns the policies of the rule for the PVP. Only OCM requires Policy_Id or Check_Id.
+func policyIdsOfRule(pvp string, rule RuleObject) []string {
+	return rule.PolicyIds(pvp != LintPVPOcm)
+}
+
+// parameterIdsOfPolicy returns the parameters of the rules using the policy.
+func parameterIdsOfPolicy(pvp string, policyId string, rules []RuleObject) []string {
+	paramIds := []string{}
+	for _, rule := range rules {
+		if slices.Contains(policyIdsOfRule(pvp, rule), policyId) && rule.ParameterId != "" {
+			paramIds = append(paramIds, rule.ParameterId)
+		}
+	}
+	return paramIds
+}
+
+func findParameterIdsInDir(dir string) ([]string, error) {
+	paramIds := []string{}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !(strings.HasSuffix(info.Name(), ".yaml") || strings.HasSuffix(info.Name(), ".yml")) {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		for _, paramId := range pkg.FindParameterIds(data) {
+			if !slices.Contains(paramIds, paramId) {
+				paramIds = append(paramIds, paramId)
+			}
+		}
+		return nil
+	})
+	sort.Strings(paramIds)
+	return paramIds, err
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oscal
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/oscal-compass/compliance-to-policy/go/pkg"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal"
+	cd "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/componentdefinition"
+)
+
+func loadTestCatalog(t *testing.T) *oscal.Catalog {
+	var catalogRoot oscal.CatalogRoot
+	err := pkg.LoadOscalFileToObject(pkg.PathFromPkgDirectory("./testdata/ocm/catalog.json"), &catalogRoot)
+	assert.NoError(t, err, "Should not happen")
+	return &catalogRoot.Catalog
+}
+
+func TestLintComponentDefinition(t *testing.T) {
+	var cdRoot cd.ComponentDefinitionRoot
+	err := pkg.LoadOscalFileToObject(pkg.PathFromPkgDirectory("./oscal/testdata/lint/component-definition.json"), &cdRoot)
+	assert.NoError(t, err, "Should not happen")
+	catalog := loadTestCatalog(t)
+
+	issues := LintComponentDefinition(cdRoot, LintOptions{
+		PVP:       LintPVPOcm,
+		PolicyDir: pkg.PathFromPkgDirectory("./oscal/testdata/lint/policies"),
+		ResolveCatalog: func(source string) (*oscal.Catalog, error) {
+			return catalog, nil
+		},
+	})
+	actual := [][]string{}
+	for _, issue := range issues {
+		assert.Equal(t, LintSeverityError, issue.Severity)
+		assert.Equal(t, "Lint Test", issue.Component)
+		actual = append(actual, []string{issue.Check, issue.Location})
+	}
+	expected := [][]string{
+		{LintCheckInconsistentRemarks, "props"},
+		{LintCheckDuplicateRule, "props[rule_set_02]"},
+		{LintCheckInconsistentRemarks, "props[rule_set_03]"},
+		{LintCheckUnknownParameter, "rule_a"},
+		{LintCheckMissingPolicyId, "rule_b"},
+		{LintCheckMissingPolicy, "rule_c"},
+		{LintCheckUnknownRule, "ac-1"},
+		{LintCheckUnknownControl, "xx-99"},
+		{LintCheckUnknownParameter, "set-parameters"},
+	}
+	assert.Equal(t, expected, actual)
+	assert.True(t, HasLintErrors(issues))
+}
+
+func TestLintComponentDefinitionWithoutIssues(t *testing.T) {
+	var cdRoot cd.ComponentDefinitionRoot
+	err := pkg.LoadOscalFileToObject(pkg.PathFromPkgDirectory("./testdata/kyverno/component-definition.json"), &cdRoot)
+	assert.NoError(t, err, "Should not happen")
+	catalog := loadTestCatalog(t)
+
+	issues := LintComponentDefinition(cdRoot, LintOptions{
+		PVP:       LintPVPKyverno,
+		PolicyDir: pkg.PathFromPkgDirectory("./testdata/kyverno/policy-resources"),
+		ResolveCatalog: func(source string) (*oscal.Catalog, error) {
+			return catalog, nil
+		},
+	})
+	assert.Empty(t, issues)
+
+	issues = LintComponentDefinition(cdRoot, LintOptions{
+		ResolveCatalog: func(source string) (*oscal.Catalog, error) {
+			return nil, errors.New("not found")
+		},
+	})
+	assert.Len(t, issues, 1)
+	assert.Equal(t, LintCheckCatalogUnavailable, issues[0].Check)
+	assert.False(t, HasLintErrors(issues))
+}
+
+func TestLintComponentDefinitionWithValidationComponent(t *testing.T) {
+	var cdRoot cd.ComponentDefinitionRoot
+	err := pkg.LoadOscalFileToObject(pkg.PathFromPkgDirectory("./testdata/kyverno/validation-component/component-definition.json"), &cdRoot)
+	assert.NoError(t, err, "Should not happen")
+	catalog := loadTestCatalog(t)
+
+	issues := LintComponentDefinition(cdRoot, LintOptions{
+		PVP:       LintPVPKyverno,
+		PolicyDir: pkg.PathFromPkgDirectory("./testdata/kyverno/policy-resources"),
+		ResolveCatalog: func(source string) (*oscal.Catalog, error) {
+			return catalog, nil
+		},
+	})
+	assert.Empty(t, issues)
+}
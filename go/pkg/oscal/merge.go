@@ -0,0 +1,352 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oscal
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	typear "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/assessmentresults"
+	typecommon "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/common"
+)
+
+// AssessmentResultsSource is an assessment results document to be merged and the name of its source (e.g. the PVP or the file).
+type AssessmentResultsSource struct {
+	Name              string
+	AssessmentResults typear.AssessmentResultsRoot
+}
+
+// Names of the props of the observations keeping the name and the UUID of the source assessment results
+var provenancePropNames = []string{"source", "source-uuid"}
+
+type MergeOptions struct {
+	// Rule aggregating the statuses of the findings of a control across the sources (e.g. all-pass: any fail means fail)
+	AggregationRule AggregationRule
+	Stamper         Stamper
+}
+
+// MergeAssessmentResults merges the results of the assessment results of multiple PVPs against the same component-definition into one result.
+//   - Inventory items having the same props and description are merged, and the subjects referring to them are updated.
+//   - Observations having the same UUID are merged, and the subjects are deduplicated by the subject-uuid (the later source wins).
+//   - Observations have the props "source" and "source-uuid" of the assessment results they come from.
+//   - Reviewed controls are the union of the reviewed controls of the sources.
+//   - Findings of the same target (type and target-id, e.g. statement-id cm-6_smt) are merged and their statuses are aggregated
+//     by the aggregation rule. The implementation-statement-uuid is dropped if the merged findings have different ones.
+//     Findings having no related observations are not counted.
+//   - The import-ap is that of the first source, and the back-matter resources of all the sources are kept (deduplicated by the UUID)
+//     so that the href of the import-ap to a back-matter resource is resolved.
+func MergeAssessmentResults(sources []AssessmentResultsSource, options MergeOptions) (*typear.AssessmentResultsRoot, error) {
+	if len(sources) == 0 {
+		return nil, errors.New("no assessment results to be merged")
+	}
+	aggregationRule := options.AggregationRule
+	if aggregationRule == "" {
+		aggregationRule = AggregationRuleAllPass
+	}
+
+	m := newAssessmentResultsMerger()
+	names := []string{}
+	sourceUUIDs := []string{}
+	inputTimestamps := []time.Time{}
+	for _, source := range sources {
+		ar := source.AssessmentResults.AssessmentResults
+		names = append(names, source.Name)
+		sourceUUIDs = append(sourceUUIDs, ar.UUID)
+		inputTimestamps = append(inputTimestamps, ar.Metadata.LastModified)
+		provenance := []typecommon.Prop{
+			{Name: provenancePropNames[0], Value: source.Name},
+			{Name: provenancePropNames[1], Value: ar.UUID},
+		}
+		for _, result := range ar.Results {
+			m.merge(result, provenance)
+		}
+		if ar.BackMatter != nil {
+			for _, resource := range ar.BackMatter.Resources {
+				m.mergeResource(resource)
+			}
+		}
+	}
+
+	timestamp, err := options.Stamper.Timestamp(inputTimestamps...)
+	if err != nil {
+		return nil, err
+	}
+	result := typear.Result{
+		UUID:        options.Stamper.UUID(append([]string{"result", "merged"}, sourceUUIDs...)...),
+		Title:       "Merged Assessment Results",
+		Description: fmt.Sprintf("Assessment Results merged from %s", strings.Join(names, ", ")),
+		Start:       timestamp,
+		LocalDefinitions: typear.LocalDefinitions{
+			InventoryItems: m.inventoryItems,
+		},
+		ReviewedControls: typear.ReviewedControl{
+			ControlSelections: []typear.ControlSelection{{
+				IncludeControls: m.controls,
+			}},
+		},
+		Observations: m.observations,
+		Findings:     m.mergedFindings(aggregationRule, options.Stamper),
+	}
+	ar := typear.AssessmentResults{
+		UUID: options.Stamper.UUID(append([]string{"assessment-results", "merged"}, sourceUUIDs...)...),
+		Metadata: typear.Metadata{
+			Title:        "OSCAL Assessment Results",
+			LastModified: timestamp,
+			Version:      "0.0.1",
+			OscalVersion: OscalVersion,
+		},
+		ImportAp: sources[0].AssessmentResults.AssessmentResults.ImportAp,
+		Results:  []typear.Result{result},
+	}
+	if len(m.resources) > 0 {
+		ar.BackMatter = &typecommon.BackMatter{Resources: m.resources}
+	}
+	if options.Stamper.IsDeterministic() {
+		SortAssessmentResults(&ar)
+	}
+	return &typear.AssessmentResultsRoot{AssessmentResults: ar}, nil
+}
+
+type assessmentResultsMerger struct {
+	inventoryItems []typear.InventoryItem
+	// UUID of the merged inventory item by the props and description
+	inventoryUUIDs map[string]string
+	controls       []typear.SelectControlById
+	observations   []typear.Observation
+	// Index of the merged observation by the UUID
+	observationIndex map[string]int
+	findings         []typear.Finding
+	findingReasons   [][]string
+	// Index of the merged finding by the implementation statement and the target
+	findingIndex map[string]int
+	// Back-matter resources deduplicated by the UUID
+	resources []typecommon.Resource
+}
+
+func newAssessmentResultsMerger() *assessmentResultsMerger {
+	return &assessmentResultsMerger{
+		inventoryItems:   []typear.InventoryItem{},
+		inventoryUUIDs:   map[string]string{},
+		controls:         []typear.SelectControlById{},
+		observations:     []typear.Observation{},
+		observationIndex: map[string]int{},
+		findings:         []typear.Finding{},
+		findingReasons:   [][]string{},
+		findingIndex:     map[string]int{},
+	}
+}
+
+func (m *assessmentResultsMerger) merge(result typear.Result, provenance []typecommon.Prop) {
+	// UUIDs of the inventory items of the result are replaced with the UUIDs of the merged inventory items
+	replacedUUIDs := map[string]string{}
+	for _, item := range result.LocalDefinitions.InventoryItems {
+		key := propsKey(item.Props) + "\x00" + item.Description
+		if len(item.Props) == 0 && item.Description == "" {
+			key = item.UUID
+		}
+		mergedUUID, ok := m.inventoryUUIDs[key]
+		if !ok {
+			mergedUUID = item.UUID
+			m.inventoryUUIDs[key] = mergedUUID
+			m.inventoryItems = append(m.inventoryItems, item)
+		}
+		replacedUUIDs[item.UUID] = mergedUUID
+	}
+
+	for _, selection := range result.ReviewedControls.ControlSelections {
+		for _, control := range selection.IncludeControls {
+			m.mergeControl(control)
+		}
+	}
+
+	for _, observation := range result.Observations {
+		subjects := []typear.Subject{}
+		for _, subject := range observation.Subjects {
+			if mergedUUID, ok := replacedUUIDs[subject.SubjectUUID]; ok {
+				subject.SubjectUUID = mergedUUID
+			}
+			subjects = mergeSubject(subjects, subject)
+		}
+		observation.Subjects = subjects
+		m.mergeObservation(observation, provenance)
+	}
+
+	for _, finding := range result.Findings {
+		m.mergeFinding(finding)
+	}
+}
+
+func (m *assessmentResultsMerger) mergeResource(resource typecommon.Resource) {
+	for _, merged := range m.resources {
+		if merged.UUID == resource.UUID {
+			return
+		}
+	}
+	m.resources = append(m.resources, resource)
+}
+
+func (m *assessmentResultsMerger) mergeControl(control typear.SelectControlById) {
+	idx := sort.Search(len(m.controls), func(i int) bool {
+		return m.controls[i].ControlID >= control.ControlID
+	})
+	if idx == len(m.controls) || m.controls[idx].ControlID != control.ControlID {
+		control.StatementIds = slices.Clone(control.StatementIds)
+		m.controls = append(m.controls[:idx], append([]typear.SelectControlById{control}, m.controls[idx:]...)...)
+		return
+	}
+	merged := &m.controls[idx]
+	// The whole control is reviewed if any of the sources reviews the whole control
+	if len(merged.StatementIds) == 0 || len(control.StatementIds) == 0 {
+		merged.StatementIds = nil
+		return
+	}
+	for _, statementId := range control.StatementIds {
+		if !slices.Contains(merged.StatementIds, statementId) {
+			merged.StatementIds = append(merged.StatementIds, statementId)
+		}
+	}
+	sort.Strings(merged.StatementIds)
+}
+
+func (m *assessmentResultsMerger) mergeObservation(observation typear.Observation, provenance []typecommon.Prop) {
+	idx, ok := m.observationIndex[observation.UUID]
+	if !ok {
+		observation.Props = appendProvenance(append([]typecommon.Prop{}, observation.Props...), provenance)
+		m.observationIndex[observation.UUID] = len(m.observations)
+		m.observations = append(m.observations, observation)
+		return
+	}
+	merged := &m.observations[idx]
+	for _, subject := range observation.Subjects {
+		merged.Subjects = mergeSubject(merged.Subjects, subject)
+	}
+	merged.Props = mergeProps(merged.Props, observation.Props)
+	merged.Props = appendProvenance(merged.Props, provenance)
+	if observation.Collected.After(merged.Collected) {
+		merged.Collected = observation.Collected
+	}
+}
+
+func (m *assessmentResultsMerger) mergeFinding(finding typear.Finding) {
+	reason := finding.Target.Status.Reason
+	if len(finding.RelatedObservations) == 0 {
+		reason = "skip"
+	} else if reason != "pass" && reason != "fail" && reason != "other" {
+		reason = "fail"
+		if finding.Target.Status.State == FindingStateSatisfied {
+			reason = "pass"
+		}
+	}
+	// The implemented requirements of the same control have different UUIDs in the component-definitions of the PVPs
+	key := findingKey(finding)
+	idx, ok := m.findingIndex[key]
+	if !ok {
+		finding.RelatedObservations = append([]typear.RelatedObservation{}, finding.RelatedObservations...)
+		m.findingIndex[key] = len(m.findings)
+		m.findings = append(m.findings, finding)
+		m.findingReasons = append(m.findingReasons, []string{reason})
+		return
+	}
+	merged := &m.findings[idx]
+	if merged.ImplementationStatementUUID != finding.ImplementationStatementUUID {
+		// The merged finding is not of a single implemented requirement
+		merged.ImplementationStatementUUID = ""
+	}
+	for _, relatedObservation := range finding.RelatedObservations {
+		if !containsRelatedObservation(merged.RelatedObservations, relatedObservation) {
+			merged.RelatedObservations = append(merged.RelatedObservations, relatedObservation)
+		}
+	}
+	m.findingReasons[idx] = append(m.findingReasons[idx], reason)
+}
+
+func (m *assessmentResultsMerger) mergedFindings(aggregationRule AggregationRule, stamper Stamper) []typear.Finding {
+	findings := []typear.Finding{}
+	for idx, finding := range m.findings {
+		state, reason := aggregationRule.Aggregate(m.findingReasons[idx])
+		finding.UUID = stamper.UUID("finding", finding.Target.Type, finding.Target.TargetId)
+		finding.Target.Status = typear.ObjectiveStatus{
+			State:  state,
+			Reason: reason,
+		}
+		findings = append(findings, finding)
+	}
+	return findings
+}
+
+// findingKey identifies the findings of the same objective (e.g. statement-id cm-6_smt) across the sources.
+func findingKey(finding typear.Finding) string {
+	return finding.Target.Type + "\x00" + finding.Target.TargetId
+}
+
+// mergeSubject appends the subject, or replaces the subject having the same subject-uuid (or title if the subject has no UUID).
+func mergeSubject(subjects []typear.Subject, subject typear.Subject) []typear.Subject {
+	for idx, s := range subjects {
+		if s.SubjectUUID == subject.SubjectUUID && (subject.SubjectUUID != "" || s.Title == subject.Title) {
+			subjects[idx] = subject
+			return subjects
+		}
+	}
+	return append(subjects, subject)
+}
+
+// mergeProps replaces the props having the same name with the new props (the provenance props are appended).
+func mergeProps(props []typecommon.Prop, newProps []typecommon.Prop) []typecommon.Prop {
+	for _, prop := range newProps {
+		if slices.Contains(provenancePropNames, prop.Name) {
+			props = appendProvenance(props, []typecommon.Prop{prop})
+			continue
+		}
+		idx := slices.IndexFunc(props, func(p typecommon.Prop) bool { return p.Name == prop.Name })
+		if idx < 0 {
+			props = append(props, prop)
+		} else {
+			props[idx] = prop
+		}
+	}
+	return props
+}
+
+// appendProvenance appends the props which are not in the props yet.
+func appendProvenance(props []typecommon.Prop, provenance []typecommon.Prop) []typecommon.Prop {
+	for _, prop := range provenance {
+		found := false
+		for _, p := range props {
+			if p.Name == prop.Name && p.Value == prop.Value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			props = append(props, prop)
+		}
+	}
+	return props
+}
+
+func containsRelatedObservation(relatedObservations []typear.RelatedObservation, relatedObservation typear.RelatedObservation) bool {
+	for _, r := range relatedObservations {
+		if r.ObservationUUID == relatedObservation.ObservationUUID {
+			return true
+		}
+	}
+	return false
+}
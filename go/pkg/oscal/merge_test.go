@@ -0,0 +1,189 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oscal
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/oscal-compass/compliance-to-policy/go/pkg"
+	typear "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/assessmentresults"
+	typecommon "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/common"
+)
+
+func makeTestAssessmentResults(uuid string, inventoryUUID string, observationUUID string, result string, controls []typear.SelectControlById, findings []typear.Finding) typear.AssessmentResultsRoot {
+	return typear.AssessmentResultsRoot{AssessmentResults: typear.AssessmentResults{
+		UUID: uuid,
+		Results: []typear.Result{{
+			LocalDefinitions: typear.LocalDefinitions{
+				InventoryItems: []typear.InventoryItem{{UUID: inventoryUUID, Props: []typecommon.Prop{{Name: "cluster-name", Value: "cluster1"}}}},
+			},
+			ReviewedControls: typear.ReviewedControl{
+				ControlSelections: []typear.ControlSelection{{IncludeControls: controls}},
+			},
+			Observations: []typear.Observation{{
+				UUID:  observationUUID,
+				Props: []typecommon.Prop{{Name: "assessment-rule-id", Value: "rule_a"}, {Name: "result", Value: result}},
+				Subjects: []typear.Subject{{
+					SubjectUUID: inventoryUUID,
+					Title:       "Cluster Name: cluster1",
+					Props:       []typecommon.Prop{{Name: "result", Value: result}},
+				}},
+			}},
+			Findings: findings,
+		}},
+	}}
+}
+
+func makeTestFinding(targetId string, state string, reason string, observationUUIDs ...string) typear.Finding {
+	relatedObservations := []typear.RelatedObservation{}
+	for _, observationUUID := range observationUUIDs {
+		relatedObservations = append(relatedObservations, typear.RelatedObservation{ObservationUUID: observationUUID})
+	}
+	return typear.Finding{
+		Target: typear.FindingTarget{
+			Type:     "statement-id",
+			TargetId: targetId,
+			Status:   typear.ObjectiveStatus{State: state, Reason: reason},
+		},
+		RelatedObservations: relatedObservations,
+	}
+}
+
+func TestMergeAssessmentResults(t *testing.T) {
+	ocm := makeTestAssessmentResults("ar-1", "inventory-1", "observation-1", "fail",
+		[]typear.SelectControlById{{ControlID: "ac-1"}, {ControlID: "cm-8", StatementIds: []string{"cm-8_smt.a"}}},
+		[]typear.Finding{makeTestFinding("ac-1_smt", FindingStateNotSatisfied, "fail", "observation-1")},
+	)
+	kyverno := makeTestAssessmentResults("ar-2", "inventory-2", "observation-2", "pass",
+		[]typear.SelectControlById{{ControlID: "cm-8", StatementIds: []string{"cm-8_smt.b"}}, {ControlID: "cm-6"}},
+		[]typear.Finding{
+			makeTestFinding("ac-1_smt", FindingStateSatisfied, "pass", "observation-2"),
+			makeTestFinding("cm-6_smt", FindingStateNotSatisfied, "other"),
+		},
+	)
+	sources := []AssessmentResultsSource{{Name: "ocm", AssessmentResults: ocm}, {Name: "kyverno", AssessmentResults: kyverno}}
+
+	merged, err := MergeAssessmentResults(sources, MergeOptions{Stamper: NewStamper(true)})
+	assert.NoError(t, err, "Should not happen")
+	result := merged.AssessmentResults.Results[0]
+
+	assert.Len(t, result.LocalDefinitions.InventoryItems, 1)
+	assert.Equal(t, "inventory-1", result.LocalDefinitions.InventoryItems[0].UUID)
+
+	assert.Equal(t, []typear.SelectControlById{
+		{ControlID: "ac-1"},
+		{ControlID: "cm-6"},
+		{ControlID: "cm-8", StatementIds: []string{"cm-8_smt.a", "cm-8_smt.b"}},
+	}, result.ReviewedControls.ControlSelections[0].IncludeControls)
+
+	assert.Len(t, result.Observations, 2)
+	for _, observation := range result.Observations {
+		assert.Equal(t, "inventory-1", observation.Subjects[0].SubjectUUID)
+	}
+	source, _ := FindProp("source", result.Observations[1].Props)
+	assert.Equal(t, "kyverno", source.Value)
+	sourceUUID, _ := FindProp("source-uuid", result.Observations[1].Props)
+	assert.Equal(t, "ar-2", sourceUUID.Value)
+
+	assert.Len(t, result.Findings, 2)
+	assert.Equal(t, "ac-1_smt", result.Findings[0].Target.TargetId)
+	assert.Equal(t, FindingStateNotSatisfied, result.Findings[0].Target.Status.State)
+	assert.Len(t, result.Findings[0].RelatedObservations, 2)
+	// Findings having no related observations are not counted
+	assert.Equal(t, "cm-6_smt", result.Findings[1].Target.TargetId)
+	assert.Equal(t, typear.ObjectiveStatus{State: FindingStateNotSatisfied, Reason: "other"}, result.Findings[1].Target.Status)
+
+	merged, err = MergeAssessmentResults(sources, MergeOptions{AggregationRule: AggregationRuleAnyPass, Stamper: NewStamper(true)})
+	assert.NoError(t, err, "Should not happen")
+	assert.Equal(t, FindingStateSatisfied, merged.AssessmentResults.Results[0].Findings[0].Target.Status.State)
+}
+
+func TestMergeAssessmentResultsOfPVPs(t *testing.T) {
+	var ocm, kyverno typear.AssessmentResultsRoot
+	err := pkg.LoadJsonFileToObject(pkg.PathFromPkgDirectory("./testdata/ocm/assessment-results.json"), &ocm)
+	assert.NoError(t, err, "Should not happen")
+	err = pkg.LoadJsonFileToObject(pkg.PathFromPkgDirectory("./testdata/kyverno/scoped/assessment-results.json"), &kyverno)
+	assert.NoError(t, err, "Should not happen")
+
+	merged, err := MergeAssessmentResults([]AssessmentResultsSource{
+		{Name: "ocm", AssessmentResults: ocm},
+		{Name: "kyverno", AssessmentResults: kyverno},
+	}, MergeOptions{Stamper: NewStamper(true)})
+	assert.NoError(t, err, "Should not happen")
+
+	findings := map[string]typear.Finding{}
+	for _, finding := range merged.AssessmentResults.Results[0].Findings {
+		assert.Equal(t, "statement-id", finding.Target.Type)
+		findings[finding.Target.TargetId] = finding
+	}
+	assert.Len(t, findings, 4)
+	// cm-2 is implemented by both PVPs by the implemented requirements having different UUIDs
+	assert.Len(t, findings["cm-2_smt"].RelatedObservations, 2)
+	assert.Equal(t, "", findings["cm-2_smt"].ImplementationStatementUUID)
+	assert.Len(t, findings["cm-8_smt"].RelatedObservations, 1)
+	assert.Equal(t, kyverno.AssessmentResults.Results[0].Findings[1].ImplementationStatementUUID, findings["cm-8_smt"].ImplementationStatementUUID)
+
+	// The href of the import-ap refers to a back-matter resource of the merged assessment results
+	importAp := merged.AssessmentResults.ImportAp
+	assert.Equal(t, ocm.AssessmentResults.ImportAp, importAp)
+	assert.NotNil(t, merged.AssessmentResults.BackMatter)
+	resourceUUIDs := []string{}
+	for _, resource := range merged.AssessmentResults.BackMatter.Resources {
+		resourceUUIDs = append(resourceUUIDs, resource.UUID)
+	}
+	assert.Contains(t, resourceUUIDs, strings.TrimPrefix(importAp.Href, "#"))
+	// The resources of all the sources are kept once
+	assert.Len(t, resourceUUIDs, 2)
+	merged, err = MergeAssessmentResults([]AssessmentResultsSource{
+		{Name: "ocm", AssessmentResults: ocm},
+		{Name: "ocm-again", AssessmentResults: ocm},
+	}, MergeOptions{Stamper: NewStamper(true)})
+	assert.NoError(t, err, "Should not happen")
+	assert.Len(t, merged.AssessmentResults.BackMatter.Resources, 1)
+}
+
+func TestMergeAssessmentResultsWithSameObservations(t *testing.T) {
+	controls := []typear.SelectControlById{{ControlID: "ac-1"}}
+	first := makeTestAssessmentResults("ar-1", "inventory-1", "observation-1", "fail", controls, nil)
+	second := makeTestAssessmentResults("ar-2", "inventory-1", "observation-1", "pass", controls, nil)
+
+	merged, err := MergeAssessmentResults([]AssessmentResultsSource{
+		{Name: "first", AssessmentResults: first},
+		{Name: "second", AssessmentResults: second},
+	}, MergeOptions{Stamper: NewStamper(true)})
+	assert.NoError(t, err, "Should not happen")
+
+	observations := merged.AssessmentResults.Results[0].Observations
+	assert.Len(t, observations, 1)
+	assert.Len(t, observations[0].Subjects, 1)
+	result, _ := FindProp("result", observations[0].Subjects[0].Props)
+	assert.Equal(t, "pass", result.Value)
+	assert.Equal(t, []typecommon.Prop{
+		{Name: "assessment-rule-id", Value: "rule_a"},
+		{Name: "result", Value: "pass"},
+		{Name: "source", Value: "first"},
+		{Name: "source-uuid", Value: "ar-1"},
+		{Name: "source", Value: "second"},
+		{Name: "source-uuid", Value: "ar-2"},
+	}, observations[0].Props)
+
+	_, err = MergeAssessmentResults([]AssessmentResultsSource{}, MergeOptions{})
+	assert.Error(t, err)
+}
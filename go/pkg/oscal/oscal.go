@@ -34,6 +34,8 @@ var standardFromPolicyCollectionToOscal map[string]string = map[string]string{
 
 const (
 	OscaleNamespace = "http://oscal-compass.github.io/compliance-trestle/schemas/oscal/cd"
+	// Version of the OSCAL schemas the generated documents conform to (see pkg/oscal/validation)
+	OscalVersion = "1.1.2"
 )
 
 func controlIdFromPolicyCollectionToOscal(controlId string) (string, bool) {
@@ -71,8 +73,14 @@ func FindProp(name string, props []typecommon.Prop) (typecommon.Prop, bool) {
 	return typecommon.Prop{}, false
 }
 
+// ToPropValue makes the text a valid prop value, which is a string without line breaks and leading or trailing spaces.
+func ToPropValue(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// GenerateUUID returns a random (version 4) UUID as required by the OSCAL schemas.
 func GenerateUUID() string {
-	uuid, err := uuid.NewUUID()
+	uuid, err := uuid.NewRandom()
 	if err != nil {
 		return "01234567-yyyy-zzzz-1111-0123456789ab"
 	}
@@ -130,14 +138,20 @@ func makeComponentDefinitionFromMasterData(resourceTable *resources.Table) *cd.C
 
 type TrestleCsvRow struct {
 	TrestleComponentProps
-	ControlIdList        []string
-	RuleId               string
-	RuleDescription      string
-	ParameterId          string
-	ParameterDescription string
-	ProfileSource        string
-	ProfileDescription   string
-	Namespace            string
+	ControlIdList              []string
+	RuleId                     string
+	RuleDescription            string
+	ParameterId                string
+	ParameterDescription       string
+	ParameterValueAlternatives string
+	ParameterValueDefault      string
+	CheckId                    string
+	CheckDescription           string
+	ProfileSource              string
+	ProfileDescription         string
+	Namespace                  string
+	// Columns not prefixed by $, which are added to the props of the rule (e.g. Policy_Id)
+	UserColumns map[string]string
 }
 
 type TrestleComponentProps struct {
@@ -147,84 +161,58 @@ type TrestleComponentProps struct {
 }
 
 func (t *TrestleCsvRow) Header() []string {
-	return []string{
-		"$$Component_Title",
-		"$$Component_Type",
-		"$$Control_Id_List",
-		"$$Rule_Id",
-		"$$Rule_Description",
-		"$Parameter_Id",
-		"$Parameter_Description",
-		"$$Profile_Source",
-		"$$Profile_Description",
-		"$$Namespace",
-		"$$Component_Description",
+	header := []string{}
+	for _, column := range trestleCsvColumns {
+		header = append(header, column.name)
 	}
+	return header
 }
 
 func (t *TrestleCsvRow) ToStringList() []string {
-	controlIdList := []string{}
-	for _, control := range t.ControlIdList {
-		controlId, _ := controlIdFromPolicyCollectionToOscal(control)
-		controlIdList = append(controlIdList, controlId)
-	}
-	return []string{
-		t.ComponentTitle,
-		t.ComponentType,
-		strings.Join(controlIdList, " "),
-		t.RuleId,
-		t.RuleDescription,
-		t.ParameterId,
-		t.ParameterDescription,
-		t.ProfileSource,
-		t.ProfileDescription,
-		t.Namespace,
-		t.ComponentDescription,
+	values := []string{}
+	for _, column := range trestleCsvColumns {
+		values = append(values, column.get(t))
 	}
+	return values
 }
 
-func makeTrestleCsvFromMasterData(componentProps TrestleComponentProps, namespace string, resourceTable *resources.Table) map[string][]TrestleCsvRow {
-	rowsMap := map[string][]TrestleCsvRow{}
-	groupedByStandard := resourceTable.GroupBy("standard")
-	for standard, resourcesByStandard := range groupedByStandard {
-		rows := []TrestleCsvRow{}
-		groupedByPolicy := resourcesByStandard.GroupBy("policy")
-		for policy, resourcesByPolicy := range groupedByPolicy {
-			groupedByControl := resourcesByPolicy.GroupBy("control")
-			controls := []string{}
-			for control := range groupedByControl {
-				controls = append(controls, control)
-			}
-			row := TrestleCsvRow{
-				TrestleComponentProps: componentProps,
-				RuleId:                policy,
-				RuleDescription:       "Description of " + policy,
-				ProfileSource:         standard,
-				ProfileDescription:    "Description of " + standard,
-				ControlIdList:         controls,
-				Namespace:             namespace,
-			}
-			rows = append(rows, row)
-		}
-		rowsMap[standard] = rows
+func IntersectProfileWithCD(compDef cd.ComponentDefinition, profile oscal.Profile) cd.ComponentDefinition {
+	intersected := intersectWithCD(compDef, profile.Metadata.Title, func(controlId string) bool {
+		return findControlId(profile, controlId)
+	})
+	intersected.UUID = "cdfd629a-bd62-11ed-afa1-0242ac120002"
+	return intersected
+}
+
+// IntersectResolvedProfileWithCD keeps only the implemented requirements for the controls in the resolved profile.
+func IntersectResolvedProfileWithCD(compDef cd.ComponentDefinition, resolvedProfile oscal.Catalog) cd.ComponentDefinition {
+	controlIds := map[string]bool{}
+	for _, controlId := range ListControlIds(resolvedProfile) {
+		controlIds[controlId] = true
 	}
-	return rowsMap
+	return intersectWithCD(compDef, "", func(controlId string) bool {
+		return controlIds[controlId]
+	})
 }
 
-func IntersectProfileWithCD(compDef cd.ComponentDefinition, profile oscal.Profile) cd.ComponentDefinition {
+func intersectWithCD(compDef cd.ComponentDefinition, source string, contains func(controlId string) bool) cd.ComponentDefinition {
 	components := []cd.Component{}
 	for _, component := range compDef.Components {
 		controlImplementations := []cd.ControlImplementation{}
 		for _, controlImpl := range component.ControlImplementations {
 			implReqs := []cd.ImplementedRequirement{}
 			for _, implReq := range controlImpl.ImplementedRequirements {
-				if findControlId(profile, implReq.ControlID) {
+				if contains(implReq.ControlID) {
 					implReqs = append(implReqs, implReq)
 				}
 			}
+			_source := controlImpl.Source
+			if source != "" {
+				_source = source
+			}
 			controlImplementations = append(controlImplementations, cd.ControlImplementation{
 				UUID:                    controlImpl.UUID,
-				Source:                  profile.Metadata.Title,
+				Source:                  _source,
 				Description:             controlImpl.Description,
 				Props:                   controlImpl.Props,
 				SetParameters:           controlImpl.SetParameters,
@@ -241,19 +229,31 @@ func IntersectProfileWithCD(compDef cd.ComponentDefinition, profile oscal.Profil
 		})
 	}
 	return cd.ComponentDefinition{
-		UUID:       "cdfd629a-bd62-11ed-afa1-0242ac120002",
+		UUID:       compDef.UUID,
 		Metadata:   compDef.Metadata,
 		Components: components,
 	}
 }
 
+// findControlId checks the control selection of the profile itself without following the imports.
+// Use ProfileResolver to get the controls of profiles importing other profiles.
 func findControlId(profile oscal.Profile, controlId string) bool {
 	for _, profileImport := range profile.Imports {
-		for _, includeControl := range profileImport.IncludeControls {
-			for _, id := range includeControl.WithIds {
-				if id == controlId {
-					return true
-				}
+		excluded := false
+		for _, selection := range profileImport.ExcludeControls {
+			if matchesSelection(selection, controlId) {
+				excluded = true
+			}
+		}
+		if excluded {
+			continue
+		}
+		if profileImport.IncludeAll != nil {
+			return true
+		}
+		for _, selection := range profileImport.IncludeControls {
+			if matchesSelection(selection, controlId) {
+				return true
 			}
 		}
 	}
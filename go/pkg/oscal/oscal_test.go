@@ -17,11 +17,9 @@ limitations under the License.
 package oscal
 
 import (
-	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"os"
-	"strings"
 	"testing"
 
 	"github.com/oscal-compass/compliance-to-policy/go/pkg"
@@ -95,39 +93,6 @@ func TestCD2InternalCompliance(t *testing.T) {
 	}
 }
 
-func TestTrestleCsv(t *testing.T) {
-	f, err := os.Open(pkg.PathFromPkgDirectory("./oscal/testdata/resources.csv"))
-	if err != nil {
-		panic(err)
-	}
-	resourceTable := resources.FromCsv(f)
-	componentProps := TrestleComponentProps{
-		ComponentTitle:       "OCM",
-		ComponentDescription: "Description ...",
-		ComponentType:        "Service",
-	}
-	namespace := "https://github.com/oscal-compass/compliance-to-policy/go"
-	trestlCsvRowsMap := makeTrestleCsvFromMasterData(componentProps, namespace, resourceTable)
-	t.Log(trestlCsvRowsMap)
-
-	for standard, trestlCsvRows := range trestlCsvRowsMap {
-		odir := pkg.PathFromPkgDirectory("./oscal/_test")
-		_standard := strings.Replace(standard, "/", "_", -1)
-		_standard = strings.Replace(_standard, " ", "_", -1)
-		csvFile, err := os.Create(fmt.Sprintf("%s/trestle.rule-mapping.%s.csv", odir, _standard))
-		assert.NoError(t, err, "Should be no error")
-
-		wr := csv.NewWriter(csvFile)
-		err = wr.Write(trestlCsvRows[0].Header())
-		assert.NoError(t, err, "Should be no error")
-		for _, row := range trestlCsvRows {
-			err := wr.Write(row.ToStringList())
-			assert.NoError(t, err, "Should be no error")
-		}
-		wr.Flush()
-	}
-}
-
 func makeInternalComplianceFromComponentDefinition(cd cd.ComponentDefinition) internalcompliance.Compliance {
 
 	controls := []internalcompliance.Control{}
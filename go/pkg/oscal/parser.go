@@ -17,6 +17,8 @@ limitations under the License.
 package oscal
 
 import (
+	"strings"
+
 	. "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/componentdefinition"
 )
 
@@ -26,12 +28,93 @@ type RuleObject struct {
 	PolicyId             string
 	ParameterId          string
 	ParameterDescription string
+	// Checks of the rule defined in the component itself or in the validation components
+	Checks []CheckObject
+	// Enforcement and scope of the policies of the rule
+	Scope PolicyScope
+}
+
+// PolicyScope is how and where the policies are enforced, given by the props of a rule or a control implementation
+// (Enforcement_Mode, Target_Namespaces and Excluded_Namespaces).
+type PolicyScope struct {
+	// audit or enforce. Empty if the mode of the policies is kept.
+	EnforcementMode string
+	// Namespaces to which the policies are applied. Empty if the namespaces of the policies are kept.
+	TargetNamespaces []string
+	// Namespaces excluded from the policies
+	ExcludedNamespaces []string
+}
+
+// IsEmpty returns true if the scope does not change the policies.
+func (s PolicyScope) IsEmpty() bool {
+	return s.EnforcementMode == "" && len(s.TargetNamespaces) == 0 && len(s.ExcludedNamespaces) == 0
+}
+
+// setScopeProp sets the field of the scope given by the prop. It returns false if the prop is not a prop of the scope.
+func setScopeProp(scope *PolicyScope, prop Prop) bool {
+	switch prop.Name {
+	case "Enforcement_Mode":
+		scope.EnforcementMode = strings.ToLower(strings.TrimSpace(prop.Value))
+	case "Target_Namespaces":
+		scope.TargetNamespaces = splitList(prop.Value)
+	case "Excluded_Namespaces":
+		scope.ExcludedNamespaces = splitList(prop.Value)
+	default:
+		return false
+	}
+	return true
+}
+
+func splitList(value string) []string {
+	list := []string{}
+	for _, element := range strings.Split(value, ",") {
+		if element = strings.TrimSpace(element); element != "" {
+			list = append(list, element)
+		}
+	}
+	return list
+}
+
+// CheckObject is a check of a PVP (e.g. a Kyverno policy) validating a rule.
+// In the trestle convention, checks are mapped to rules by the validation components.
+type CheckObject struct {
+	CheckId          string
+	CheckDescription string
+	// Title of the component defining the check
+	ComponentTitle string
+}
+
+// CheckIds returns the IDs of the checks of the rule.
+func (r *RuleObject) CheckIds() []string {
+	checkIds := []string{}
+	for _, check := range r.Checks {
+		checkIds = append(checkIds, check.CheckId)
+	}
+	return checkIds
+}
+
+// PolicyIds returns the IDs of the policies (the directories of the policy resources) of the rule: the Policy_Id of the rule,
+// or the Check_Ids of the rule if it has no Policy_Id. If the rule has neither, the Rule_Id is returned if ruleIdFallback is set
+// (PVPs whose policies are named after the rules, e.g. Kyverno), and nothing otherwise.
+func (r *RuleObject) PolicyIds(ruleIdFallback bool) []string {
+	if r.PolicyId != "" {
+		return []string{r.PolicyId}
+	}
+	if len(r.Checks) > 0 {
+		return r.CheckIds()
+	}
+	if ruleIdFallback {
+		return []string{r.RuleId}
+	}
+	return []string{}
 }
 
 type ControlObject struct {
 	ControlId   string
 	StatementId string
 	RuleIds     []string
+	// UUID of the implemented requirement (or the statement if StatementId is set) in the component-definition
+	ImplementationUUID string
 }
 
 func (c *ControlObject) GetControlId() string {
@@ -44,6 +127,8 @@ func (c *ControlObject) GetControlId() string {
 type ControlImpleObject struct {
 	SetParameters  []SetParameter
 	ControlObjects []ControlObject
+	// Enforcement and scope of the policies of the rules of the controls
+	Scope PolicyScope
 }
 
 type ComponentObject struct {
@@ -64,12 +149,15 @@ func FindRulesByRuleId(ruleId string, rules []RuleObject) (RuleObject, bool) {
 
 func GetComponentWideRules(component Component) []RuleObject {
 	ruleMap := map[string]*RuleObject{}
+	// Remarks in the order of appearance so that the rules are returned in the order of the props
+	remarksList := []string{}
 	for _, prop := range component.Props {
 		ruleId := prop.Remarks
 		rule, ok := ruleMap[ruleId]
 		if !ok {
 			rule = &RuleObject{}
 			ruleMap[ruleId] = rule
+			remarksList = append(remarksList, ruleId)
 		}
 		switch prop.Name {
 		case "Rule_Id":
@@ -82,15 +170,73 @@ func GetComponentWideRules(component Component) []RuleObject {
 			rule.ParameterId = prop.Value
 		case "Parameter_Description":
 			rule.ParameterDescription = prop.Value
+		case "Check_Id":
+			rule.Checks = append(rule.Checks, CheckObject{CheckId: prop.Value, ComponentTitle: component.Title})
+		default:
+			setScopeProp(&rule.Scope, prop)
+		}
+	}
+	// Check_Description can precede Check_Id
+	for _, prop := range component.Props {
+		if prop.Name == "Check_Description" {
+			rule := ruleMap[prop.Remarks]
+			if len(rule.Checks) > 0 {
+				rule.Checks[len(rule.Checks)-1].CheckDescription = prop.Value
+			}
 		}
 	}
 	rules := []RuleObject{}
-	for _, rule := range ruleMap {
-		rules = append(rules, *rule)
+	for _, remarks := range remarksList {
+		rules = append(rules, *ruleMap[remarks])
 	}
 	return rules
 }
 
+// linkChecks adds the checks of the rules defined in the other components (i.e. validation components)
+// to the rules having the same Rule_Id.
+func linkChecks(componentObjects []ComponentObject) {
+	for i := range componentObjects {
+		for j := range componentObjects[i].RuleObjects {
+			rule := &componentObjects[i].RuleObjects[j]
+			for k, other := range componentObjects {
+				if k == i {
+					continue
+				}
+				for _, otherRule := range other.RuleObjects {
+					if otherRule.RuleId != rule.RuleId {
+						continue
+					}
+					for _, check := range otherRule.Checks {
+						if !containsCheck(rule.Checks, check.CheckId) {
+							rule.Checks = append(rule.Checks, check)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func containsCheck(checks []CheckObject, checkId string) bool {
+	for _, check := range checks {
+		if check.CheckId == checkId {
+			return true
+		}
+	}
+	return false
+}
+
+// FindRulesByCheckId returns the rules validated by the check.
+func FindRulesByCheckId(checkId string, rules []RuleObject) []RuleObject {
+	found := []RuleObject{}
+	for _, rule := range rules {
+		if containsCheck(rule.Checks, checkId) {
+			found = append(found, rule)
+		}
+	}
+	return found
+}
+
 func ParseComponentDefinition(cd ComponentDefinitionRoot) []ComponentObject {
 	componentObjects := []ComponentObject{}
 	for _, component := range cd.Components {
@@ -99,31 +245,39 @@ func ParseComponentDefinition(cd ComponentDefinitionRoot) []ComponentObject {
 		for _, controlImpl := range component.ControlImplementations {
 			controlObjects := []ControlObject{}
 			for _, implReq := range controlImpl.ImplementedRequirements {
-				ruleIds := []string{}
 				if implReq.Statements != nil && len(implReq.Statements) > 0 {
 					for _, statement := range implReq.Statements {
+						ruleIds := []string{}
 						for _, prop := range listRules(statement.Props) {
 							ruleIds = append(ruleIds, prop.Value)
 						}
 						controlObjects = append(controlObjects, ControlObject{
-							ControlId:   implReq.ControlID,
-							RuleIds:     ruleIds,
-							StatementId: statement.StatementId,
+							ControlId:          implReq.ControlID,
+							RuleIds:            ruleIds,
+							StatementId:        statement.StatementId,
+							ImplementationUUID: statement.UUID,
 						})
 					}
 				} else {
+					ruleIds := []string{}
 					for _, prop := range listRules(implReq.Props) {
 						ruleIds = append(ruleIds, prop.Value)
 					}
 					controlObjects = append(controlObjects, ControlObject{
-						ControlId: implReq.ControlID,
-						RuleIds:   ruleIds,
+						ControlId:          implReq.ControlID,
+						RuleIds:            ruleIds,
+						ImplementationUUID: implReq.UUID,
 					})
 				}
 			}
+			scope := PolicyScope{}
+			for _, prop := range controlImpl.Props {
+				setScopeProp(&scope, prop)
+			}
 			controlImpleObjects = append(controlImpleObjects, ControlImpleObject{
 				SetParameters:  controlImpl.SetParameters,
 				ControlObjects: controlObjects,
+				Scope:          scope,
 			})
 		}
 		componentObjects = append(componentObjects, ComponentObject{
@@ -133,5 +287,6 @@ func ParseComponentDefinition(cd ComponentDefinitionRoot) []ComponentObject {
 			ControlImpleObjects: controlImpleObjects,
 		})
 	}
+	linkChecks(componentObjects)
 	return componentObjects
 }
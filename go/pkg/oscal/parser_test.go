@@ -0,0 +1,105 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oscal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/componentdefinition"
+)
+
+func TestParseComponentDefinitionWithChecks(t *testing.T) {
+	cdRoot := ComponentDefinitionRoot{ComponentDefinition: ComponentDefinition{
+		Components: []Component{{
+			Type:  "service",
+			Title: "Target",
+			Props: []Prop{
+				{Name: "Rule_Id", Value: "rule_a", Remarks: "rule_set_0"},
+				{Name: "Rule_Id", Value: "rule_b", Remarks: "rule_set_1"},
+				{Name: "Check_Id", Value: "check_b", Remarks: "rule_set_1"},
+			},
+		}, {
+			Type:  "validation",
+			Title: "PVP",
+			Props: []Prop{
+				{Name: "Rule_Id", Value: "rule_a", Remarks: "rule_set_0"},
+				{Name: "Check_Description", Value: "Check A1", Remarks: "rule_set_0"},
+				{Name: "Check_Id", Value: "check_a1", Remarks: "rule_set_0"},
+				{Name: "Rule_Id", Value: "rule_a", Remarks: "rule_set_1"},
+				{Name: "Check_Id", Value: "check_a2", Remarks: "rule_set_1"},
+				{Name: "Rule_Id", Value: "rule_b", Remarks: "rule_set_2"},
+				{Name: "Check_Id", Value: "check_b", Remarks: "rule_set_2"},
+			},
+		}},
+	}}
+	componentObjects := ParseComponentDefinition(cdRoot)
+
+	rules := componentObjects[0].RuleObjects
+	assert.Equal(t, []CheckObject{
+		{CheckId: "check_a1", CheckDescription: "Check A1", ComponentTitle: "PVP"},
+		{CheckId: "check_a2", ComponentTitle: "PVP"},
+	}, rules[0].Checks)
+	assert.Equal(t, []CheckObject{{CheckId: "check_b", ComponentTitle: "Target"}}, rules[1].Checks)
+
+	found := FindRulesByCheckId("check_a2", rules)
+	assert.Len(t, found, 1)
+	assert.Equal(t, "rule_a", found[0].RuleId)
+}
+
+func TestPolicyIdsOfRule(t *testing.T) {
+	checks := []CheckObject{{CheckId: "check_a1"}, {CheckId: "check_a2"}}
+
+	rule := RuleObject{RuleId: "rule_a", PolicyId: "policy_a", Checks: checks}
+	assert.Equal(t, []string{"policy_a"}, rule.PolicyIds(false))
+	assert.Equal(t, []string{"policy_a"}, rule.PolicyIds(true))
+
+	rule = RuleObject{RuleId: "rule_a", Checks: checks}
+	assert.Equal(t, []string{"check_a1", "check_a2"}, rule.PolicyIds(false))
+	assert.Equal(t, []string{"check_a1", "check_a2"}, rule.PolicyIds(true))
+
+	rule = RuleObject{RuleId: "rule_a"}
+	assert.Equal(t, []string{}, rule.PolicyIds(false))
+	assert.Equal(t, []string{"rule_a"}, rule.PolicyIds(true))
+}
+
+func TestParseComponentDefinitionWithStatements(t *testing.T) {
+	cdRoot := ComponentDefinitionRoot{ComponentDefinition: ComponentDefinition{
+		Components: []Component{{
+			Type:  "service",
+			Title: "Target",
+			ControlImplementations: []ControlImplementation{{
+				ImplementedRequirements: []ImplementedRequirement{{
+					ControlID: "ac-1",
+					Statements: []Statement{
+						{StatementId: "ac-1_smt.a", UUID: "uuid-a", Props: []Prop{{Name: "Rule_Id", Value: "rule_a"}}},
+						{StatementId: "ac-1_smt.b", UUID: "uuid-b", Props: []Prop{{Name: "Rule_Id", Value: "rule_b"}}},
+					},
+				}},
+			}},
+		}},
+	}}
+	componentObjects := ParseComponentDefinition(cdRoot)
+
+	// Each statement has only its own rules
+	controlObjects := componentObjects[0].ControlImpleObjects[0].ControlObjects
+	assert.Equal(t, []ControlObject{
+		{ControlId: "ac-1", RuleIds: []string{"rule_a"}, StatementId: "ac-1_smt.a", ImplementationUUID: "uuid-a"},
+		{ControlId: "ac-1", RuleIds: []string{"rule_b"}, StatementId: "ac-1_smt.b", ImplementationUUID: "uuid-b"},
+	}, controlObjects)
+}
@@ -0,0 +1,281 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oscal
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	typear "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/assessmentresults"
+	typecommon "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/common"
+	typepoam "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/poam"
+)
+
+const (
+	RiskStatusOpen   = "open"
+	RiskStatusClosed = "closed"
+)
+
+// Name of the prop of the POA&M items having the status open or closed like the risks
+const poamItemStatusPropName = "status"
+
+// Props identifying the POA&M items (failing findings) and the risks (failing observations) across the assessment results
+var (
+	poamItemKeyPropNames = []string{"control-id", "statement-id", "implementation-statement-uuid"}
+	riskKeyPropNames     = []string{"assessment-rule-id", "check-id", "policy-id", "control-id", "statement-id"}
+)
+
+// GeneratePoam returns the POA&M of the failing findings of the assessment results.
+// A POA&M item is made for each finding which is not satisfied due to failing observations, and a risk with a remediation placeholder
+// is made for each failing observation. The observations of the POA&M have the failing subjects only.
+// If the existing POA&M is given, it is updated: the items and the risks are matched by their props (stable keys),
+// the risks which do not fail anymore are closed, and the risks still failing keep their remediations, deadlines and statuses.
+// An item has the prop "status", which is closed if all its risks are closed (e.g. the control passes) and open otherwise.
+func GeneratePoam(arRoot typear.AssessmentResultsRoot, existing *typepoam.PlanOfActionAndMilestonesRoot, stamper Stamper) (*typepoam.PlanOfActionAndMilestonesRoot, error) {
+	timestamp, err := stamper.Timestamp(arRoot.AssessmentResults.Metadata.LastModified)
+	if err != nil {
+		return nil, err
+	}
+	poam := typepoam.PlanOfActionAndMilestones{
+		UUID: stamper.UUID("plan-of-action-and-milestones", arRoot.AssessmentResults.UUID),
+		Metadata: typear.Metadata{
+			Title:   "Plan of Action and Milestones",
+			Version: "0.0.1",
+		},
+		Observations: []typear.Observation{},
+		Risks:        []typear.Risk{},
+		PoamItems:    []typepoam.PoamItem{},
+	}
+	if existing != nil {
+		poam = existing.PlanOfActionAndMilestones
+	}
+	poam.Metadata.LastModified = timestamp
+	poam.Metadata.OscalVersion = OscalVersion
+
+	g := poamGenerator{stamper: stamper, poam: &poam, openRisks: []string{}, observations: []typear.Observation{}}
+	for _, result := range arRoot.AssessmentResults.Results {
+		for _, finding := range result.Findings {
+			g.addFinding(finding, result.Observations)
+		}
+	}
+	g.closeFixed()
+	return &typepoam.PlanOfActionAndMilestonesRoot{PlanOfActionAndMilestones: poam}, nil
+}
+
+type poamGenerator struct {
+	stamper Stamper
+	poam    *typepoam.PlanOfActionAndMilestones
+	// Keys of the risks failing in the assessment results
+	openRisks []string
+	// Failing observations of the assessment results
+	observations []typear.Observation
+}
+
+func (g *poamGenerator) addFinding(finding typear.Finding, observations []typear.Observation) {
+	if finding.Target.Status.State != FindingStateNotSatisfied {
+		return
+	}
+	itemProps := []typecommon.Prop{{Name: "control-id", Value: strings.TrimSuffix(finding.Target.TargetId, "_smt")}}
+	if prop, ok := FindProp("control-id", finding.Props); ok {
+		itemProps = []typecommon.Prop{prop}
+		if prop, ok := FindProp("statement-id", finding.Props); ok {
+			itemProps = append(itemProps, prop)
+		}
+	}
+	if finding.ImplementationStatementUUID != "" {
+		itemProps = append(itemProps, typecommon.Prop{Name: "implementation-statement-uuid", Value: finding.ImplementationStatementUUID})
+	}
+	controlId := controlIdOfItem(itemProps)
+
+	relatedObservations := []typear.RelatedObservation{}
+	relatedRisks := []typepoam.RelatedRisk{}
+	for _, relatedObservation := range finding.RelatedObservations {
+		idx := slices.IndexFunc(observations, func(o typear.Observation) bool { return o.UUID == relatedObservation.ObservationUUID })
+		if idx < 0 {
+			continue
+		}
+		observation, failing := failingObservation(observations[idx])
+		if !failing {
+			continue
+		}
+		if !slices.ContainsFunc(g.observations, func(o typear.Observation) bool { return o.UUID == observation.UUID }) {
+			g.observations = append(g.observations, observation)
+		}
+		relatedObservations = append(relatedObservations, relatedObservation)
+		risk := g.upsertRisk(observation, controlId)
+		relatedRisks = append(relatedRisks, typepoam.RelatedRisk{RiskUUID: risk.UUID})
+	}
+	if len(relatedObservations) == 0 {
+		return
+	}
+
+	key := propsKeyOf(itemProps, poamItemKeyPropNames)
+	description := fmt.Sprintf("%s is not satisfied: %d observation(s) fail", controlId, len(relatedObservations))
+	idx := slices.IndexFunc(g.poam.PoamItems, func(item typepoam.PoamItem) bool { return propsKeyOf(item.Props, poamItemKeyPropNames) == key })
+	if idx < 0 {
+		g.poam.PoamItems = append(g.poam.PoamItems, typepoam.PoamItem{
+			UUID:                g.stamper.UUID("poam-item", key),
+			Title:               fmt.Sprintf("Failing %s", controlId),
+			Description:         description,
+			Props:               itemProps,
+			RelatedObservations: relatedObservations,
+			RelatedRisks:        relatedRisks,
+		})
+		return
+	}
+	item := &g.poam.PoamItems[idx]
+	item.Description = description
+	item.RelatedObservations = relatedObservations
+	item.RelatedRisks = relatedRisks
+}
+
+// upsertRisk adds the risk of the failing observation, or updates the risk having the same key.
+// The remediations, the deadline and the status (unless closed) of the existing risk are kept.
+func (g *poamGenerator) upsertRisk(observation typear.Observation, controlId string) typear.Risk {
+	props := []typecommon.Prop{}
+	for _, name := range riskKeyPropNames {
+		if prop, ok := FindProp(name, observation.Props); ok {
+			props = append(props, prop)
+		}
+	}
+	ruleId := "unknown"
+	if prop, ok := FindProp("assessment-rule-id", props); ok {
+		ruleId = prop.Value
+	}
+	if _, ok := FindProp("control-id", props); !ok {
+		props = append(props, typecommon.Prop{Name: "control-id", Value: controlId})
+	}
+	key := propsKeyOf(props, riskKeyPropNames)
+	if !slices.Contains(g.openRisks, key) {
+		g.openRisks = append(g.openRisks, key)
+	}
+
+	statements := []string{}
+	for _, subject := range observation.Subjects {
+		statement := fmt.Sprintf("- %s", subject.Title)
+		if prop, ok := FindProp("reason", subject.Props); ok {
+			statement = fmt.Sprintf("%s: %s", statement, prop.Value)
+		}
+		statements = append(statements, statement)
+	}
+	statement := fmt.Sprintf("Rule %s fails for %s", ruleId, controlId)
+	if len(statements) > 0 {
+		statement = fmt.Sprintf("%s on the subjects:\n%s", statement, strings.Join(statements, "\n"))
+	}
+	relatedObservations := []typear.RelatedObservation{{ObservationUUID: observation.UUID}}
+
+	idx := slices.IndexFunc(g.poam.Risks, func(risk typear.Risk) bool { return propsKeyOf(risk.Props, riskKeyPropNames) == key })
+	if idx < 0 {
+		risk := typear.Risk{
+			UUID:        g.stamper.UUID("risk", key),
+			Title:       fmt.Sprintf("Risk of rule %s for %s", ruleId, controlId),
+			Description: fmt.Sprintf("Rule %s implementing %s fails", ruleId, controlId),
+			Statement:   statement,
+			Props:       props,
+			Status:      RiskStatusOpen,
+			Remediations: []typear.Response{{
+				UUID:        g.stamper.UUID("remediation", key),
+				Lifecycle:   "planned",
+				Title:       fmt.Sprintf("Remediation of rule %s", ruleId),
+				Description: "To be planned",
+			}},
+			RelatedObservations: relatedObservations,
+		}
+		g.poam.Risks = append(g.poam.Risks, risk)
+		return risk
+	}
+	risk := &g.poam.Risks[idx]
+	risk.Statement = statement
+	risk.RelatedObservations = relatedObservations
+	if risk.Status == RiskStatusClosed {
+		risk.Status = RiskStatusOpen
+	}
+	return *risk
+}
+
+// closeFixed closes the risks which do not fail anymore and the items whose risks are all closed, and replaces the observations
+// of the POA&M with the failing observations and the observations of the closed risks.
+func (g *poamGenerator) closeFixed() {
+	observations := g.observations
+	for idx := range g.poam.Risks {
+		risk := &g.poam.Risks[idx]
+		if slices.Contains(g.openRisks, propsKeyOf(risk.Props, riskKeyPropNames)) {
+			continue
+		}
+		risk.Status = RiskStatusClosed
+		for _, relatedObservation := range risk.RelatedObservations {
+			if slices.ContainsFunc(observations, func(o typear.Observation) bool { return o.UUID == relatedObservation.ObservationUUID }) {
+				continue
+			}
+			if idx := slices.IndexFunc(g.poam.Observations, func(o typear.Observation) bool { return o.UUID == relatedObservation.ObservationUUID }); idx >= 0 {
+				observations = append(observations, g.poam.Observations[idx])
+			}
+		}
+	}
+	g.poam.Observations = observations
+
+	for idx := range g.poam.PoamItems {
+		item := &g.poam.PoamItems[idx]
+		status := RiskStatusClosed
+		for _, relatedRisk := range item.RelatedRisks {
+			riskIdx := slices.IndexFunc(g.poam.Risks, func(risk typear.Risk) bool { return risk.UUID == relatedRisk.RiskUUID })
+			if riskIdx < 0 || g.poam.Risks[riskIdx].Status != RiskStatusClosed {
+				status = RiskStatusOpen
+				break
+			}
+		}
+		item.Props = slices.DeleteFunc(item.Props, func(prop typecommon.Prop) bool { return prop.Name == poamItemStatusPropName })
+		item.Props = append(item.Props, typecommon.Prop{Name: poamItemStatusPropName, Value: status})
+		if status == RiskStatusClosed {
+			item.Description = fmt.Sprintf("%s is not failing anymore: all risks are closed", controlIdOfItem(item.Props))
+		}
+	}
+}
+
+// controlIdOfItem returns the statement-id or the control-id of the POA&M item by its props
+func controlIdOfItem(props []typecommon.Prop) string {
+	if prop, ok := FindProp("statement-id", props); ok {
+		return prop.Value
+	}
+	prop, _ := FindProp("control-id", props)
+	return prop.Value
+}
+
+// failingObservation returns the observation with the failing subjects only, and whether the observation fails.
+func failingObservation(observation typear.Observation) (typear.Observation, bool) {
+	if !slices.Contains(observationResults(observation), "fail") {
+		return observation, false
+	}
+	subjects := []typear.Subject{}
+	for _, subject := range observation.Subjects {
+		if prop, ok := FindProp("result", subject.Props); !ok || prop.Value == "fail" {
+			subjects = append(subjects, subject)
+		}
+	}
+	observation.Subjects = subjects
+	return observation, true
+}
+
+func propsKeyOf(props []typecommon.Prop, names []string) string {
+	values := []string{}
+	for _, name := range names {
+		prop, _ := FindProp(name, props)
+		values = append(values, prop.Value)
+	}
+	return strings.Join(values, "\x00")
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oscal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/oscal-compass/compliance-to-policy/go/pkg"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal/validation"
+	typear "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/assessmentresults"
+	typecommon "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/common"
+)
+
+func TestGeneratePoam(t *testing.T) {
+	controls := []typear.SelectControlById{{ControlID: "ac-1"}}
+	makeAr := func(observationUUID string, result string, state string) typear.AssessmentResultsRoot {
+		arRoot := makeTestAssessmentResults("ar-"+observationUUID, "inventory-1", observationUUID, result, controls, []typear.Finding{
+			makeTestFinding("ac-1_smt", state, result, observationUUID),
+		})
+		arRoot.AssessmentResults.Results[0].Observations[0].Subjects = append(arRoot.AssessmentResults.Results[0].Observations[0].Subjects, typear.Subject{
+			Title: "Cluster Name: cluster2",
+			Props: []typecommon.Prop{{Name: "result", Value: "pass"}},
+		})
+		return arRoot
+	}
+	stamper := NewStamper(true)
+
+	poamRoot, err := GeneratePoam(makeAr("11111111-1111-4111-8111-111111111111", "fail", FindingStateNotSatisfied), nil, stamper)
+	assert.NoError(t, err, "Should not happen")
+	poam := poamRoot.PlanOfActionAndMilestones
+	assert.Len(t, poam.PoamItems, 1)
+	assert.Len(t, poam.Risks, 1)
+	assert.Len(t, poam.Observations, 1)
+	// Passing subjects are not included
+	assert.Len(t, poam.Observations[0].Subjects, 1)
+	assert.Equal(t, RiskStatusOpen, poam.Risks[0].Status)
+	status, _ := FindProp("status", poam.PoamItems[0].Props)
+	assert.Equal(t, RiskStatusOpen, status.Value)
+	assert.Equal(t, "planned", poam.Risks[0].Remediations[0].Lifecycle)
+	assert.Equal(t, poam.Risks[0].UUID, poam.PoamItems[0].RelatedRisks[0].RiskUUID)
+	itemUUID, riskUUID := poam.PoamItems[0].UUID, poam.Risks[0].UUID
+
+	// Remediations and statuses edited by users are kept while the items are failing
+	poamRoot.PlanOfActionAndMilestones.Risks[0].Status = "remediating"
+	poamRoot.PlanOfActionAndMilestones.Risks[0].Remediations[0].Description = "Deploy the CRDs"
+	poamRoot, err = GeneratePoam(makeAr("22222222-2222-4222-8222-222222222222", "fail", FindingStateNotSatisfied), poamRoot, stamper)
+	assert.NoError(t, err, "Should not happen")
+	poam = poamRoot.PlanOfActionAndMilestones
+	assert.Len(t, poam.PoamItems, 1)
+	assert.Len(t, poam.Risks, 1)
+	assert.Equal(t, itemUUID, poam.PoamItems[0].UUID)
+	assert.Equal(t, riskUUID, poam.Risks[0].UUID)
+	assert.Equal(t, "remediating", poam.Risks[0].Status)
+	assert.Equal(t, "Deploy the CRDs", poam.Risks[0].Remediations[0].Description)
+	assert.Equal(t, "22222222-2222-4222-8222-222222222222", poam.Observations[0].UUID)
+
+	// Fixed risks are closed, and their observations are kept
+	poamRoot, err = GeneratePoam(makeAr("33333333-3333-4333-8333-333333333333", "pass", FindingStateSatisfied), poamRoot, stamper)
+	assert.NoError(t, err, "Should not happen")
+	poam = poamRoot.PlanOfActionAndMilestones
+	assert.Len(t, poam.PoamItems, 1)
+	assert.Equal(t, RiskStatusClosed, poam.Risks[0].Status)
+	assert.Equal(t, "22222222-2222-4222-8222-222222222222", poam.Observations[0].UUID)
+
+	// Closed risks are reopened if they fail again
+	poamRoot, err = GeneratePoam(makeAr("44444444-4444-4444-8444-444444444444", "fail", FindingStateNotSatisfied), poamRoot, stamper)
+	assert.NoError(t, err, "Should not happen")
+	poam = poamRoot.PlanOfActionAndMilestones
+	assert.Len(t, poam.Risks, 1)
+	assert.Equal(t, RiskStatusOpen, poam.Risks[0].Status)
+	assert.Len(t, poam.Observations, 1)
+	status, _ = FindProp("status", poam.PoamItems[0].Props)
+	assert.Equal(t, RiskStatusOpen, status.Value)
+}
+
+func TestGeneratePoamWithFixedControl(t *testing.T) {
+	controls := []typear.SelectControlById{{ControlID: "ac-1"}, {ControlID: "cm-6"}}
+	makeAr := func(uuid string, ac1 string, cm6 string) typear.AssessmentResultsRoot {
+		state := func(result string) string {
+			if result == "fail" {
+				return FindingStateNotSatisfied
+			}
+			return FindingStateSatisfied
+		}
+		arRoot := makeTestAssessmentResults("ar-"+uuid, "inventory-1", "ac-1-"+uuid, ac1, controls, []typear.Finding{
+			makeTestFinding("ac-1_smt", state(ac1), ac1, "ac-1-"+uuid),
+			makeTestFinding("cm-6_smt", state(cm6), cm6, "cm-6-"+uuid),
+		})
+		result := &arRoot.AssessmentResults.Results[0]
+		result.Observations = append(result.Observations, typear.Observation{
+			UUID:     "cm-6-" + uuid,
+			Props:    []typecommon.Prop{{Name: "assessment-rule-id", Value: "rule_b"}},
+			Subjects: []typear.Subject{{Title: "Cluster Name: cluster1", Props: []typecommon.Prop{{Name: "result", Value: cm6}}}},
+		})
+		return arRoot
+	}
+	stamper := NewStamper(true)
+
+	poamRoot, err := GeneratePoam(makeAr("1", "fail", "fail"), nil, stamper)
+	assert.NoError(t, err, "Should not happen")
+	assert.Len(t, poamRoot.PlanOfActionAndMilestones.PoamItems, 2)
+
+	// ac-1 passes by the second assessment results
+	poamRoot, err = GeneratePoam(makeAr("2", "pass", "fail"), poamRoot, stamper)
+	assert.NoError(t, err, "Should not happen")
+	poam := poamRoot.PlanOfActionAndMilestones
+	assert.Len(t, poam.PoamItems, 2)
+	statuses := map[string]string{}
+	for _, item := range poam.PoamItems {
+		control, _ := FindProp("control-id", item.Props)
+		status, _ := FindProp("status", item.Props)
+		statuses[control.Value] = status.Value
+	}
+	assert.Equal(t, map[string]string{"ac-1": RiskStatusClosed, "cm-6": RiskStatusOpen}, statuses)
+	assert.Equal(t, "ac-1 is not failing anymore: all risks are closed", poam.PoamItems[0].Description)
+	assert.Equal(t, "cm-6 is not satisfied: 1 observation(s) fail", poam.PoamItems[1].Description)
+	risks := map[string]string{}
+	for _, risk := range poam.Risks {
+		rule, _ := FindProp("assessment-rule-id", risk.Props)
+		risks[rule.Value] = risk.Status
+	}
+	assert.Equal(t, map[string]string{"rule_a": RiskStatusClosed, "rule_b": RiskStatusOpen}, risks)
+}
+
+func TestGeneratePoamFromAssessmentResults(t *testing.T) {
+	var arRoot typear.AssessmentResultsRoot
+	err := pkg.LoadOscalFileToObject(pkg.PathFromPkgDirectory("./testdata/ocm/assessment-results.json"), &arRoot)
+	assert.NoError(t, err, "Should not happen")
+
+	poamRoot, err := GeneratePoam(arRoot, nil, NewStamper(true))
+	assert.NoError(t, err, "Should not happen")
+	if validation.HasSchema("plan-of-action-and-milestones") {
+		assert.NoError(t, validation.ValidateObject(poamRoot))
+	}
+	poam := poamRoot.PlanOfActionAndMilestones
+	assert.Len(t, poam.PoamItems, 2)
+	assert.Len(t, poam.Risks, 2)
+	for _, item := range poam.PoamItems {
+		assert.NotEmpty(t, item.RelatedRisks)
+	}
+}
@@ -0,0 +1,689 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oscal
+
+import (
+	"fmt"
+	neturl "net/url"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/oscal-compass/compliance-to-policy/go/pkg"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal"
+	typecommon "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/common"
+	cd "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/componentdefinition"
+)
+
+var logger *zap.Logger = pkg.GetLogger("oscal")
+
+const (
+	combineUseFirst = "use-first"
+	combineMerge    = "merge"
+	combineKeep     = "keep"
+)
+
+// ProfileResolver resolves an OSCAL profile into a catalog containing only the selected
+// and modified controls, following the chain of imported profiles and catalogs.
+type ProfileResolver struct {
+	gitUtils pkg.GitUtils
+}
+
+// importedDocument is either a catalog or a profile referenced from a profile import.
+type importedDocument struct {
+	Catalog *oscal.Catalog `json:"catalog,omitempty"`
+	Profile *oscal.Profile `json:"profile,omitempty"`
+}
+
+func NewProfileResolver(gitUtils pkg.GitUtils) *ProfileResolver {
+	return &ProfileResolver{
+		gitUtils: gitUtils,
+	}
+}
+
+// Resolve loads the profile from href and resolves it.
+func (r *ProfileResolver) Resolve(href string) (*oscal.Catalog, error) {
+	return r.load(href, []string{})
+}
+
+// ResolveProfile resolves an already loaded profile. Relative import hrefs are resolved against href.
+func (r *ProfileResolver) ResolveProfile(profile oscal.Profile, href string) (*oscal.Catalog, error) {
+	return r.resolveProfile(profile, href, []string{href})
+}
+
+// IntersectWithCD resolves the profile loaded from href, and returns the resolved profile and the component-definition
+// intersected with the controls of the resolved profile.
+func (r *ProfileResolver) IntersectWithCD(profile oscal.Profile, href string, compDef cd.ComponentDefinition) (*oscal.Catalog, cd.ComponentDefinition, error) {
+	resolved, err := r.ResolveProfile(profile, href)
+	if err != nil {
+		return nil, compDef, err
+	}
+	return resolved, IntersectResolvedProfileWithCD(compDef, *resolved), nil
+}
+
+func (r *ProfileResolver) load(href string, chain []string) (*oscal.Catalog, error) {
+	for _, visited := range chain {
+		if visited == href {
+			return nil, fmt.Errorf("circular import of profile: %s", strings.Join(append(chain, href), " -> "))
+		}
+	}
+	var doc importedDocument
+	if err := r.gitUtils.LoadFromWeb(href, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Catalog != nil {
+		return doc.Catalog, nil
+	}
+	if doc.Profile != nil {
+		nextChain := append(append([]string{}, chain...), href)
+		return r.resolveProfile(*doc.Profile, href, nextChain)
+	}
+	return nil, fmt.Errorf("%s is neither OSCAL catalog nor OSCAL profile", href)
+}
+
+func (r *ProfileResolver) resolveProfile(profile oscal.Profile, href string, chain []string) (*oscal.Catalog, error) {
+	selections := []oscal.Catalog{}
+	backMatter := oscal.BackMatter{}
+	for _, profileImport := range profile.Imports {
+		importHref, err := resolveImportHref(href, profileImport.Href, profile.BackMatter)
+		if err != nil {
+			return nil, err
+		}
+		imported, err := r.load(importHref, chain)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import %s from %s: %w", profileImport.Href, href, err)
+		}
+		selections = append(selections, selectControls(*imported, profileImport))
+		backMatter.Resources = append(backMatter.Resources, imported.BackMatter.Resources...)
+	}
+	resolved := mergeSelections(profile.Merge, selections)
+	if profile.Modify != nil {
+		setParameters(&resolved, profile.Modify.SetParameters)
+		for _, alter := range profile.Modify.Alters {
+			alterControl(&resolved, alter)
+		}
+	}
+	if profile.BackMatter != nil {
+		backMatter.Resources = append(backMatter.Resources, profile.BackMatter.Resources...)
+	}
+	resolved.UUID = GenerateUUID()
+	resolved.Metadata = oscal.CatalogMetadata{
+		Title:              profile.Metadata.Title,
+		LastModified:       profile.Metadata.LastModified,
+		Version:            profile.Metadata.Version,
+		OscalVersion:       profile.Metadata.OscalVersion,
+		Roles:              profile.Metadata.Roles,
+		Parties:            profile.Metadata.Parties,
+		ResponsibleParties: profile.Metadata.ResponsibleParties,
+	}
+	resolved.BackMatter = backMatter
+	return &resolved, nil
+}
+
+func resolveImportHref(baseHref string, href string, backMatter *oscal.BackMatter) (string, error) {
+	if strings.HasPrefix(href, "#") {
+		if backMatter == nil {
+			return "", fmt.Errorf("%s refers to back-matter but the profile has no back-matter", href)
+		}
+		resourceHref, ok := findResourceHref(*backMatter, strings.TrimPrefix(href, "#"))
+		if !ok {
+			return "", fmt.Errorf("resource %s is not found in back-matter", href)
+		}
+		href = resourceHref
+	}
+	u, err := neturl.Parse(href)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme != "" || filepath.IsAbs(href) {
+		return href, nil
+	}
+	base, err := neturl.Parse(baseHref)
+	if err != nil {
+		return "", err
+	}
+	switch base.Scheme {
+	case "":
+		return filepath.Join(filepath.Dir(baseHref), href), nil
+	case "local":
+		return path.Join(path.Dir(toLocalPath(base)), href), nil
+	default:
+		return base.ResolveReference(u).String(), nil
+	}
+}
+
+func toLocalPath(u *neturl.URL) string {
+	return u.Host + u.Path
+}
+
+func findResourceHref(backMatter oscal.BackMatter, uuid string) (string, bool) {
+	for _, resource := range backMatter.Resources {
+		if resource.UUID != uuid || len(resource.Rlinks) == 0 {
+			continue
+		}
+		for _, rlink := range resource.Rlinks {
+			if strings.HasSuffix(rlink.Href, ".json") {
+				return rlink.Href, true
+			}
+		}
+		return resource.Rlinks[0].Href, true
+	}
+	return "", false
+}
+
+// selectControls returns a copy of the catalog pruned down to the controls selected by the import.
+// A selected child control whose parent is not selected takes the place of the parent.
+func selectControls(catalog oscal.Catalog, profileImport oscal.ProfileImport) oscal.Catalog {
+	selected := map[string]bool{}
+	walkControls(catalog, func(control oscal.Control) {
+		if profileImport.IncludeAll != nil {
+			selected[control.ID] = true
+			return
+		}
+		for _, selection := range profileImport.IncludeControls {
+			if matchesSelection(selection, control.ID) {
+				markControl(selected, control, selection.WithChildControls == "yes", true)
+			}
+		}
+	})
+	walkControls(catalog, func(control oscal.Control) {
+		for _, selection := range profileImport.ExcludeControls {
+			if matchesSelection(selection, control.ID) {
+				markControl(selected, control, selection.WithChildControls == "yes", false)
+			}
+		}
+	})
+	pruned := catalog
+	pruned.Controls = pruneControls(catalog.Controls, selected)
+	pruned.Groups = pruneGroups(catalog.Groups, selected)
+	return pruned
+}
+
+func markControl(selected map[string]bool, control oscal.Control, withChildControls bool, value bool) {
+	selected[control.ID] = value
+	if withChildControls {
+		for _, child := range control.Controls {
+			markControl(selected, child, withChildControls, value)
+		}
+	}
+}
+
+func matchesSelection(selection oscal.ProfileSelectControlById, controlId string) bool {
+	for _, id := range selection.WithIds {
+		if id == controlId {
+			return true
+		}
+	}
+	for _, matching := range selection.Matching {
+		if matched, err := path.Match(matching.Pattern, controlId); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+func pruneControls(controls []oscal.Control, selected map[string]bool) []oscal.Control {
+	pruned := []oscal.Control{}
+	for _, control := range controls {
+		children := pruneControls(control.Controls, selected)
+		if selected[control.ID] {
+			control.Controls = children
+			pruned = append(pruned, control)
+		} else {
+			pruned = append(pruned, children...)
+		}
+	}
+	return pruned
+}
+
+func pruneGroups(groups []oscal.Group, selected map[string]bool) []oscal.Group {
+	pruned := []oscal.Group{}
+	for _, group := range groups {
+		group.Controls = pruneControls(group.Controls, selected)
+		group.Groups = pruneGroups(group.Groups, selected)
+		if len(group.Controls) > 0 || len(group.Groups) > 0 {
+			pruned = append(pruned, group)
+		}
+	}
+	return pruned
+}
+
+// controlMerger combines the controls of several selections, handling duplicate control IDs
+// according to the combine method of the profile.
+type controlMerger struct {
+	method  string
+	seen    map[string]bool
+	content map[string]oscal.Control
+}
+
+func newControlMerger(merge oscal.ProfileMerge, selections []oscal.Catalog) *controlMerger {
+	method := combineUseFirst
+	if merge.Combine != nil && merge.Combine.Method != "" {
+		method = merge.Combine.Method
+	}
+	m := &controlMerger{
+		method:  method,
+		seen:    map[string]bool{},
+		content: map[string]oscal.Control{},
+	}
+	for _, selection := range selections {
+		walkControls(selection, func(control oscal.Control) {
+			existing, ok := m.content[control.ID]
+			if !ok {
+				m.content[control.ID] = control
+			} else if method == combineMerge {
+				m.content[control.ID] = mergeControl(existing, control)
+			}
+		})
+	}
+	return m
+}
+
+func (m *controlMerger) controls(controls []oscal.Control) []oscal.Control {
+	merged := []oscal.Control{}
+	for _, control := range controls {
+		children := m.controls(control.Controls)
+		if m.method != combineKeep {
+			if m.seen[control.ID] {
+				merged = append(merged, children...)
+				continue
+			}
+			m.seen[control.ID] = true
+			control = m.content[control.ID]
+		}
+		control.Controls = children
+		merged = append(merged, control)
+	}
+	return merged
+}
+
+func (m *controlMerger) groups(merged []oscal.Group, groups []oscal.Group) []oscal.Group {
+	for _, group := range groups {
+		controls := m.controls(group.Controls)
+		idx := findGroupIndex(merged, group.ID)
+		if idx < 0 {
+			subGroups := group.Groups
+			group.Controls = controls
+			group.Groups = m.groups([]oscal.Group{}, subGroups)
+			merged = append(merged, group)
+		} else {
+			merged[idx].Controls = append(merged[idx].Controls, controls...)
+			merged[idx].Groups = m.groups(merged[idx].Groups, group.Groups)
+		}
+	}
+	return merged
+}
+
+func findGroupIndex(groups []oscal.Group, id string) int {
+	if id == "" {
+		return -1
+	}
+	for idx, group := range groups {
+		if group.ID == id {
+			return idx
+		}
+	}
+	return -1
+}
+
+// mergeSelections combines selected controls. Group structure is kept with "as-is" and
+// the controls are flattened otherwise.
+func mergeSelections(merge oscal.ProfileMerge, selections []oscal.Catalog) oscal.Catalog {
+	merger := newControlMerger(merge, selections)
+	resolved := oscal.Catalog{
+		Params:   []oscal.Parameter{},
+		Controls: []oscal.Control{},
+		Groups:   []oscal.Group{},
+	}
+	for _, selection := range selections {
+		resolved.Params = append(resolved.Params, selection.Params...)
+		if merge.AsIs {
+			resolved.Controls = append(resolved.Controls, merger.controls(selection.Controls)...)
+			resolved.Groups = merger.groups(resolved.Groups, selection.Groups)
+		} else {
+			walkControls(selection, func(control oscal.Control) {
+				control.Controls = nil
+				resolved.Controls = append(resolved.Controls, merger.controls([]oscal.Control{control})...)
+			})
+			walkGroups(selection.Groups, func(group oscal.Group) {
+				resolved.Params = append(resolved.Params, group.Params...)
+			})
+		}
+	}
+	return resolved
+}
+
+func mergeControl(control oscal.Control, other oscal.Control) oscal.Control {
+	if control.Title == "" {
+		control.Title = other.Title
+	}
+	for _, param := range other.Params {
+		if _, found := findParam(control.Params, param.ID); !found {
+			control.Params = append(control.Params, param)
+		}
+	}
+	for _, prop := range other.Props {
+		if !containsProp(control.Props, prop) {
+			control.Props = append(control.Props, prop)
+		}
+	}
+	for _, link := range other.Links {
+		if !containsLink(control.Links, link) {
+			control.Links = append(control.Links, link)
+		}
+	}
+	for _, part := range other.Parts {
+		if !containsPart(control.Parts, part) {
+			control.Parts = append(control.Parts, part)
+		}
+	}
+	return control
+}
+
+func findParam(params []oscal.Parameter, id string) (oscal.Parameter, bool) {
+	for _, param := range params {
+		if param.ID == id {
+			return param, true
+		}
+	}
+	return oscal.Parameter{}, false
+}
+
+func containsProp(props []typecommon.Prop, prop typecommon.Prop) bool {
+	for _, p := range props {
+		if p.Name == prop.Name && p.Ns == prop.Ns && p.Class == prop.Class && p.Value == prop.Value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsLink(links []typecommon.Link, link typecommon.Link) bool {
+	for _, l := range links {
+		if l.Href == link.Href && l.Rel == link.Rel {
+			return true
+		}
+	}
+	return false
+}
+
+func containsPart(parts []oscal.Part, part oscal.Part) bool {
+	for _, p := range parts {
+		if part.ID != "" && p.ID == part.ID {
+			return true
+		}
+		if part.ID == "" && p.ID == "" && p.Name == part.Name {
+			return true
+		}
+	}
+	return false
+}
+
+func setParameters(catalog *oscal.Catalog, setParams []oscal.ProfileSetParameter) {
+	if len(setParams) == 0 {
+		return
+	}
+	setParamMap := map[string]oscal.ProfileSetParameter{}
+	for _, setParam := range setParams {
+		setParamMap[setParam.ParamID] = setParam
+	}
+	applied := map[string]bool{}
+	apply := func(params []oscal.Parameter) {
+		for idx := range params {
+			setParam, ok := setParamMap[params[idx].ID]
+			if ok {
+				applySetParameter(&params[idx], setParam)
+				applied[setParam.ParamID] = true
+			}
+		}
+	}
+	apply(catalog.Params)
+	walkControlRefs(catalog, func(control *oscal.Control) {
+		apply(control.Params)
+	})
+	walkGroupRefs(catalog.Groups, func(group *oscal.Group) {
+		apply(group.Params)
+	})
+	for _, setParam := range setParams {
+		if !applied[setParam.ParamID] {
+			logger.Warn(fmt.Sprintf("Parameter %s in set-parameters is not found in the resolved profile", setParam.ParamID))
+		}
+	}
+}
+
+func applySetParameter(param *oscal.Parameter, setParam oscal.ProfileSetParameter) {
+	if setParam.Class != "" {
+		param.Class = setParam.Class
+	}
+	if setParam.DependsOn != "" {
+		param.DependsOn = setParam.DependsOn
+	}
+	if setParam.Label != "" {
+		param.Label = setParam.Label
+	}
+	if setParam.Usage != "" {
+		param.Usage = setParam.Usage
+	}
+	if len(setParam.Guidelines) > 0 {
+		param.Guidelines = setParam.Guidelines
+	}
+	if len(setParam.Values) > 0 {
+		param.Values = setParam.Values
+	}
+	if setParam.Select != nil {
+		param.Select = setParam.Select
+	}
+	param.Props = append(param.Props, setParam.Props...)
+	param.Links = append(param.Links, setParam.Links...)
+}
+
+func alterControl(catalog *oscal.Catalog, alter oscal.ProfileAlter) {
+	var target *oscal.Control
+	walkControlRefs(catalog, func(control *oscal.Control) {
+		if target == nil && control.ID == alter.ControlID {
+			target = control
+		}
+	})
+	if target == nil {
+		logger.Warn(fmt.Sprintf("Control %s in alters is not found in the resolved profile", alter.ControlID))
+		return
+	}
+	for _, remove := range alter.Removes {
+		target.Params = filterSlice(target.Params, func(param oscal.Parameter) bool {
+			return !matchesRemove(remove, "param", "", param.Class, param.ID, "")
+		})
+		target.Props = filterSlice(target.Props, func(prop typecommon.Prop) bool {
+			return !matchesRemove(remove, "prop", prop.Name, prop.Class, "", prop.Ns)
+		})
+		target.Links = filterSlice(target.Links, func(link typecommon.Link) bool {
+			return !matchesRemove(remove, "link", link.Rel, "", "", "")
+		})
+		target.Parts = removeParts(target.Parts, remove)
+	}
+	for _, add := range alter.Adds {
+		addToControl(target, add)
+	}
+}
+
+func matchesRemove(remove oscal.ProfileRemove, itemName string, name string, class string, id string, ns string) bool {
+	if remove.ByItemName != "" && remove.ByItemName != itemName {
+		return false
+	}
+	if remove.ByName != "" && remove.ByName != name {
+		return false
+	}
+	if remove.ByClass != "" && remove.ByClass != class {
+		return false
+	}
+	if remove.ById != "" && remove.ById != id {
+		return false
+	}
+	if remove.ByNs != "" && remove.ByNs != ns {
+		return false
+	}
+	return remove.ByItemName != "" || remove.ByName != "" || remove.ByClass != "" || remove.ById != "" || remove.ByNs != ""
+}
+
+func removeParts(parts []oscal.Part, remove oscal.ProfileRemove) []oscal.Part {
+	kept := []oscal.Part{}
+	for _, part := range parts {
+		if matchesRemove(remove, "part", part.Name, part.Class, part.ID, part.Ns) {
+			continue
+		}
+		part.Props = filterSlice(part.Props, func(prop typecommon.Prop) bool {
+			return !matchesRemove(remove, "prop", prop.Name, prop.Class, "", prop.Ns)
+		})
+		part.Parts = removeParts(part.Parts, remove)
+		kept = append(kept, part)
+	}
+	return kept
+}
+
+func addToControl(control *oscal.Control, add oscal.ProfileAdd) {
+	position := add.Position
+	if position == "" {
+		position = "ending"
+	}
+	if add.ById == "" || add.ById == control.ID {
+		if add.Title != "" {
+			control.Title = add.Title
+		}
+		if position == "starting" || position == "before" {
+			control.Params = append(append([]oscal.Parameter{}, add.Params...), control.Params...)
+			control.Props = append(append([]typecommon.Prop{}, add.Props...), control.Props...)
+			control.Links = append(append([]typecommon.Link{}, add.Links...), control.Links...)
+			control.Parts = append(append([]oscal.Part{}, add.Parts...), control.Parts...)
+		} else {
+			control.Params = append(control.Params, add.Params...)
+			control.Props = append(control.Props, add.Props...)
+			control.Links = append(control.Links, add.Links...)
+			control.Parts = append(control.Parts, add.Parts...)
+		}
+		return
+	}
+	var added bool
+	control.Parts, added = addToParts(control.Parts, add, position)
+	if !added {
+		logger.Warn(fmt.Sprintf("Part %s in alters is not found in control %s", add.ById, control.ID))
+	}
+}
+
+func addToParts(parts []oscal.Part, add oscal.ProfileAdd, position string) ([]oscal.Part, bool) {
+	for idx := range parts {
+		if parts[idx].ID == add.ById {
+			switch position {
+			case "before":
+				result := append(append([]oscal.Part{}, parts[:idx]...), add.Parts...)
+				return append(result, parts[idx:]...), true
+			case "after":
+				result := append(append([]oscal.Part{}, parts[:idx+1]...), add.Parts...)
+				return append(result, parts[idx+1:]...), true
+			case "starting":
+				parts[idx].Props = append(append([]typecommon.Prop{}, add.Props...), parts[idx].Props...)
+				parts[idx].Parts = append(append([]oscal.Part{}, add.Parts...), parts[idx].Parts...)
+			default:
+				parts[idx].Props = append(parts[idx].Props, add.Props...)
+				parts[idx].Parts = append(parts[idx].Parts, add.Parts...)
+			}
+			return parts, true
+		}
+		var added bool
+		if parts[idx].Parts, added = addToParts(parts[idx].Parts, add, position); added {
+			return parts, true
+		}
+	}
+	return parts, false
+}
+
+func filterSlice[T any](items []T, keep func(T) bool) []T {
+	if items == nil {
+		return nil
+	}
+	filtered := []T{}
+	for _, item := range items {
+		if keep(item) {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered
+}
+
+func walkControls(catalog oscal.Catalog, callback func(control oscal.Control)) {
+	var walk func(controls []oscal.Control)
+	walk = func(controls []oscal.Control) {
+		for _, control := range controls {
+			callback(control)
+			walk(control.Controls)
+		}
+	}
+	walk(catalog.Controls)
+	walkGroups(catalog.Groups, func(group oscal.Group) {
+		walk(group.Controls)
+	})
+}
+
+func walkGroups(groups []oscal.Group, callback func(group oscal.Group)) {
+	for _, group := range groups {
+		callback(group)
+		walkGroups(group.Groups, callback)
+	}
+}
+
+func walkControlRefs(catalog *oscal.Catalog, callback func(control *oscal.Control)) {
+	var walk func(controls []oscal.Control)
+	walk = func(controls []oscal.Control) {
+		for idx := range controls {
+			callback(&controls[idx])
+			walk(controls[idx].Controls)
+		}
+	}
+	walk(catalog.Controls)
+	walkGroupRefs(catalog.Groups, func(group *oscal.Group) {
+		walk(group.Controls)
+	})
+}
+
+func walkGroupRefs(groups []oscal.Group, callback func(group *oscal.Group)) {
+	for idx := range groups {
+		callback(&groups[idx])
+		walkGroupRefs(groups[idx].Groups, callback)
+	}
+}
+
+// ListControlIds returns the IDs of all the controls in the catalog including control enhancements.
+func ListControlIds(catalog oscal.Catalog) []string {
+	controlIds := []string{}
+	walkControls(catalog, func(control oscal.Control) {
+		controlIds = append(controlIds, control.ID)
+	})
+	return controlIds
+}
+
+func FindControl(catalog oscal.Catalog, controlId string) (oscal.Control, bool) {
+	var found *oscal.Control
+	walkControls(catalog, func(control oscal.Control) {
+		if found == nil && control.ID == controlId {
+			found = &control
+		}
+	})
+	if found == nil {
+		return oscal.Control{}, false
+	}
+	return *found, true
+}
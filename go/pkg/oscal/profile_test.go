@@ -0,0 +1,128 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oscal
+
+import (
+	"testing"
+
+	"github.com/oscal-compass/compliance-to-policy/go/pkg"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal"
+	cd "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/componentdefinition"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestProfileResolver() *ProfileResolver {
+	tempDir := pkg.NewTempDirectory(pkg.PathFromPkgDirectory("./oscal/_test"))
+	return NewProfileResolver(pkg.NewGitUtils(tempDir))
+}
+
+func TestResolveProfileChain(t *testing.T) {
+	resolver := newTestProfileResolver()
+	resolved, err := resolver.Resolve(pkg.PathFromPkgDirectory("./oscal/testdata/profile-resolution/profile.json"))
+	assert.NoError(t, err, "Should not happen")
+
+	assert.Equal(t, "Tailored Profile", resolved.Metadata.Title)
+	assert.Equal(t, []string{"ac-2", "ac-2.1", "cm-6"}, ListControlIds(*resolved))
+	assert.Equal(t, []string{"ac", "cm"}, []string{resolved.Groups[0].ID, resolved.Groups[1].ID})
+
+	ac2, found := FindControl(*resolved, "ac-2")
+	assert.True(t, found)
+	assert.Equal(t, []string{"30 days"}, ac2.Params[0].Values)
+	assert.Equal(t, []string{"ac-2_smt", "ac-2_smt.tailored", "ac-2_gdn"}, []string{ac2.Parts[0].ID, ac2.Parts[1].ID, ac2.Parts[2].ID})
+
+	cm6, found := FindControl(*resolved, "cm-6")
+	assert.True(t, found)
+	assert.Len(t, cm6.Props, 2)
+	assert.Equal(t, "label", cm6.Props[0].Name)
+	assert.Equal(t, "tailored", cm6.Props[1].Value)
+}
+
+func TestResolveProfileFlat(t *testing.T) {
+	resolver := newTestProfileResolver()
+	resolved, err := resolver.Resolve(pkg.PathFromPkgDirectory("./oscal/testdata/profile-resolution/flat-profile.json"))
+	assert.NoError(t, err, "Should not happen")
+
+	assert.Empty(t, resolved.Groups)
+	assert.Equal(t, []string{"ac-2", "ac-2.1", "cm-6"}, ListControlIds(*resolved))
+}
+
+func TestResolveProfileCircularImport(t *testing.T) {
+	resolver := newTestProfileResolver()
+	_, err := resolver.Resolve(pkg.PathFromPkgDirectory("./oscal/testdata/profile-resolution/circular-profile.json"))
+	assert.ErrorContains(t, err, "circular import")
+}
+
+func TestIntersectResolvedProfileWithCD(t *testing.T) {
+	resolver := newTestProfileResolver()
+	resolved, err := resolver.Resolve(pkg.PathFromPkgDirectory("./oscal/testdata/profile-resolution/profile.json"))
+	assert.NoError(t, err, "Should not happen")
+
+	compDef := cd.ComponentDefinition{
+		Components: []cd.Component{{
+			Title: "Component",
+			ControlImplementations: []cd.ControlImplementation{{
+				Source: "source",
+				ImplementedRequirements: []cd.ImplementedRequirement{
+					{ControlID: "ac-1"}, {ControlID: "ac-2.1"}, {ControlID: "cm-2"}, {ControlID: "cm-6"},
+				},
+			}},
+		}},
+	}
+	intersected := IntersectResolvedProfileWithCD(compDef, *resolved)
+	controlIds := []string{}
+	for _, implReq := range intersected.Components[0].ControlImplementations[0].ImplementedRequirements {
+		controlIds = append(controlIds, implReq.ControlID)
+	}
+	assert.Equal(t, []string{"ac-2.1", "cm-6"}, controlIds)
+	assert.Equal(t, "source", intersected.Components[0].ControlImplementations[0].Source)
+
+	// The loaded profile is resolved and intersected with the component-definition at once
+	var profileRoot oscal.ProfileRoot
+	href := pkg.PathFromPkgDirectory("./oscal/testdata/profile-resolution/profile.json")
+	err = pkg.LoadJsonFileToObject(href, &profileRoot)
+	assert.NoError(t, err, "Should not happen")
+	resolved, intersected, err = resolver.IntersectWithCD(profileRoot.Profile, href, compDef)
+	assert.NoError(t, err, "Should not happen")
+	assert.Equal(t, []string{"ac-2", "ac-2.1", "cm-6"}, ListControlIds(*resolved))
+	assert.Len(t, intersected.Components[0].ControlImplementations[0].ImplementedRequirements, 2)
+}
+
+func TestFindControlId(t *testing.T) {
+	profile := oscal.Profile{
+		Imports: []oscal.ProfileImport{{
+			IncludeAll: &oscal.IncludeAll{},
+			ExcludeControls: []oscal.ProfileSelectControlById{{
+				Matching: []oscal.ProfileMatching{{Pattern: "ac-2*"}},
+			}},
+		}},
+	}
+	assert.True(t, findControlId(profile, "ac-1"))
+	assert.False(t, findControlId(profile, "ac-2"))
+	assert.False(t, findControlId(profile, "ac-2.1"))
+}
+
+func TestResolveProfileInYamlImportingXmlCatalog(t *testing.T) {
+	resolver := newTestProfileResolver()
+	resolved, err := resolver.Resolve(pkg.PathFromPkgDirectory("./oscal/format/testdata/profile.yaml"))
+	assert.NoError(t, err, "Should not happen")
+
+	assert.Equal(t, "Test Profile in YAML", resolved.Metadata.Title)
+	assert.Equal(t, []string{"ac-1", "ac-2.1", "cm-6"}, ListControlIds(*resolved))
+	ac1, found := FindControl(*resolved, "ac-1")
+	assert.True(t, found)
+	assert.Equal(t, []string{"security officers"}, ac1.Params[0].Values)
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oscal
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+)
+
+// Enforcement modes given by Enforcement_Mode
+var enforcementModes = []string{"audit", "enforce"}
+
+// RefersRule returns true if the control implementation refers to the rule by the rule IDs of the controls.
+func (cio ControlImpleObject) RefersRule(ruleId string) bool {
+	for _, co := range cio.ControlObjects {
+		if slices.Contains(co.RuleIds, ruleId) {
+			return true
+		}
+	}
+	return false
+}
+
+// Merge merges the scope and the other scope into a scope covering the namespaces of both. Enforce takes precedence over audit.
+// The target namespaces are merged unless either scope has no target namespaces (all namespaces), and only the namespaces
+// excluded by both scopes are excluded.
+func (s PolicyScope) Merge(other PolicyScope) PolicyScope {
+	merged := PolicyScope{EnforcementMode: s.EnforcementMode}
+	if other.EnforcementMode == "enforce" || merged.EnforcementMode == "" {
+		merged.EnforcementMode = other.EnforcementMode
+	}
+	if len(s.TargetNamespaces) > 0 && len(other.TargetNamespaces) > 0 {
+		merged.TargetNamespaces = appendUnique(slices.Clone(s.TargetNamespaces), other.TargetNamespaces...)
+	}
+	for _, namespace := range s.ExcludedNamespaces {
+		if slices.Contains(other.ExcludedNamespaces, namespace) {
+			merged.ExcludedNamespaces = append(merged.ExcludedNamespaces, namespace)
+		}
+	}
+	return merged
+}
+
+// ScopeOfRule returns the scope of the policies of the rule. The props of the rule take precedence over those of the control implementations
+// referring to the rule. The scopes of the control implementations are merged by PolicyScope.Merge.
+func ScopeOfRule(componentObject ComponentObject, ruleObject RuleObject) (PolicyScope, error) {
+	scope, found := PolicyScope{}, false
+	for _, cio := range componentObject.ControlImpleObjects {
+		if !cio.RefersRule(ruleObject.RuleId) {
+			continue
+		}
+		if found {
+			scope = scope.Merge(cio.Scope)
+		} else {
+			scope, found = cio.Scope, true
+		}
+	}
+	if ruleObject.Scope.EnforcementMode != "" {
+		scope.EnforcementMode = ruleObject.Scope.EnforcementMode
+	}
+	if len(ruleObject.Scope.TargetNamespaces) > 0 {
+		scope.TargetNamespaces = ruleObject.Scope.TargetNamespaces
+	}
+	if len(ruleObject.Scope.ExcludedNamespaces) > 0 {
+		scope.ExcludedNamespaces = ruleObject.Scope.ExcludedNamespaces
+	}
+	if scope.EnforcementMode != "" && !slices.Contains(enforcementModes, scope.EnforcementMode) {
+		return scope, fmt.Errorf("invalid Enforcement_Mode '%s' of rule %s: must be audit or enforce", scope.EnforcementMode, ruleObject.RuleId)
+	}
+	return scope, nil
+}
+
+// PolicyObject is a policy configured by all the rules referring to the policy
+type PolicyObject struct {
+	PolicyId string
+	// Rules of the target components referring to the policy
+	RuleObjects []RuleObject
+	// Values of the parameters of the rules
+	Parameters map[string][]string
+	// Scope merged from the scopes of the rules by PolicyScope.Merge
+	Scope PolicyScope
+}
+
+// PolicyObjects returns the policies (Policy_Id, Check_Id or Rule_Id of the rules) referred to by the rules of the components in the order of the rules.
+// The rules of the validation components are ignored since they only map checks to the rules of the target components.
+// It fails if the rules referring to a policy set a parameter to different values.
+func PolicyObjects(componentObjects []ComponentObject) ([]PolicyObject, error) {
+	policyObjects := []PolicyObject{}
+	indices := map[string]int{}
+	for _, componentObject := range componentObjects {
+		if componentObject.ComponentType == "validation" {
+			continue
+		}
+		for _, ruleObject := range componentObject.RuleObjects {
+			scope, err := ScopeOfRule(componentObject, ruleObject)
+			if err != nil {
+				return nil, err
+			}
+			parameters, err := CollectParameters(componentObject, ruleObject)
+			if err != nil {
+				return nil, err
+			}
+			for _, policyId := range ruleObject.PolicyIds(true) {
+				index, found := indices[policyId]
+				if !found {
+					indices[policyId] = len(policyObjects)
+					policyObjects = append(policyObjects, PolicyObject{
+						PolicyId:    policyId,
+						RuleObjects: []RuleObject{ruleObject},
+						Parameters:  maps.Clone(parameters),
+						Scope:       scope,
+					})
+					continue
+				}
+				policyObject := &policyObjects[index]
+				if !slices.ContainsFunc(policyObject.RuleObjects, func(r RuleObject) bool { return r.RuleId == ruleObject.RuleId }) {
+					policyObject.RuleObjects = append(policyObject.RuleObjects, ruleObject)
+				}
+				policyObject.Scope = policyObject.Scope.Merge(scope)
+				for paramId, values := range parameters {
+					merged, found := policyObject.Parameters[paramId]
+					if found && !reflect.DeepEqual(merged, values) {
+						return nil, fmt.Errorf("parameter %s of the rules of policy %s has conflicting values %v and %v", paramId, policyId, merged, values)
+					}
+					policyObject.Parameters[paramId] = values
+				}
+			}
+		}
+	}
+	return policyObjects, nil
+}
+
+// CollectParameters returns the values of the parameter of the rule set by the control implementations referring to the rule.
+// It fails if the rule has a parameter that no control implementation sets or that is set to different values.
+func CollectParameters(componentObject ComponentObject, ruleObject RuleObject) (map[string][]string, error) {
+	parameters := map[string][]string{}
+	if ruleObject.ParameterId == "" {
+		return parameters, nil
+	}
+	for _, cio := range componentObject.ControlImpleObjects {
+		if !cio.RefersRule(ruleObject.RuleId) {
+			continue
+		}
+		for _, setParameter := range cio.SetParameters {
+			if setParameter.ParamID != ruleObject.ParameterId {
+				continue
+			}
+			values, found := parameters[setParameter.ParamID]
+			if found && !reflect.DeepEqual(values, setParameter.Values) {
+				return nil, fmt.Errorf("parameter %s of rule %s has conflicting values %v and %v", setParameter.ParamID, ruleObject.RuleId, values, setParameter.Values)
+			}
+			parameters[setParameter.ParamID] = setParameter.Values
+		}
+	}
+	if len(parameters[ruleObject.ParameterId]) == 0 {
+		return nil, fmt.Errorf("parameter %s is not set for rule %s", ruleObject.ParameterId, ruleObject.RuleId)
+	}
+	return parameters, nil
+}
+
+func appendUnique(list []string, elements ...string) []string {
+	for _, element := range elements {
+		if !slices.Contains(list, element) {
+			list = append(list, element)
+		}
+	}
+	return list
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oscal
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/componentdefinition"
+)
+
+func makeTestComponentObject() ComponentObject {
+	return ComponentObject{
+		ComponentTitle: "Kubernetes",
+		ComponentType:  "service",
+		RuleObjects: []RuleObject{
+			{RuleId: "rule-a", PolicyId: "policy-a", ParameterId: "param-a"},
+			{RuleId: "rule-b", PolicyId: "policy-a", Scope: PolicyScope{EnforcementMode: "enforce", TargetNamespaces: []string{"prod"}}},
+			{RuleId: "rule-c"},
+		},
+		ControlImpleObjects: []ControlImpleObject{
+			{
+				SetParameters:  []SetParameter{{ParamID: "param-a", Values: []string{"1"}}},
+				ControlObjects: []ControlObject{{ControlId: "cm-2", RuleIds: []string{"rule-a", "rule-c"}}},
+				Scope:          PolicyScope{EnforcementMode: "audit", TargetNamespaces: []string{"app"}, ExcludedNamespaces: []string{"kube-system"}},
+			},
+			{
+				ControlObjects: []ControlObject{{ControlId: "cm-6", RuleIds: []string{"rule-a"}}},
+				Scope:          PolicyScope{TargetNamespaces: []string{"web"}},
+			},
+		},
+	}
+}
+
+func TestScopeOfRule(t *testing.T) {
+	componentObject := makeTestComponentObject()
+
+	// The scopes of the control implementations referring to the rule are merged.
+	// kube-system is not excluded since the second control implementation does not exclude it.
+	scope, err := ScopeOfRule(componentObject, componentObject.RuleObjects[0])
+	assert.NoError(t, err)
+	assert.Equal(t, PolicyScope{EnforcementMode: "audit", TargetNamespaces: []string{"app", "web"}}, scope)
+
+	// A control implementation without target namespaces applies the policies to all the namespaces
+	unrestricted := makeTestComponentObject()
+	unrestricted.ControlImpleObjects[1].Scope = PolicyScope{}
+	scope, err = ScopeOfRule(unrestricted, unrestricted.RuleObjects[0])
+	assert.NoError(t, err)
+	assert.Equal(t, PolicyScope{EnforcementMode: "audit"}, scope)
+
+	// The props of the rule take precedence over those of the control implementations
+	scope, err = ScopeOfRule(componentObject, componentObject.RuleObjects[1])
+	assert.NoError(t, err)
+	assert.Equal(t, PolicyScope{EnforcementMode: "enforce", TargetNamespaces: []string{"prod"}}, scope)
+
+	invalid := componentObject.RuleObjects[1]
+	invalid.Scope.EnforcementMode = "warn"
+	_, err = ScopeOfRule(componentObject, invalid)
+	assert.EqualError(t, err, "invalid Enforcement_Mode 'warn' of rule rule-b: must be audit or enforce")
+}
+
+func TestScopesOfPolicyObjects(t *testing.T) {
+	componentObject := makeTestComponentObject()
+	validation := ComponentObject{
+		ComponentType: "validation",
+		RuleObjects:   []RuleObject{{RuleId: "rule-a", Scope: PolicyScope{EnforcementMode: "warn"}}},
+	}
+	policyObjects, err := PolicyObjects([]ComponentObject{componentObject, validation})
+	assert.NoError(t, err)
+	scopes := map[string]PolicyScope{}
+	for _, policyObject := range policyObjects {
+		scopes[policyObject.PolicyId] = policyObject.Scope
+	}
+	assert.Equal(t, map[string]PolicyScope{
+		// Enforce of rule-b takes precedence over audit of rule-a and the target namespaces are merged
+		"policy-a": {EnforcementMode: "enforce", TargetNamespaces: []string{"app", "web", "prod"}},
+		"rule-c":   {EnforcementMode: "audit", TargetNamespaces: []string{"app"}, ExcludedNamespaces: []string{"kube-system"}},
+	}, scopes)
+}
+
+func TestScopesOfPolicyObjectsWithUnscopedRule(t *testing.T) {
+	componentObject := ComponentObject{
+		ComponentType: "service",
+		RuleObjects: []RuleObject{
+			{RuleId: "scoped", PolicyId: "policy-a", Scope: PolicyScope{EnforcementMode: "enforce", TargetNamespaces: []string{"app"}, ExcludedNamespaces: []string{"kube-system", "kube-public"}}},
+			{RuleId: "unscoped", PolicyId: "policy-a", Scope: PolicyScope{ExcludedNamespaces: []string{"kube-system"}}},
+		},
+	}
+	policyObjects, err := PolicyObjects([]ComponentObject{componentObject})
+	assert.NoError(t, err)
+	// The unscoped rule keeps the policy applied to all the namespaces except those excluded by both rules
+	assert.Equal(t, PolicyScope{EnforcementMode: "enforce", ExcludedNamespaces: []string{"kube-system"}}, policyObjects[0].Scope)
+
+	// The order of the rules does not matter
+	slices.Reverse(componentObject.RuleObjects)
+	reversed, err := PolicyObjects([]ComponentObject{componentObject})
+	assert.NoError(t, err)
+	assert.Equal(t, policyObjects[0].Scope, reversed[0].Scope)
+}
+
+func TestCollectParameters(t *testing.T) {
+	componentObject := makeTestComponentObject()
+
+	parameters, err := CollectParameters(componentObject, componentObject.RuleObjects[0])
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{"param-a": {"1"}}, parameters)
+
+	parameters, err = CollectParameters(componentObject, componentObject.RuleObjects[2])
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{}, parameters)
+
+	conflicting := makeTestComponentObject()
+	conflicting.ControlImpleObjects[1].SetParameters = []SetParameter{{ParamID: "param-a", Values: []string{"2"}}}
+	_, err = CollectParameters(conflicting, conflicting.RuleObjects[0])
+	assert.EqualError(t, err, "parameter param-a of rule rule-a has conflicting values [1] and [2]")
+
+	unset := makeTestComponentObject()
+	unset.ControlImpleObjects[0].SetParameters = nil
+	_, err = CollectParameters(unset, unset.RuleObjects[0])
+	assert.EqualError(t, err, "parameter param-a is not set for rule rule-a")
+}
+
+func TestPolicyObjects(t *testing.T) {
+	componentObject := makeTestComponentObject()
+	componentObject.RuleObjects[1].ParameterId = "param-b"
+	componentObject.ControlImpleObjects[1].SetParameters = []SetParameter{{ParamID: "param-b", Values: []string{"2"}}}
+	componentObject.ControlImpleObjects[1].ControlObjects[0].RuleIds = []string{"rule-a", "rule-b"}
+	policyObjects, err := PolicyObjects([]ComponentObject{componentObject})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(policyObjects))
+
+	// The rules referring to the policy give the parameters and the scope of the policy
+	assert.Equal(t, "policy-a", policyObjects[0].PolicyId)
+	assert.Equal(t, []string{"rule-a", "rule-b"}, []string{policyObjects[0].RuleObjects[0].RuleId, policyObjects[0].RuleObjects[1].RuleId})
+	assert.Equal(t, map[string][]string{"param-a": {"1"}, "param-b": {"2"}}, policyObjects[0].Parameters)
+	assert.Equal(t, "enforce", policyObjects[0].Scope.EnforcementMode)
+	assert.Equal(t, "rule-c", policyObjects[1].PolicyId)
+	assert.Equal(t, map[string][]string{}, policyObjects[1].Parameters)
+
+	conflicting := makeTestComponentObject()
+	conflicting.RuleObjects[1].ParameterId = "param-a"
+	conflicting.ControlImpleObjects[1].SetParameters = []SetParameter{{ParamID: "param-a", Values: []string{"2"}}}
+	conflicting.ControlImpleObjects[1].ControlObjects[0].RuleIds = []string{"rule-b"}
+	_, err = PolicyObjects([]ComponentObject{conflicting})
+	assert.EqualError(t, err, "parameter param-a of the rules of policy policy-a has conflicting values [1] and [2]")
+}
@@ -0,0 +1,464 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oscal
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+
+	cd "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/componentdefinition"
+)
+
+// Column of the trestle CSV. Columns prefixed by $$ are required and the ones prefixed by $ are optional.
+type trestleCsvColumn struct {
+	name        string
+	description string
+	get         func(row *TrestleCsvRow) string
+	set         func(row *TrestleCsvRow, value string)
+}
+
+// Columns of the trestle CSV in the order of the header
+var trestleCsvColumns = []trestleCsvColumn{
+	{
+		"$$Component_Title", "A human readable name for the component.",
+		func(r *TrestleCsvRow) string { return r.ComponentTitle },
+		func(r *TrestleCsvRow, v string) { r.ComponentTitle = v },
+	},
+	{
+		"$$Component_Type", "A category describing the purpose of the component. ALLOWED VALUES interconnection:software:hardware:service:policy:physical:process-procedure:plan:guidance:standard:validation:",
+		func(r *TrestleCsvRow) string { return r.ComponentType },
+		func(r *TrestleCsvRow, v string) { r.ComponentType = v },
+	},
+	{
+		"$$Control_Id_List", "A list of textual labels that uniquely identify the controls or statements that the component implements.",
+		func(r *TrestleCsvRow) string {
+			controlIdList := []string{}
+			for _, control := range r.ControlIdList {
+				controlId, _ := controlIdFromPolicyCollectionToOscal(control)
+				controlIdList = append(controlIdList, controlId)
+			}
+			return strings.Join(controlIdList, " ")
+		},
+		func(r *TrestleCsvRow, v string) { r.ControlIdList = strings.Fields(v) },
+	},
+	{
+		"$$Rule_Id", "A textual label that uniquely identifies a policy (desired state) that can be used to reference it elsewhere in this or other documents.",
+		func(r *TrestleCsvRow) string { return r.RuleId },
+		func(r *TrestleCsvRow, v string) { r.RuleId = v },
+	},
+	{
+		"$$Rule_Description", "A description of the policy (desired state) including information about its purpose and scope.",
+		func(r *TrestleCsvRow) string { return r.RuleDescription },
+		func(r *TrestleCsvRow, v string) { r.RuleDescription = v },
+	},
+	{
+		"$Parameter_Id", "A textual label that uniquely identifies the parameter associated with that policy (desired state) or controls implemented by the policy (desired state).",
+		func(r *TrestleCsvRow) string { return r.ParameterId },
+		func(r *TrestleCsvRow, v string) { r.ParameterId = v },
+	},
+	{
+		"$Parameter_Description", "A description of the parameter.",
+		func(r *TrestleCsvRow) string { return r.ParameterDescription },
+		func(r *TrestleCsvRow, v string) { r.ParameterDescription = v },
+	},
+	{
+		"$Parameter_Value_Alternatives", "Value alternatives for the parameter.",
+		func(r *TrestleCsvRow) string { return r.ParameterValueAlternatives },
+		func(r *TrestleCsvRow, v string) { r.ParameterValueAlternatives = v },
+	},
+	{
+		"$Parameter_Value_Default", "Default value of the parameter set for the control implementation.",
+		func(r *TrestleCsvRow) string { return r.ParameterValueDefault },
+		func(r *TrestleCsvRow, v string) { r.ParameterValueDefault = v },
+	},
+	{
+		"$$Check_Id", "A textual label that uniquely identifies a check of the policy (desired state) evaluation that can be used to reference it elsewhere in this or other documents.",
+		func(r *TrestleCsvRow) string { return r.CheckId },
+		func(r *TrestleCsvRow, v string) { r.CheckId = v },
+	},
+	{
+		"$$Check_Description", "A description of the check of the policy (desired state) evaluation.",
+		func(r *TrestleCsvRow) string { return r.CheckDescription },
+		func(r *TrestleCsvRow, v string) { r.CheckDescription = v },
+	},
+	{
+		"$$Profile_Source", "A URL reference to the source catalog or profile for which this component is implementing controls for.",
+		func(r *TrestleCsvRow) string { return r.ProfileSource },
+		func(r *TrestleCsvRow, v string) { r.ProfileSource = v },
+	},
+	{
+		"$$Profile_Description", "A description of the profile.",
+		func(r *TrestleCsvRow) string { return r.ProfileDescription },
+		func(r *TrestleCsvRow, v string) { r.ProfileDescription = v },
+	},
+	{
+		"$$Namespace", "A namespace qualifying the property name.",
+		func(r *TrestleCsvRow) string { return r.Namespace },
+		func(r *TrestleCsvRow, v string) { r.Namespace = v },
+	},
+	{
+		"$$Component_Description", "A description of the component including information about its function.",
+		func(r *TrestleCsvRow) string { return r.ComponentDescription },
+		func(r *TrestleCsvRow, v string) { r.ComponentDescription = v },
+	},
+}
+
+// Columns required for reading the CSV. The other columns prefixed by $$ (e.g. $$Check_Id) are required by trestle only for some components.
+var requiredTrestleCsvColumns = []string{"Component_Title", "Component_Type", "Control_Id_List", "Rule_Id", "Rule_Description", "Profile_Source", "Profile_Description", "Namespace", "Component_Description"}
+
+// ReadTrestleCsv reads the rows of the trestle CSV. The first row is the header and the second row is the descriptions of the columns.
+func ReadTrestleCsv(r io.Reader) ([]TrestleCsvRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("no header in the CSV")
+	}
+	header := records[0]
+	names := []string{}
+	for _, column := range header {
+		names = append(names, strings.TrimLeft(strings.TrimSpace(column), "$"))
+	}
+	for _, required := range requiredTrestleCsvColumns {
+		if !slices.Contains(names, required) {
+			return nil, fmt.Errorf("required column $$%s is not in the header", required)
+		}
+	}
+	rows := []TrestleCsvRow{}
+	for idx, record := range records {
+		// Skip the header and the descriptions
+		if idx < 2 {
+			continue
+		}
+		row := TrestleCsvRow{UserColumns: map[string]string{}}
+		empty := true
+		for colIdx, value := range record {
+			if colIdx >= len(header) {
+				break
+			}
+			value = strings.TrimSpace(value)
+			if value != "" {
+				empty = false
+			}
+			column, ok := findTrestleCsvColumn(names[colIdx])
+			if ok {
+				column.set(&row, value)
+			} else if !strings.HasPrefix(header[colIdx], "$") && value != "" {
+				row.UserColumns[names[colIdx]] = value
+			}
+		}
+		if empty {
+			continue
+		}
+		if row.ComponentTitle == "" || row.ComponentType == "" || row.RuleId == "" {
+			return nil, fmt.Errorf("row %d: $$Component_Title, $$Component_Type and $$Rule_Id are required", idx+1)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func findTrestleCsvColumn(name string) (trestleCsvColumn, bool) {
+	for _, column := range trestleCsvColumns {
+		if strings.TrimLeft(column.name, "$") == name {
+			return column, true
+		}
+	}
+	return trestleCsvColumn{}, false
+}
+
+// WriteTrestleCsv writes the rows in the trestle CSV with the header, the descriptions of the columns and the user columns of the rows.
+func WriteTrestleCsv(w io.Writer, rows []TrestleCsvRow) error {
+	userColumns := []string{}
+	for _, row := range rows {
+		for name := range row.UserColumns {
+			if !slices.Contains(userColumns, name) {
+				userColumns = append(userColumns, name)
+			}
+		}
+	}
+	sort.Strings(userColumns)
+
+	header := (&TrestleCsvRow{}).Header()
+	descriptions := []string{}
+	for _, column := range trestleCsvColumns {
+		descriptions = append(descriptions, column.description)
+	}
+	for _, name := range userColumns {
+		header = append(header, name)
+		descriptions = append(descriptions, "")
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	if err := writer.Write(descriptions); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		values := row.ToStringList()
+		for _, name := range userColumns {
+			values = append(values, row.UserColumns[name])
+		}
+		if err := writer.Write(values); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// MakeComponentDefinitionFromTrestleCsv generates the component-definition from the rows of the trestle CSV as trestle csv-to-oscal-cd does.
+// The props of each rule are grouped by the remarks rule_set_<n> and the controls (or statements) of the rows refer to the rules.
+func MakeComponentDefinitionFromTrestleCsv(rows []TrestleCsvRow, title string, stamper Stamper) (*cd.ComponentDefinitionRoot, error) {
+	timestamp, err := stamper.Timestamp()
+	if err != nil {
+		return nil, err
+	}
+	components := []*cd.Component{}
+	// Remarks of the rule sets per component title, rule ID and check ID
+	ruleSets := map[string]string{}
+	for _, row := range rows {
+		var component *cd.Component
+		for _, c := range components {
+			if c.Title == row.ComponentTitle {
+				component = c
+				break
+			}
+		}
+		if component == nil {
+			component = &cd.Component{
+				UUID:        stamper.UUID("component", row.ComponentTitle),
+				Type:        row.ComponentType,
+				Title:       row.ComponentTitle,
+				Description: row.ComponentDescription,
+			}
+			components = append(components, component)
+		}
+
+		ruleSetKey := strings.Join([]string{row.ComponentTitle, row.RuleId, row.CheckId}, "\x00")
+		if _, ok := ruleSets[ruleSetKey]; !ok {
+			remarks := fmt.Sprintf("rule_set_%d", len(ruleSets))
+			ruleSets[ruleSetKey] = remarks
+			component.Props = append(component.Props, ruleSetProps(row, remarks)...)
+		}
+
+		if row.ProfileSource == "" {
+			continue
+		}
+		var controlImpl *cd.ControlImplementation
+		for idx := range component.ControlImplementations {
+			ci := &component.ControlImplementations[idx]
+			if ci.Source == row.ProfileSource && ci.Description == row.ProfileDescription {
+				controlImpl = ci
+				break
+			}
+		}
+		if controlImpl == nil {
+			component.ControlImplementations = append(component.ControlImplementations, cd.ControlImplementation{
+				UUID:                    stamper.UUID("control-implementation", row.ComponentTitle, row.ProfileSource, row.ProfileDescription),
+				Source:                  row.ProfileSource,
+				Description:             row.ProfileDescription,
+				ImplementedRequirements: []cd.ImplementedRequirement{},
+			})
+			controlImpl = &component.ControlImplementations[len(component.ControlImplementations)-1]
+		}
+		if row.ParameterId != "" && row.ParameterValueDefault != "" && !slices.ContainsFunc(controlImpl.SetParameters, func(sp cd.SetParameter) bool {
+			return sp.ParamID == row.ParameterId
+		}) {
+			controlImpl.SetParameters = append(controlImpl.SetParameters, cd.SetParameter{
+				ParamID: row.ParameterId,
+				Values:  strings.Split(row.ParameterValueDefault, ","),
+			})
+		}
+		ruleProp := cd.Prop{Name: "Rule_Id", Ns: row.Namespace, Value: row.RuleId}
+		for _, id := range row.ControlIdList {
+			controlId, _, _ := strings.Cut(id, "_smt")
+			var implReq *cd.ImplementedRequirement
+			for idx := range controlImpl.ImplementedRequirements {
+				if controlImpl.ImplementedRequirements[idx].ControlID == controlId {
+					implReq = &controlImpl.ImplementedRequirements[idx]
+					break
+				}
+			}
+			if implReq == nil {
+				controlImpl.ImplementedRequirements = append(controlImpl.ImplementedRequirements, cd.ImplementedRequirement{
+					UUID:      stamper.UUID("implemented-requirement", row.ComponentTitle, row.ProfileSource, row.ProfileDescription, controlId),
+					ControlID: controlId,
+				})
+				implReq = &controlImpl.ImplementedRequirements[len(controlImpl.ImplementedRequirements)-1]
+			}
+			if id == controlId {
+				implReq.Props = appendRuleProp(implReq.Props, ruleProp)
+				continue
+			}
+			var statement *cd.Statement
+			for idx := range implReq.Statements {
+				if implReq.Statements[idx].StatementId == id {
+					statement = &implReq.Statements[idx]
+					break
+				}
+			}
+			if statement == nil {
+				implReq.Statements = append(implReq.Statements, cd.Statement{
+					StatementId: id,
+					UUID:        stamper.UUID("statement", row.ComponentTitle, row.ProfileSource, row.ProfileDescription, id),
+				})
+				statement = &implReq.Statements[len(implReq.Statements)-1]
+			}
+			statement.Props = appendRuleProp(statement.Props, ruleProp)
+		}
+	}
+
+	compDef := cd.ComponentDefinition{
+		UUID: stamper.UUID("component-definition", title),
+		Metadata: cd.Metadata{
+			Title:        title,
+			LastModified: timestamp,
+			Version:      "1.0",
+			OscalVersion: OscalVersion,
+		},
+		Components: []cd.Component{},
+	}
+	for _, component := range components {
+		compDef.Components = append(compDef.Components, *component)
+	}
+	return &cd.ComponentDefinitionRoot{ComponentDefinition: compDef}, nil
+}
+
+func ruleSetProps(row TrestleCsvRow, remarks string) []cd.Prop {
+	props := []cd.Prop{}
+	add := func(name string, value string) {
+		if value != "" {
+			props = append(props, cd.Prop{Name: name, Ns: row.Namespace, Value: value, Remarks: remarks})
+		}
+	}
+	add("Rule_Id", row.RuleId)
+	add("Rule_Description", row.RuleDescription)
+	add("Parameter_Id", row.ParameterId)
+	add("Parameter_Description", row.ParameterDescription)
+	add("Parameter_Value_Alternatives", row.ParameterValueAlternatives)
+	add("Check_Id", row.CheckId)
+	add("Check_Description", row.CheckDescription)
+	userColumns := []string{}
+	for name := range row.UserColumns {
+		userColumns = append(userColumns, name)
+	}
+	sort.Strings(userColumns)
+	for _, name := range userColumns {
+		add(name, row.UserColumns[name])
+	}
+	return props
+}
+
+func appendRuleProp(props []cd.Prop, ruleProp cd.Prop) []cd.Prop {
+	for _, prop := range props {
+		if prop.Name == ruleProp.Name && prop.Value == ruleProp.Value {
+			return props
+		}
+	}
+	return append(props, ruleProp)
+}
+
+// MakeTrestleCsvFromComponentDefinition generates the rows of the trestle CSV from the component-definition,
+// one row per rule set and control implementation referring to the rule.
+func MakeTrestleCsvFromComponentDefinition(cdRoot cd.ComponentDefinitionRoot) []TrestleCsvRow {
+	rows := []TrestleCsvRow{}
+	for _, component := range cdRoot.Components {
+		componentProps := TrestleComponentProps{
+			ComponentTitle:       component.Title,
+			ComponentDescription: component.Description,
+			ComponentType:        component.Type,
+		}
+		remarksList := []string{}
+		ruleSets := map[string]*TrestleCsvRow{}
+		for _, prop := range component.Props {
+			if prop.Remarks == "" {
+				continue
+			}
+			row, ok := ruleSets[prop.Remarks]
+			if !ok {
+				row = &TrestleCsvRow{TrestleComponentProps: componentProps, UserColumns: map[string]string{}}
+				ruleSets[prop.Remarks] = row
+				remarksList = append(remarksList, prop.Remarks)
+			}
+			if prop.Name == "Rule_Id" {
+				row.Namespace = prop.Ns
+			}
+			if column, ok := findTrestleCsvColumn(prop.Name); ok {
+				column.set(row, prop.Value)
+			} else {
+				row.UserColumns[prop.Name] = prop.Value
+			}
+		}
+		for _, remarks := range remarksList {
+			rule := ruleSets[remarks]
+			if rule.RuleId == "" {
+				continue
+			}
+			referred := false
+			for _, controlImpl := range component.ControlImplementations {
+				controlIds := []string{}
+				for _, implReq := range controlImpl.ImplementedRequirements {
+					if refersRule(implReq.Props, rule.RuleId) {
+						controlIds = append(controlIds, implReq.ControlID)
+					}
+					for _, statement := range implReq.Statements {
+						if refersRule(statement.Props, rule.RuleId) {
+							controlIds = append(controlIds, statement.StatementId)
+						}
+					}
+				}
+				if len(controlIds) == 0 {
+					continue
+				}
+				referred = true
+				row := *rule
+				row.ControlIdList = controlIds
+				row.ProfileSource = controlImpl.Source
+				row.ProfileDescription = controlImpl.Description
+				for _, setParameter := range controlImpl.SetParameters {
+					if setParameter.ParamID == rule.ParameterId {
+						row.ParameterValueDefault = strings.Join(setParameter.Values, ",")
+					}
+				}
+				rows = append(rows, row)
+			}
+			if !referred {
+				rows = append(rows, *rule)
+			}
+		}
+	}
+	return rows
+}
+
+func refersRule(props []cd.Prop, ruleId string) bool {
+	for _, prop := range listRules(props) {
+		if prop.Value == ruleId {
+			return true
+		}
+	}
+	return false
+}
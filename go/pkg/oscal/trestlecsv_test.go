@@ -0,0 +1,92 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oscal
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/oscal-compass/compliance-to-policy/go/pkg"
+	cd "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/componentdefinition"
+)
+
+func TestTrestleCsvRoundTrip(t *testing.T) {
+	for _, path := range []string{
+		"./testdata/ocm/component-definition.json",
+		"./testdata/kyverno/validation-component/component-definition.json",
+	} {
+		var cdRoot cd.ComponentDefinitionRoot
+		err := pkg.LoadOscalFileToObject(pkg.PathFromPkgDirectory(path), &cdRoot)
+		assert.NoError(t, err, "Should not happen")
+
+		buffer := bytes.NewBuffer([]byte{})
+		err = WriteTrestleCsv(buffer, MakeTrestleCsvFromComponentDefinition(cdRoot))
+		assert.NoError(t, err, "Should not happen")
+
+		rows, err := ReadTrestleCsv(buffer)
+		assert.NoError(t, err, "Should not happen")
+		generated, err := MakeComponentDefinitionFromTrestleCsv(rows, cdRoot.Metadata.Title, NewStamper(true))
+		assert.NoError(t, err, "Should not happen")
+
+		diff := cmp.Diff(ParseComponentDefinition(cdRoot), ParseComponentDefinition(*generated),
+			cmpopts.IgnoreFields(ControlObject{}, "ImplementationUUID"),
+			cmpopts.EquateEmpty(),
+		)
+		assert.Empty(t, diff, "%s is round-tripped", path)
+
+		err = pkg.WriteOscalObjToFile(pkg.PathFromPkgDirectory("./oscal/testdata/_test/component-definition-from-csv.json"), generated, "json")
+		assert.NoError(t, err, "generated component-definition is valid")
+	}
+}
+
+func TestReadTrestleCsv(t *testing.T) {
+	data := "$$Component_Title,$$Component_Type,$$Control_Id_List,$$Rule_Id,$$Rule_Description,$Parameter_Id,$Parameter_Value_Default,$$Profile_Source,$$Profile_Description,$$Namespace,$$Component_Description,Policy_Id\n" +
+		"descriptions,,,,,,,,,,,\n" +
+		"Kubernetes,service,ac-2 ac-2_smt.a cm-6,rule_a,Rule A,param_a,10,./profile.json,Profile,http://example.com/ns,Kubernetes cluster,policy-a\n" +
+		"Kubernetes,service,cm-6,rule_b,Rule B,,,./profile.json,Profile,http://example.com/ns,Kubernetes cluster,\n"
+	rows, err := ReadTrestleCsv(bytes.NewBufferString(data))
+	assert.NoError(t, err, "Should not happen")
+	assert.Len(t, rows, 2)
+	assert.Equal(t, []string{"ac-2", "ac-2_smt.a", "cm-6"}, rows[0].ControlIdList)
+	assert.Equal(t, map[string]string{"Policy_Id": "policy-a"}, rows[0].UserColumns)
+
+	generated, err := MakeComponentDefinitionFromTrestleCsv(rows, "From CSV", NewStamper(true))
+	assert.NoError(t, err, "Should not happen")
+	component := generated.Components[0]
+	assert.Equal(t, []string{"Rule_Id", "Rule_Description", "Parameter_Id", "Policy_Id", "Rule_Id", "Rule_Description"}, propNames(component.Props))
+	assert.Equal(t, "rule_set_1", component.Props[4].Remarks)
+	controlImpl := component.ControlImplementations[0]
+	assert.Equal(t, []cd.SetParameter{{ParamID: "param_a", Values: []string{"10"}}}, controlImpl.SetParameters)
+	assert.Len(t, controlImpl.ImplementedRequirements, 2)
+	assert.Equal(t, "ac-2_smt.a", controlImpl.ImplementedRequirements[0].Statements[0].StatementId)
+	assert.Len(t, controlImpl.ImplementedRequirements[1].Props, 2)
+
+	_, err = ReadTrestleCsv(bytes.NewBufferString("$$Component_Title,$$Rule_Id\n"))
+	assert.Error(t, err)
+}
+
+func propNames(props []cd.Prop) []string {
+	names := []string{}
+	for _, prop := range props {
+		names = append(names, prop.Name)
+	}
+	return names
+}
@@ -0,0 +1,268 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// The OSCAL JSON schemas released by NIST, vendored by hack/update-oscal-schemas.sh
+//
+//go:embed schemas
+var schemaFS embed.FS
+
+// OSCAL models (the root property of the documents) and the file names of the schemas released by NIST
+var schemaFiles = map[string]string{
+	"catalog":                       "oscal_catalog_schema.json",
+	"profile":                       "oscal_profile_schema.json",
+	"component-definition":          "oscal_component_schema.json",
+	"assessment-results":            "oscal_assessment-results_schema.json",
+	"assessment-plan":               "oscal_assessment-plan_schema.json",
+	"plan-of-action-and-milestones": "oscal_poam_schema.json",
+}
+
+// ErrSchemaNotFound is returned if the schema of the model is not vendored
+var ErrSchemaNotFound = errors.New("OSCAL schema is not vendored (run hack/update-oscal-schemas.sh)")
+
+// validator validates the documents against the schemas in the file system
+type validator struct {
+	fsys       fs.FS
+	once       sync.Once
+	schemas    map[string]*jsonschema.Schema
+	compileErr error
+}
+
+var defaultValidator = newValidator(mustSub(schemaFS, "schemas"))
+
+func newValidator(fsys fs.FS) *validator {
+	return &validator{fsys: fsys}
+}
+
+func mustSub(fsys fs.FS, dir string) fs.FS {
+	sub, err := fs.Sub(fsys, dir)
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
+
+// ValidationError is a schema violation located by JSON pointers.
+type ValidationError struct {
+	// JSON pointer to the invalid value in the document (e.g. "/assessment-results/results/0/uuid")
+	InstanceLocation string `json:"instanceLocation"`
+	// JSON pointer to the violated keyword in the schema (e.g. "/properties/assessment-results/$ref/required")
+	KeywordLocation string `json:"keywordLocation"`
+	Message         string `json:"message"`
+}
+
+func (e ValidationError) Error() string {
+	location := e.InstanceLocation
+	if location == "" {
+		location = "/"
+	}
+	return fmt.Sprintf("%s: %s", location, e.Message)
+}
+
+// ValidationErrors is returned if a document violates the schema of its model.
+type ValidationErrors struct {
+	Model  string            `json:"model"`
+	Errors []ValidationError `json:"errors"`
+}
+
+func (e *ValidationErrors) Error() string {
+	messages := []string{}
+	for _, err := range e.Errors {
+		messages = append(messages, err.Error())
+	}
+	return fmt.Sprintf("%s is not valid against the OSCAL schema: %s", e.Model, strings.Join(messages, "; "))
+}
+
+// Models returns the OSCAL models that can be validated.
+func Models() []string {
+	models := []string{}
+	for model := range schemaFiles {
+		models = append(models, model)
+	}
+	sort.Strings(models)
+	return models
+}
+
+// DetectModel returns the OSCAL model of the JSON document given by its root property.
+func DetectModel(jsonData []byte) (string, error) {
+	root := map[string]json.RawMessage{}
+	if err := json.Unmarshal(jsonData, &root); err != nil {
+		return "", err
+	}
+	for model := range root {
+		if _, ok := schemaFiles[model]; ok {
+			return model, nil
+		}
+	}
+	keys := []string{}
+	for key := range root {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return "", fmt.Errorf("unsupported OSCAL model %v: must be one of %v", keys, Models())
+}
+
+// IsSupported returns true if the JSON document is of a model that can be validated.
+func IsSupported(jsonData []byte) bool {
+	_, err := DetectModel(jsonData)
+	return err == nil
+}
+
+// Validate validates the OSCAL document in JSON against the schema of its model.
+// Schema violations are returned as *ValidationErrors.
+func Validate(jsonData []byte) error {
+	model, err := DetectModel(jsonData)
+	if err != nil {
+		return err
+	}
+	return ValidateModel(model, jsonData)
+}
+
+// ValidateModel validates the OSCAL document in JSON against the schema of the model.
+func ValidateModel(model string, jsonData []byte) error {
+	return defaultValidator.validateModel(model, jsonData)
+}
+
+// HasSchema returns true if the schema of the model is vendored.
+func HasSchema(model string) bool {
+	if err := defaultValidator.compile(); err != nil {
+		return false
+	}
+	_, ok := defaultValidator.schemas[model]
+	return ok
+}
+
+func (v *validator) validateModel(model string, jsonData []byte) error {
+	if _, ok := schemaFiles[model]; !ok {
+		return fmt.Errorf("unsupported OSCAL model %s: must be one of %v", model, Models())
+	}
+	if err := v.compile(); err != nil {
+		return err
+	}
+	schema, ok := v.schemas[model]
+	if !ok {
+		return fmt.Errorf("%s of %s: %w", schemaFiles[model], model, ErrSchemaNotFound)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(jsonData))
+	decoder.UseNumber()
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return err
+	}
+	err := schema.Validate(doc)
+	if err == nil {
+		return nil
+	}
+	var schemaErr *jsonschema.ValidationError
+	if !errors.As(err, &schemaErr) {
+		return err
+	}
+	return &ValidationErrors{Model: model, Errors: flatten(schemaErr)}
+}
+
+// ValidateObject validates the OSCAL document root (e.g. typear.AssessmentResultsRoot).
+func ValidateObject(in interface{}) error {
+	jsonData, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return Validate(jsonData)
+}
+
+// compile compiles the schemas of the models found in the file system. All the schemas in the file system, including
+// the schemas referred to by those of the models, are registered by their $id, so the schemas released by NIST are used as they are.
+func (v *validator) compile() error {
+	v.once.Do(func() {
+		compiler := jsonschema.NewCompiler()
+		compiler.Draft = jsonschema.Draft7
+		files, err := fs.Glob(v.fsys, "*.json")
+		if err != nil {
+			v.compileErr = err
+			return
+		}
+		idsOfFiles := map[string]string{}
+		for _, file := range files {
+			data, err := fs.ReadFile(v.fsys, file)
+			if err != nil {
+				v.compileErr = err
+				return
+			}
+			var header struct {
+				Id string `json:"$id"`
+			}
+			if err := json.Unmarshal(data, &header); err != nil {
+				v.compileErr = fmt.Errorf("failed to read %s: %w", file, err)
+				return
+			}
+			if header.Id == "" {
+				v.compileErr = fmt.Errorf("%s has no $id", file)
+				return
+			}
+			if err := compiler.AddResource(header.Id, bytes.NewReader(data)); err != nil {
+				v.compileErr = err
+				return
+			}
+			idsOfFiles[file] = header.Id
+		}
+		ids := map[string]string{}
+		for model, file := range schemaFiles {
+			if id, ok := idsOfFiles[file]; ok {
+				ids[model] = id
+			}
+		}
+		v.schemas = map[string]*jsonschema.Schema{}
+		for model, id := range ids {
+			schema, err := compiler.Compile(id)
+			if err != nil {
+				v.compileErr = fmt.Errorf("failed to compile the schema of %s: %w", model, err)
+				return
+			}
+			v.schemas[model] = schema
+		}
+	})
+	return v.compileErr
+}
+
+// flatten returns the leaf causes of the error, which point to the actual violations.
+func flatten(err *jsonschema.ValidationError) []ValidationError {
+	if len(err.Causes) == 0 {
+		return []ValidationError{{
+			InstanceLocation: err.InstanceLocation,
+			KeywordLocation:  err.KeywordLocation,
+			Message:          err.Message,
+		}}
+	}
+	errs := []ValidationError{}
+	for _, cause := range err.Causes {
+		errs = append(errs, flatten(cause)...)
+	}
+	return errs
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	typear "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/assessmentresults"
+)
+
+func TestValidate(t *testing.T) {
+	for _, file := range []string{
+		"../../testdata/oscal/catalog.json",
+		"../../testdata/ocm/profile.json",
+		"../../testdata/kyverno/component-definition.json",
+		"../../testdata/ocm/assessment-results.json",
+	} {
+		data, err := os.ReadFile(file)
+		assert.NoError(t, err, "Should not happen")
+		assert.NoError(t, Validate(data), file)
+	}
+}
+
+func TestEmbeddedSchemas(t *testing.T) {
+	// The schemas of all the models must be vendored by hack/update-oscal-schemas.sh
+	for _, model := range Models() {
+		assert.True(t, HasSchema(model), "the schema of %s is not vendored (run make update-oscal-schemas)", model)
+	}
+}
+
+func TestValidateWithViolations(t *testing.T) {
+	invalidData := []byte(`{
+  "component-definition": {
+    "uuid": "not-a-uuid",
+    "metadata": {
+      "title": "Invalid Component Definition",
+      "last-modified": "2023-12-05T16:10:04+09:00",
+      "version": "1.0",
+      "oscal-version": "1.1.2"
+    },
+    "components": [{
+      "uuid": "c8106bc8-5174-4e86-91a4-52f2fe0ed027",
+      "type": "Service",
+      "title": "Component",
+      "description": "Component",
+      "control-implementations": []
+    }]
+  }
+}`)
+	v := newValidator(os.DirFS("./testdata/schemas"))
+	data, err := os.ReadFile("../../testdata/kyverno/component-definition.json")
+	assert.NoError(t, err, "Should not happen")
+	assert.NoError(t, v.validateModel("component-definition", data))
+
+	err = v.validateModel("component-definition", invalidData)
+	var validationErrs *ValidationErrors
+	assert.True(t, errors.As(err, &validationErrs))
+	assert.Equal(t, "component-definition", validationErrs.Model)
+
+	locations := []string{}
+	for _, e := range validationErrs.Errors {
+		locations = append(locations, e.InstanceLocation)
+	}
+	assert.ElementsMatch(t, []string{
+		"/component-definition/uuid",
+		"/component-definition/components/0/control-implementations",
+	}, locations)
+
+	// Models without the schema are not validated
+	err = v.validateModel("assessment-results", []byte(`{"assessment-results": {}}`))
+	assert.ErrorIs(t, err, ErrSchemaNotFound)
+}
+
+func TestValidateAssessmentResults(t *testing.T) {
+	data, err := os.ReadFile("../../testdata/ocm/assessment-results.json")
+	assert.NoError(t, err, "Should not happen")
+	var arRoot typear.AssessmentResultsRoot
+	err = json.Unmarshal(data, &arRoot)
+	assert.NoError(t, err, "Should not happen")
+	assert.NoError(t, ValidateObject(arRoot))
+
+	// Fields not defined in the schema are rejected
+	var doc map[string]interface{}
+	err = json.Unmarshal(data, &doc)
+	assert.NoError(t, err, "Should not happen")
+	observation := doc["assessment-results"].(map[string]interface{})["results"].([]interface{})[0].(map[string]interface{})["observations"].([]interface{})[0].(map[string]interface{})
+	observation["markup-multiline"] = "remarks"
+	err = ValidateObject(doc)
+	var validationErrs *ValidationErrors
+	assert.True(t, errors.As(err, &validationErrs))
+	assert.Equal(t, "/assessment-results/results/0/observations/0", validationErrs.Errors[0].InstanceLocation)
+}
+
+func TestDetectModel(t *testing.T) {
+	model, err := DetectModel([]byte(`{"assessment-results": {}}`))
+	assert.NoError(t, err, "Should not happen")
+	assert.Equal(t, "assessment-results", model)
+
+	_, err = DetectModel([]byte(`{"system-security-plan": {}}`))
+	assert.Error(t, err)
+	assert.False(t, IsSupported([]byte(`{"apiVersion": "v1"}`)))
+}
@@ -0,0 +1,238 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pkg
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Placeholder for a parameter value in policy resources, e.g. "{{ c2p.parameters.minimum_replicas }}".
+// The value can be converted by a function, e.g. "{{ c2p.parameters.minimum_replicas | toInt }}".
+var ParameterPlaceholder = regexp.MustCompile(`\{\{\s*c2p\.parameters\.([A-Za-z0-9_.\-]+)\s*(?:\|\s*(toInt|toBool)\s*)?\}\}`)
+
+type Placeholder struct {
+	ParamId string
+	// Function converting the value ("toInt" or "toBool"). Empty if not specified.
+	Function string
+	// Embedded is true if the placeholder is a part of a string value
+	Embedded bool
+}
+
+// Tag returns the yaml tag of the values converted by the function of the placeholder.
+func (p Placeholder) Tag() string {
+	switch p.Function {
+	case "toInt":
+		return "!!int"
+	case "toBool":
+		return "!!bool"
+	}
+	return "!!str"
+}
+
+// ParameterResolver returns the values replacing the placeholder and their yaml tag.
+type ParameterResolver func(placeholder Placeholder) ([]string, string, error)
+
+// FindParameterIds returns the parameter ids referred by the placeholders in the data.
+func FindParameterIds(data []byte) []string {
+	paramIds := []string{}
+	for _, match := range ParameterPlaceholder.FindAllSubmatch(data, -1) {
+		paramId := string(match[1])
+		found := false
+		for _, _paramId := range paramIds {
+			if _paramId == paramId {
+				found = true
+				break
+			}
+		}
+		if !found {
+			paramIds = append(paramIds, paramId)
+		}
+	}
+	return paramIds
+}
+
+// ReplaceParametersInDir replaces parameter placeholders in the yaml files of the directory.
+// The files having no placeholders are left untouched.
+func ReplaceParametersInDir(dir string, resolve ParameterResolver) error {
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !(strings.HasSuffix(info.Name(), ".yaml") || strings.HasSuffix(info.Name(), ".yml")) {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if !ParameterPlaceholder.Match(data) {
+			return nil
+		}
+		substituted, err := SubstituteParameters(data, resolve)
+		if err != nil {
+			return fmt.Errorf("failed to apply parameters to %s: %w", path, err)
+		}
+		return os.WriteFile(path, substituted, info.Mode())
+	})
+}
+
+// SubstituteParameters replaces parameter placeholders in the yaml documents.
+// A scalar consisting of only a placeholder is replaced with a sequence if the parameter has multiple values
+// (or the values are spliced if the scalar is an item of a sequence). Placeholders embedded in a string
+// are replaced with the values joined by comma.
+func SubstituteParameters(data []byte, resolve ParameterResolver) ([]byte, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	buffer := bytes.NewBuffer([]byte{})
+	encoder := yaml.NewEncoder(buffer)
+	encoder.SetIndent(2)
+	for {
+		var node yaml.Node
+		err := decoder.Decode(&node)
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if err := substituteNode(&node, resolve); err != nil {
+			return nil, err
+		}
+		if err := encoder.Encode(&node); err != nil {
+			return nil, err
+		}
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func substituteNode(node *yaml.Node, resolve ParameterResolver) error {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			if err := substituteNode(child, resolve); err != nil {
+				return err
+			}
+		}
+	case yaml.MappingNode:
+		for idx := 1; idx < len(node.Content); idx += 2 {
+			if err := substituteNode(node.Content[idx], resolve); err != nil {
+				return err
+			}
+		}
+	case yaml.SequenceNode:
+		content := []*yaml.Node{}
+		for _, child := range node.Content {
+			values, tag, ok, err := exactPlaceholderValues(child, resolve)
+			if err != nil {
+				return err
+			}
+			if ok && len(values) > 1 {
+				for _, value := range values {
+					content = append(content, newScalarNode(value, tag, child.Style))
+				}
+				continue
+			}
+			if err := substituteNode(child, resolve); err != nil {
+				return err
+			}
+			content = append(content, child)
+		}
+		node.Content = content
+	case yaml.ScalarNode:
+		values, tag, ok, err := exactPlaceholderValues(node, resolve)
+		if err != nil {
+			return err
+		}
+		if ok {
+			if len(values) == 1 {
+				*node = *newScalarNode(values[0], tag, node.Style)
+			} else {
+				content := []*yaml.Node{}
+				for _, value := range values {
+					content = append(content, newScalarNode(value, tag, node.Style))
+				}
+				*node = yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Content: content}
+			}
+			return nil
+		}
+		var resolveErr error
+		node.Value = ParameterPlaceholder.ReplaceAllStringFunc(node.Value, func(text string) string {
+			match := ParameterPlaceholder.FindStringSubmatch(text)
+			values, _, err := resolve(Placeholder{ParamId: match[1], Function: match[2], Embedded: true})
+			if err != nil && resolveErr == nil {
+				resolveErr = err
+			}
+			return strings.Join(values, ",")
+		})
+		return resolveErr
+	}
+	return nil
+}
+
+func exactPlaceholderValues(node *yaml.Node, resolve ParameterResolver) ([]string, string, bool, error) {
+	if node.Kind != yaml.ScalarNode {
+		return nil, "", false, nil
+	}
+	value := strings.TrimSpace(node.Value)
+	match := ParameterPlaceholder.FindStringSubmatch(value)
+	if match == nil || match[0] != value {
+		return nil, "", false, nil
+	}
+	values, tag, err := resolve(Placeholder{ParamId: match[1], Function: match[2]})
+	if err != nil {
+		return nil, "", false, err
+	}
+	for _, value := range values {
+		if err := validateValue(value, tag); err != nil {
+			return nil, "", false, fmt.Errorf("parameter %s: %w", match[1], err)
+		}
+	}
+	return values, tag, true, nil
+}
+
+func validateValue(value string, tag string) error {
+	switch tag {
+	case "!!int":
+		if _, err := strconv.Atoi(value); err != nil {
+			return fmt.Errorf("value %s is not an integer", value)
+		}
+	case "!!bool":
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("value %s is not a boolean", value)
+		}
+	}
+	return nil
+}
+
+func newScalarNode(value string, tag string, style yaml.Style) *yaml.Node {
+	if tag != "!!str" {
+		// Converted values are written as plain scalars
+		style = 0
+	}
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: value, Style: style}
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pkg
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestSubstituteParameters(t *testing.T) {
+	data := []byte(`spec:
+  namespaces:
+  - kube-system
+  - "{{ c2p.parameters.namespaces }}"
+  allowed: "{{c2p.parameters.namespaces}}"
+  message: "allowed in {{ c2p.parameters.namespaces }}"
+  replicas: '{{ c2p.parameters.replicas | toInt }}'
+`)
+	resolve := func(placeholder Placeholder) ([]string, string, error) {
+		switch placeholder.ParamId {
+		case "namespaces":
+			return []string{"default", "app"}, placeholder.Tag(), nil
+		case "replicas":
+			return []string{"3"}, placeholder.Tag(), nil
+		}
+		return nil, "", fmt.Errorf("unknown parameter %s", placeholder.ParamId)
+	}
+	substituted, err := SubstituteParameters(data, resolve)
+	assert.NoError(t, err, "Should not happen")
+
+	var obj map[string]interface{}
+	err = yaml.Unmarshal(substituted, &obj)
+	assert.NoError(t, err, "Should not happen")
+	spec := obj["spec"].(map[string]interface{})
+	assert.Equal(t, []interface{}{"kube-system", "default", "app"}, spec["namespaces"])
+	assert.Equal(t, []interface{}{"default", "app"}, spec["allowed"])
+	assert.Equal(t, "allowed in default,app", spec["message"])
+	assert.Equal(t, 3, spec["replicas"])
+
+	_, err = SubstituteParameters([]byte(`message: "{{ c2p.parameters.unknown }} replicas"`), resolve)
+	assert.ErrorContains(t, err, "unknown parameter unknown")
+
+	_, err = SubstituteParameters([]byte(`enabled: "{{ c2p.parameters.namespaces | toBool }}"`), resolve)
+	assert.ErrorContains(t, err, "value default is not a boolean")
+}
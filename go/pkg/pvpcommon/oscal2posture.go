@@ -17,11 +17,7 @@ limitations under the License.
 package pvpcommon
 
 import (
-	"bytes"
 	"embed"
-	"html/template"
-	"os"
-	"strings"
 
 	"go.uber.org/zap"
 
@@ -32,7 +28,7 @@ import (
 	typecd "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/componentdefinition"
 )
 
-//go:embed template/*.md
+//go:embed template/*.md template/*.html
 var embeddedResources embed.FS
 
 type Oscal2Posture struct {
@@ -40,6 +36,7 @@ type Oscal2Posture struct {
 	c2pParsed         typec2pcr.C2PCRParsed
 	assessmentResults typear.AssessmentResultsRoot
 	templateFile      *string
+	format            PostureFormat
 }
 
 type TemplateValues struct {
@@ -53,15 +50,21 @@ func NewOscal2Posture(c2pParsed typec2pcr.C2PCRParsed, assessmentResults typear.
 		c2pParsed:         c2pParsed,
 		assessmentResults: assessmentResults,
 		templateFile:      templateFile,
+		format:            PostureFormatMarkdown,
 	}
 }
 
-func (r *Oscal2Posture) findSubjects(ruleId string) []typear.Subject {
+// SetFormat sets the format of the compliance posture. The template file is used only for the markdown and html formats.
+func (r *Oscal2Posture) SetFormat(format PostureFormat) {
+	r.format = format
+}
+
+func (r *Oscal2Posture) findSubjects(ruleId string, controlObject oscal.ControlObject) []typear.Subject {
 	subjects := []typear.Subject{}
 	for _, ar := range r.assessmentResults.AssessmentResults.Results {
 		for _, ob := range ar.Observations {
 			prop, found := oscal.FindProp("assessment-rule-id", ob.Props)
-			if found && prop.Value == ruleId {
+			if found && prop.Value == ruleId && oscal.IsObservationOf(ob, controlObject) {
 				subjects = append(subjects, ob.Subjects...)
 			}
 		}
@@ -69,9 +72,48 @@ func (r *Oscal2Posture) findSubjects(ruleId string) []typear.Subject {
 	return subjects
 }
 
+func (r *Oscal2Posture) toSubjects(ruleId string, controlObject oscal.ControlObject) []tp.Subject {
+	subjects := []tp.Subject{}
+	for _, rawSubject := range r.findSubjects(ruleId, controlObject) {
+		var result, reason string
+		resultProp, resultFound := oscal.FindProp("result", rawSubject.Props)
+		reasonProp, reasonFound := oscal.FindProp("reason", rawSubject.Props)
+
+		if resultFound {
+			result = resultProp.Value
+			if reasonFound {
+				reason = reasonProp.Value
+			}
+		} else {
+			result = "Error"
+			reason = "No results found."
+		}
+		subject := tp.Subject{
+			Title:  rawSubject.Title,
+			UUID:   rawSubject.SubjectUUID,
+			Result: result,
+			Reason: reason,
+		}
+		subjects = append(subjects, subject)
+	}
+	return subjects
+}
+
+// describeControl describes the control by the resolved profile, which has the parameter values set by the profile, or by the catalog.
+func (r *Oscal2Posture) describeControl(controlId string, statementId string, setParameters []typecd.SetParameter) (oscal.ControlDescription, bool) {
+	if description, ok := oscal.DescribeControl(r.c2pParsed.ResolvedProfile.Catalog, controlId, statementId, setParameters); ok {
+		return description, true
+	}
+	return oscal.DescribeControl(r.c2pParsed.Catalog.Catalog, controlId, statementId, setParameters)
+}
+
 func (r *Oscal2Posture) toTemplateValue() tp.TemplateValue {
+	catalogTitle := r.c2pParsed.Catalog.Catalog.Metadata.Title
+	if catalogTitle == "" {
+		catalogTitle = r.c2pParsed.ResolvedProfile.Catalog.Metadata.Title
+	}
 	templateValue := tp.TemplateValue{
-		CatalogTitle: r.c2pParsed.Catalog.Catalog.Metadata.Title,
+		CatalogTitle: catalogTitle,
 		Components:   []tp.Component{},
 	}
 	for _, componentObject := range r.c2pParsed.ComponentObjects {
@@ -82,44 +124,71 @@ func (r *Oscal2Posture) toTemplateValue() tp.TemplateValue {
 			ComponentTitle: componentObject.ComponentTitle,
 			ControlResults: []tp.ControlResult{},
 		}
+		// Statements are grouped under their control
+		controlResults := []*tp.ControlResult{}
 		for _, cio := range componentObject.ControlImpleObjects {
 			for _, co := range cio.ControlObjects {
-				controlResult := tp.ControlResult{
-					ControlId:   co.GetControlId(),
-					RuleResults: []tp.RuleResult{},
+				var controlResult *tp.ControlResult
+				for _, cr := range controlResults {
+					if cr.ControlId == co.ControlId {
+						controlResult = cr
+						break
+					}
 				}
+				if controlResult == nil {
+					controlResult = &tp.ControlResult{
+						ControlId:   co.ControlId,
+						RuleResults: []tp.RuleResult{},
+					}
+					if description, ok := r.describeControl(co.ControlId, "", cio.SetParameters); ok {
+						controlResult.ControlTitle = description.Title
+						controlResult.GroupId = description.GroupId
+						controlResult.GroupTitle = description.GroupTitle
+						controlResult.Statement = description.Statement
+					}
+					controlResults = append(controlResults, controlResult)
+				}
+				ruleResults := []tp.RuleResult{}
 				for _, ruleId := range co.RuleIds {
-					subjects := []tp.Subject{}
-					rawSubjects := r.findSubjects(ruleId)
-					for _, rawSubject := range rawSubjects {
-						var result, reason string
-						resultProp, resultFound := oscal.FindProp("result", rawSubject.Props)
-						reasonProp, reasonFound := oscal.FindProp("reason", rawSubject.Props)
-
-						if resultFound {
-							result = resultProp.Value
-							if reasonFound {
-								reason = reasonProp.Value
-							}
-						} else {
-							result = "Error"
-							reason = "No results found."
+					subjects := r.toSubjects(ruleId, co)
+					for _, subject := range subjects {
+						switch subject.Result {
+						case "pass":
+							controlResult.Passed++
+						case "fail":
+							controlResult.Failed++
+						default:
+							controlResult.Other++
 						}
-						subject := tp.Subject{
-							Title:  rawSubject.Title,
-							UUID:   rawSubject.SubjectUUID,
-							Result: result,
-							Reason: reason,
-						}
-						subjects = append(subjects, subject)
 					}
-					controlResult.RuleResults = append(controlResult.RuleResults, tp.RuleResult{
+					ruleResults = append(ruleResults, tp.RuleResult{
 						RuleId:   ruleId,
 						Subjects: subjects,
 					})
 				}
-				component.ControlResults = append(component.ControlResults, controlResult)
+				if co.StatementId == "" {
+					controlResult.RuleResults = append(controlResult.RuleResults, ruleResults...)
+				} else {
+					statementResult := tp.StatementResult{
+						StatementId: co.StatementId,
+						RuleResults: ruleResults,
+					}
+					if description, ok := r.describeControl(co.ControlId, co.StatementId, cio.SetParameters); ok {
+						statementResult.Statement = description.Statement
+					}
+					controlResult.StatementResults = append(controlResult.StatementResults, statementResult)
+				}
+			}
+		}
+		for _, controlResult := range controlResults {
+			component.ControlResults = append(component.ControlResults, *controlResult)
+			templateValue.Summary.Controls++
+			if controlResult.Failed > 0 {
+				templateValue.Summary.FailingControls++
 			}
+			templateValue.Summary.Passed += controlResult.Passed
+			templateValue.Summary.Failed += controlResult.Failed
+			templateValue.Summary.Other += controlResult.Other
 		}
 		templateValue.Components = append(templateValue.Components, component)
 	}
@@ -127,36 +196,15 @@ func (r *Oscal2Posture) toTemplateValue() tp.TemplateValue {
 }
 
 func (r *Oscal2Posture) Generate() ([]byte, error) {
-	var templateData []byte
-	var err error
-	if r.templateFile == nil {
-		templateData, err = embeddedResources.ReadFile("template/template.md")
-	} else {
-		templateData, err = os.ReadFile(*r.templateFile)
-	}
-	if err != nil {
-		return nil, err
-	}
-
-	funcmap := template.FuncMap{
-		"newline_with_indent": func(text string, indent int) string {
-			newText := strings.ReplaceAll(text, "\n", "\n"+strings.Repeat(" ", indent))
-			return newText
-		},
-	}
-
-	templateString := string(templateData)
-	tmpl := template.New("report.md")
-	tmpl.Funcs(funcmap)
-	tmpl, err = tmpl.Parse(templateString)
-	if err != nil {
-		return nil, err
-	}
 	templateValue := r.toTemplateValue()
-	buffer := bytes.NewBuffer([]byte{})
-	err = tmpl.Execute(buffer, templateValue)
-	if err != nil {
-		return nil, err
+	switch r.format {
+	case PostureFormatHtml:
+		return r.renderTemplate(templateValue, "template/template.html", parseHtmlTemplate)
+	case PostureFormatJson:
+		return renderJson(templateValue)
+	case PostureFormatCsv:
+		return renderCsv(templateValue)
+	default:
+		return r.renderTemplate(templateValue, "template/template.md", parseMarkdownTemplate)
 	}
-	return buffer.Bytes(), nil
 }
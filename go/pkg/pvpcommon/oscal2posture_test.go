@@ -0,0 +1,127 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pvpcommon
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/oscal-compass/compliance-to-policy/go/pkg"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/ocm"
+	tp "github.com/oscal-compass/compliance-to-policy/go/pkg/pvpcommon/template"
+	typec2pcr "github.com/oscal-compass/compliance-to-policy/go/pkg/types/c2pcr"
+	typear "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/assessmentresults"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestOscal2Posture(t *testing.T) *Oscal2Posture {
+	tempDirPath := pkg.PathFromPkgDirectory("./testdata/_test")
+	err := os.MkdirAll(tempDirPath, os.ModePerm)
+	assert.NoError(t, err, "Should not happen")
+
+	c2pcrSpec := typec2pcr.Spec{
+		Compliance: typec2pcr.Compliance{
+			Name:                "Test Compliance",
+			Catalog:             typec2pcr.ResourceRef{Url: pkg.PathFromPkgDirectory("./testdata/ocm/catalog.json")},
+			Profile:             typec2pcr.ResourceRef{Url: pkg.PathFromPkgDirectory("./testdata/ocm/profile.json")},
+			ComponentDefinition: typec2pcr.ResourceRef{Url: pkg.PathFromPkgDirectory("./testdata/ocm/component-definition.json")},
+		},
+		PolicyResources: typec2pcr.ResourceRef{Url: pkg.PathFromPkgDirectory("./testdata/ocm/policies")},
+		ClusterGroups: []typec2pcr.ClusterGroup{{
+			Name:        "test-group",
+			MatchLabels: &map[string]string{"environment": "test"},
+		}},
+	}
+	c2pcrParser := ocm.NewParser(pkg.NewGitUtils(pkg.NewTempDirectory(tempDirPath)))
+	c2pcrParsed, err := c2pcrParser.Parse(c2pcrSpec)
+	assert.NoError(t, err, "Should not happen")
+
+	var arRoot typear.AssessmentResultsRoot
+	err = pkg.LoadOscalFileToObject(pkg.PathFromPkgDirectory("./testdata/ocm/assessment-results.json"), &arRoot)
+	assert.NoError(t, err, "Should not happen")
+
+	return NewOscal2Posture(c2pcrParsed, arRoot, nil, pkg.GetLogger("pvpcommon/oscal2posture"))
+}
+
+func TestOscal2PostureMarkdown(t *testing.T) {
+	r := newTestOscal2Posture(t)
+	data, err := r.Generate()
+	assert.NoError(t, err, "Should not happen")
+
+	report := string(data)
+	assert.Contains(t, report, "#### Result of control: cm-6 Configuration Settings")
+	assert.Contains(t, report, "Family: Configuration Management (cm)")
+	// Reasons are not HTML-escaped in markdown
+	assert.Contains(t, report, "couldn't find mapping resource")
+	assert.NotContains(t, report, "&#39;")
+}
+
+func TestOscal2PostureHtml(t *testing.T) {
+	r := newTestOscal2Posture(t)
+	r.SetFormat(PostureFormatHtml)
+	data, err := r.Generate()
+	assert.NoError(t, err, "Should not happen")
+
+	report := string(data)
+	assert.True(t, strings.HasPrefix(report, "<!DOCTYPE html>"))
+	assert.Contains(t, report, `<details class="control failing">`)
+	assert.Contains(t, report, "couldn&#39;t find mapping resource")
+}
+
+func TestOscal2PostureJson(t *testing.T) {
+	r := newTestOscal2Posture(t)
+	r.SetFormat(PostureFormatJson)
+	data, err := r.Generate()
+	assert.NoError(t, err, "Should not happen")
+
+	var templateValue tp.TemplateValue
+	err = json.Unmarshal(data, &templateValue)
+	assert.NoError(t, err, "Should not happen")
+	assert.Equal(t, 3, templateValue.Summary.Controls)
+	assert.Equal(t, 2, templateValue.Summary.FailingControls)
+	controlResult := templateValue.Components[0].ControlResults[0]
+	assert.Equal(t, "Configuration Settings", controlResult.ControlTitle)
+	failed := 0
+	for _, controlResult := range templateValue.Components[0].ControlResults {
+		failed += controlResult.Failed
+	}
+	assert.Equal(t, templateValue.Summary.Failed, failed)
+}
+
+func TestOscal2PostureCsv(t *testing.T) {
+	r := newTestOscal2Posture(t)
+	r.SetFormat(PostureFormatCsv)
+	data, err := r.Generate()
+	assert.NoError(t, err, "Should not happen")
+
+	records, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+	assert.NoError(t, err, "Should not happen")
+	assert.Equal(t, []string{"component", "control", "rule", "subject", "result", "reason"}, records[0])
+	assert.Equal(t, []string{"Managed Kubernetes", "cm-6", "test_configuration_check", "Cluster Name: cluster1", "fail"}, records[1][:5])
+}
+
+func TestParsePostureFormat(t *testing.T) {
+	format, err := ParsePostureFormat("csv")
+	assert.NoError(t, err, "Should not happen")
+	assert.Equal(t, PostureFormatCsv, format)
+
+	_, err = ParsePostureFormat("pdf")
+	assert.Error(t, err)
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pvpcommon
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"strings"
+	texttemplate "text/template"
+
+	tp "github.com/oscal-compass/compliance-to-policy/go/pkg/pvpcommon/template"
+)
+
+// PostureFormat is the format of the compliance posture.
+type PostureFormat string
+
+const (
+	// Markdown rendered by the template (default)
+	PostureFormatMarkdown PostureFormat = "markdown"
+	// Standalone HTML page with collapsible controls and subjects
+	PostureFormatHtml PostureFormat = "html"
+	// Summary and results per control in JSON
+	PostureFormatJson PostureFormat = "json"
+	// One row per subject (component, control, rule, subject, result, reason)
+	PostureFormatCsv PostureFormat = "csv"
+)
+
+var PostureFormats = []PostureFormat{PostureFormatMarkdown, PostureFormatHtml, PostureFormatJson, PostureFormatCsv}
+
+var csvHeader = []string{"component", "control", "rule", "subject", "result", "reason"}
+
+func ParsePostureFormat(s string) (PostureFormat, error) {
+	for _, format := range PostureFormats {
+		if string(format) == s {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported format %s: must be one of %v", s, PostureFormats)
+}
+
+// executor is either a text/template or an html/template.
+type executor interface {
+	Execute(wr io.Writer, data any) error
+}
+
+type templateParser func(name string, funcmap map[string]any, text string) (executor, error)
+
+// Markdown is rendered by text/template not to escape the reasons and the statements
+func parseMarkdownTemplate(name string, funcmap map[string]any, text string) (executor, error) {
+	return texttemplate.New(name).Funcs(funcmap).Parse(text)
+}
+
+func parseHtmlTemplate(name string, funcmap map[string]any, text string) (executor, error) {
+	return htmltemplate.New(name).Funcs(funcmap).Parse(text)
+}
+
+func (r *Oscal2Posture) renderTemplate(templateValue tp.TemplateValue, embeddedFile string, parse templateParser) ([]byte, error) {
+	var templateData []byte
+	var err error
+	if r.templateFile == nil {
+		templateData, err = embeddedResources.ReadFile(embeddedFile)
+	} else {
+		templateData, err = os.ReadFile(*r.templateFile)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	funcmap := map[string]any{
+		"newline_with_indent": func(text string, indent int) string {
+			newText := strings.ReplaceAll(text, "\n", "\n"+strings.Repeat(" ", indent))
+			return newText
+		},
+		"blockquote": func(text string) string {
+			return "> " + strings.ReplaceAll(text, "\n", "\n>\n> ")
+		},
+		"lower": strings.ToLower,
+	}
+
+	tmpl, err := parse("report", funcmap, string(templateData))
+	if err != nil {
+		return nil, err
+	}
+	buffer := bytes.NewBuffer([]byte{})
+	if err := tmpl.Execute(buffer, templateValue); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func renderJson(templateValue tp.TemplateValue) ([]byte, error) {
+	return json.MarshalIndent(templateValue, "", "  ")
+}
+
+func renderCsv(templateValue tp.TemplateValue) ([]byte, error) {
+	buffer := bytes.NewBuffer([]byte{})
+	writer := csv.NewWriter(buffer)
+	if err := writer.Write(csvHeader); err != nil {
+		return nil, err
+	}
+	writeRuleResults := func(componentTitle string, controlId string, ruleResults []tp.RuleResult) error {
+		for _, ruleResult := range ruleResults {
+			if len(ruleResult.Subjects) == 0 {
+				if err := writer.Write([]string{componentTitle, controlId, ruleResult.RuleId, "", "", ""}); err != nil {
+					return err
+				}
+			}
+			for _, subject := range ruleResult.Subjects {
+				if err := writer.Write([]string{componentTitle, controlId, ruleResult.RuleId, subject.Title, subject.Result, subject.Reason}); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	for _, component := range templateValue.Components {
+		for _, controlResult := range component.ControlResults {
+			if err := writeRuleResults(component.ComponentTitle, controlResult.ControlId, controlResult.RuleResults); err != nil {
+				return nil, err
+			}
+			for _, statementResult := range controlResult.StatementResults {
+				if err := writeRuleResults(component.ComponentTitle, statementResult.StatementId, statementResult.RuleResults); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
@@ -34,11 +34,36 @@ type RuleResult struct {
 	Subjects []Subject `json:"subjects,omitempty" yaml:"subjects,omitempty"`
 }
 
+type StatementResult struct {
+	// Statement ID
+	StatementId string `json:"statementId,omitempty" yaml:"statementId,omitempty"`
+	// Statement prose in the catalog with the parameter values inserted
+	Statement string `json:"statement,omitempty" yaml:"statement,omitempty"`
+	// Results per rule
+	RuleResults []RuleResult `json:"ruleResults,omitempty" yaml:"ruleResults,omitempty"`
+}
+
 type ControlResult struct {
 	// Control ID
 	ControlId string `json:"controlId,omitempty" yaml:"controlId,omitempty"`
-	// Results per rule
+	// Control title in catalog
+	ControlTitle string `json:"controlTitle,omitempty" yaml:"controlTitle,omitempty"`
+	// ID of the group (family) of the control in catalog
+	GroupId string `json:"groupId,omitempty" yaml:"groupId,omitempty"`
+	// Title of the group (family) of the control in catalog
+	GroupTitle string `json:"groupTitle,omitempty" yaml:"groupTitle,omitempty"`
+	// Statement prose in catalog with the parameter values inserted
+	Statement string `json:"statement,omitempty" yaml:"statement,omitempty"`
+	// Number of passed subjects of the control and its statements
+	Passed int `json:"passed" yaml:"passed"`
+	// Number of failed subjects of the control and its statements
+	Failed int `json:"failed" yaml:"failed"`
+	// Number of subjects of the control and its statements with the other results (e.g. error, skip)
+	Other int `json:"other" yaml:"other"`
+	// Results per rule implementing the whole control
 	RuleResults []RuleResult `json:"ruleResults,omitempty" yaml:"ruleResults,omitempty"`
+	// Results per statement of the control
+	StatementResults []StatementResult `json:"statementResults,omitempty" yaml:"statementResults,omitempty"`
 }
 
 type Component struct {
@@ -48,7 +73,21 @@ type Component struct {
 	ControlResults []ControlResult `json:"controlResults,omitempty" yaml:"controlResults,omitempty"`
 }
 
+type Summary struct {
+	// Number of controls having results
+	Controls int `json:"controls" yaml:"controls"`
+	// Number of controls having failed subjects
+	FailingControls int `json:"failingControls" yaml:"failingControls"`
+	// Number of passed subjects
+	Passed int `json:"passed" yaml:"passed"`
+	// Number of failed subjects
+	Failed int `json:"failed" yaml:"failed"`
+	// Number of subjects with the other results (e.g. error, skip)
+	Other int `json:"other" yaml:"other"`
+}
+
 type TemplateValue struct {
-	CatalogTitle string
-	Components   []Component
+	CatalogTitle string      `json:"catalogTitle,omitempty" yaml:"catalogTitle,omitempty"`
+	Summary      Summary     `json:"summary" yaml:"summary"`
+	Components   []Component `json:"components" yaml:"components"`
 }
@@ -28,6 +28,7 @@ type C2PCRParsed struct {
 	PolicyResultsDir    string
 	Catalog             typesoscal.CatalogRoot
 	Profile             typesoscal.ProfileRoot
+	ResolvedProfile     typesoscal.CatalogRoot
 	ComponentDefinition typecd.ComponentDefinitionRoot
 	ComponentObjects    []oscal.ComponentObject
 	ClusterSelectors    map[string]string
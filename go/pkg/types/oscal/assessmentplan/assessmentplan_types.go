@@ -0,0 +1,61 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assessmentplan
+
+import (
+	typear "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/assessmentresults"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/common"
+)
+
+type ImportSsp struct {
+	Href    string `json:"href"`
+	Remarks string `json:"remarks,omitempty"`
+}
+
+type IncludeAll struct{}
+
+type AssessmentSubject struct {
+	Type        string        `json:"type"`
+	Description string        `json:"description,omitempty"`
+	Props       []common.Prop `json:"props,omitempty"`
+	IncludeAll  *IncludeAll   `json:"include-all,omitempty"`
+	Remarks     string        `json:"remarks,omitempty"`
+}
+
+type Task struct {
+	UUID        string              `json:"uuid"`
+	Type        string              `json:"type"`
+	Title       string              `json:"title"`
+	Description string              `json:"description,omitempty"`
+	Props       []common.Prop       `json:"props,omitempty"`
+	Subjects    []AssessmentSubject `json:"subjects,omitempty"`
+	Remarks     string              `json:"remarks,omitempty"`
+}
+
+type AssessmentPlan struct {
+	UUID               string                 `json:"uuid"`
+	Metadata           typear.Metadata        `json:"metadata"`
+	ImportSsp          ImportSsp              `json:"import-ssp"`
+	ReviewedControls   typear.ReviewedControl `json:"reviewed-controls"`
+	AssessmentSubjects []AssessmentSubject    `json:"assessment-subjects,omitempty"`
+	Tasks              []Task                 `json:"tasks,omitempty"`
+	BackMatter         *common.BackMatter     `json:"back-matter,omitempty"`
+}
+
+type AssessmentPlanRoot struct {
+	AssessmentPlan AssessmentPlan `json:"assessment-plan"`
+}
@@ -37,11 +37,11 @@ type ImportAp struct {
 type InventoryItem struct {
 	UUID        string        `json:"uuid"`
 	Description string        `json:"description"`
-	Props       []common.Prop `json:"props"`
+	Props       []common.Prop `json:"props,omitempty"`
 }
 
 type LocalDefinitions struct {
-	InventoryItems []InventoryItem `json:"inventory-items"`
+	InventoryItems []InventoryItem `json:"inventory-items,omitempty"`
 }
 
 type SelectControlById struct {
@@ -50,7 +50,7 @@ type SelectControlById struct {
 }
 
 type ControlSelection struct {
-	IncludeControls []SelectControlById `json:"include-controls"`
+	IncludeControls []SelectControlById `json:"include-controls,omitempty"`
 }
 
 type ControlObjectiveSelection struct {
@@ -70,11 +70,11 @@ type Subject struct {
 	SubjectUUID string        `json:"subject-uuid"`
 	Type        string        `json:"type"`
 	Title       string        `json:"title"`
-	Props       []common.Prop `json:"props"`
+	Props       []common.Prop `json:"props,omitempty"`
 }
 
 type Actor struct {
-	Type      string        `json:"type,omitempty"`
+	Type      string        `json:"type"`
 	ActorUUID string        `json:"actor-uuid"`
 	RoleId    string        `json:"role-id,omitempty"`
 	Props     []common.Prop `json:"props,omitempty"`
@@ -82,7 +82,7 @@ type Actor struct {
 }
 
 type Origin struct {
-	Actors Actor `json:"actors"`
+	Actors []Actor `json:"actors"`
 }
 
 type Observation struct {
@@ -97,26 +97,83 @@ type Observation struct {
 	Subjects         []Subject                 `json:"subjects,omitempty"`
 	RelevantEvidence []common.RelevantEvidence `json:"relevant-evidence,omitempty"`
 	Collected        time.Time                 `json:"collected"`
-	Expires          time.Time                 `json:"expires,omitempty"`
-	Remarks          string                    `json:"markup-multiline,omitempty"`
+	Expires          *time.Time                `json:"expires,omitempty"`
+	Remarks          string                    `json:"remarks,omitempty"`
+}
+
+type RelatedObservation struct {
+	ObservationUUID string `json:"observation-uuid"`
+}
+
+type ObjectiveStatus struct {
+	State   string `json:"state"`
+	Reason  string `json:"reason,omitempty"`
+	Remarks string `json:"remarks,omitempty"`
+}
+
+type FindingTarget struct {
+	Type        string          `json:"type"`
+	TargetId    string          `json:"target-id"`
+	Title       string          `json:"title,omitempty"`
+	Description string          `json:"description,omitempty"`
+	Props       []common.Prop   `json:"props,omitempty"`
+	Links       []common.Link   `json:"links,omitempty"`
+	Status      ObjectiveStatus `json:"status"`
+	Remarks     string          `json:"remarks,omitempty"`
+}
+
+type Finding struct {
+	UUID                        string               `json:"uuid"`
+	Title                       string               `json:"title"`
+	Description                 string               `json:"description"`
+	Props                       []common.Prop        `json:"props,omitempty"`
+	Links                       []common.Link        `json:"links,omitempty"`
+	Origins                     []Origin             `json:"origins,omitempty"`
+	Target                      FindingTarget        `json:"target"`
+	ImplementationStatementUUID string               `json:"implementation-statement-uuid,omitempty"`
+	RelatedObservations         []RelatedObservation `json:"related-observations,omitempty"`
+	Remarks                     string               `json:"remarks,omitempty"`
+}
+
+type Response struct {
+	UUID        string        `json:"uuid"`
+	Lifecycle   string        `json:"lifecycle"`
+	Title       string        `json:"title"`
+	Description string        `json:"description"`
+	Props       []common.Prop `json:"props,omitempty"`
+	Remarks     string        `json:"remarks,omitempty"`
+}
+
+type Risk struct {
+	UUID                string               `json:"uuid"`
+	Title               string               `json:"title"`
+	Description         string               `json:"description"`
+	Statement           string               `json:"statement"`
+	Props               []common.Prop        `json:"props,omitempty"`
+	Status              string               `json:"status"`
+	Deadline            *time.Time           `json:"deadline,omitempty"`
+	Remediations        []Response           `json:"remediations,omitempty"`
+	RelatedObservations []RelatedObservation `json:"related-observations,omitempty"`
 }
 
 type Result struct {
-	UUID             string            `json:"uuid"`
-	Title            string            `json:"title"`
-	Description      string            `json:"description"`
-	Start            time.Time         `json:"start"`
-	Props            []interface{}     `json:"props,omitempty"`
-	LocalDefinitions LocalDefinitions  `json:"local-definitions,omitempty"`
-	ReviewedControls []ReviewedControl `json:"reviewed-controls,omitempty"`
-	Observations     []Observation     `json:"observations,omitempty"`
+	UUID             string           `json:"uuid"`
+	Title            string           `json:"title"`
+	Description      string           `json:"description"`
+	Start            time.Time        `json:"start"`
+	Props            []interface{}    `json:"props,omitempty"`
+	LocalDefinitions LocalDefinitions `json:"local-definitions,omitempty"`
+	ReviewedControls ReviewedControl  `json:"reviewed-controls"`
+	Observations     []Observation    `json:"observations,omitempty"`
+	Findings         []Finding        `json:"findings,omitempty"`
 }
 
 type AssessmentResults struct {
-	UUID     string   `json:"uuid"`
-	Metadata Metadata `json:"metadata"`
-	ImportAp ImportAp `json:"import-ap"`
-	Results  []Result `json:"results"`
+	UUID       string             `json:"uuid"`
+	Metadata   Metadata           `json:"metadata"`
+	ImportAp   ImportAp           `json:"import-ap"`
+	Results    []Result           `json:"results"`
+	BackMatter *common.BackMatter `json:"back-matter,omitempty"`
 }
 
 type AssessmentResultsRoot struct {
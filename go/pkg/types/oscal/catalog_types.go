@@ -16,6 +16,8 @@ limitations under the License.
 
 package oscal
 
+import "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/common"
+
 type CatalogRoot struct {
 	Catalog `json:"catalog"`
 }
@@ -65,133 +67,72 @@ type CatalogResource struct {
 		Href string `json:"href,omitempty"`
 	} `json:"rlinks,omitempty"`
 }
+type BackMatter struct {
+	Resources []CatalogResource `json:"resources,omitempty"`
+}
+
 type Catalog struct {
 	UUID       string          `json:"uuid"`
 	Metadata   CatalogMetadata `json:"metadata,omitempty"`
+	Params     []Parameter     `json:"params,omitempty"`
+	Controls   []Control       `json:"controls,omitempty"`
 	Groups     []Group         `json:"groups,omitempty"`
-	BackMatter struct {
-		Resources []CatalogResource `json:"resources,omitempty"`
-	} `json:"back-matter,omitempty"`
+	BackMatter BackMatter      `json:"back-matter,omitempty"`
 }
 
 type Group struct {
-	ID       string    `json:"id"`
-	Class    string    `json:"class"`
-	Title    string    `json:"title"`
-	Controls []Control `json:"controls"`
-	Groups   []Group   `json:"groups"`
-	Parts    []struct {
-		ID    string `json:"id,omitempty"`
-		Name  string `json:"name,omitempty"`
-		Title string `json:"title,omitempty"`
-		Prose string `json:"prose,omitempty"`
-	} `json:"parts,omitempty"`
+	ID       string        `json:"id"`
+	Class    string        `json:"class"`
+	Title    string        `json:"title"`
+	Params   []Parameter   `json:"params,omitempty"`
+	Props    []common.Prop `json:"props,omitempty"`
+	Parts    []Part        `json:"parts,omitempty"`
+	Controls []Control     `json:"controls"`
+	Groups   []Group       `json:"groups"`
 }
 
 type Control struct {
-	ID     string `json:"id"`
-	Class  string `json:"class"`
-	Title  string `json:"title"`
-	Params []struct {
-		ID    string `json:"id"`
-		Props []struct {
-			Name  string `json:"name,omitempty"`
-			Ns    string `json:"ns,omitempty"`
-			Value string `json:"value,omitempty"`
-		} `json:"props,omitempty"`
-		Label      string `json:"label,omitempty"`
-		Guidelines []struct {
-			Prose string `json:"prose,omitempty"`
-		} `json:"guidelines,omitempty"`
-		Select struct {
-			HowMany string   `json:"how-many,omitempty"`
-			Choice  []string `json:"choice,omitempty"`
-		} `json:"select,omitempty"`
-	} `json:"params,omitempty"`
-	Props []struct {
-		Name  string `json:"name"`
-		Value string `json:"value"`
-		Class string `json:"class,omitempty"`
-	} `json:"props,omitempty"`
-	Links []struct {
-		Href string `json:"href"`
-		Rel  string `json:"rel"`
-	} `json:"links,omitempty"`
-	Parts []struct {
-		ID    string `json:"id"`
-		Name  string `json:"name"`
-		Parts []struct {
-			ID    string `json:"id"`
-			Name  string `json:"name"`
-			Props []struct {
-				Name  string `json:"name"`
-				Value string `json:"value"`
-			} `json:"props"`
-			Prose string `json:"prose"`
-			Parts []struct {
-				ID    string `json:"id"`
-				Name  string `json:"name"`
-				Props []struct {
-					Name  string `json:"name"`
-					Value string `json:"value"`
-				} `json:"props"`
-				Prose string `json:"prose"`
-				Parts []struct {
-					ID    string `json:"id"`
-					Name  string `json:"name"`
-					Props []struct {
-						Name  string `json:"name"`
-						Value string `json:"value"`
-					} `json:"props"`
-					Prose string `json:"prose"`
-				} `json:"parts,omitempty"`
-			} `json:"parts,omitempty"`
-		} `json:"parts,omitempty"`
-		Prose string `json:"prose,omitempty"`
-		Props []struct {
-			Name  string `json:"name"`
-			Value string `json:"value"`
-			Class string `json:"class"`
-		} `json:"props,omitempty"`
-	} `json:"parts,omitempty"`
-	Controls []struct {
-		ID     string `json:"id"`
-		Class  string `json:"class"`
-		Title  string `json:"title"`
-		Params []struct {
-			ID    string `json:"id"`
-			Props []struct {
-				Name  string `json:"name"`
-				Value string `json:"value"`
-				Class string `json:"class,omitempty"`
-			} `json:"props"`
-			Label      string `json:"label"`
-			Guidelines []struct {
-				Prose string `json:"prose"`
-			} `json:"guidelines"`
-		} `json:"params,omitempty"`
-		Props []struct {
-			Name  string `json:"name"`
-			Value string `json:"value"`
-			Class string `json:"class,omitempty"`
-		} `json:"props"`
-		Links []struct {
-			Href string `json:"href"`
-			Rel  string `json:"rel"`
-		} `json:"links"`
-		Parts []struct {
-			ID    string `json:"id"`
-			Name  string `json:"name"`
-			Prose string `json:"prose,omitempty"`
-			Props []struct {
-				Name  string `json:"name"`
-				Value string `json:"value"`
-				Class string `json:"class"`
-			} `json:"props,omitempty"`
-			Parts []struct {
-				Name  string `json:"name"`
-				Prose string `json:"prose"`
-			} `json:"parts,omitempty"`
-		} `json:"parts,omitempty"`
-	} `json:"controls,omitempty"`
+	ID       string        `json:"id"`
+	Class    string        `json:"class"`
+	Title    string        `json:"title"`
+	Params   []Parameter   `json:"params,omitempty"`
+	Props    []common.Prop `json:"props,omitempty"`
+	Links    []common.Link `json:"links,omitempty"`
+	Parts    []Part        `json:"parts,omitempty"`
+	Controls []Control     `json:"controls,omitempty"`
+}
+
+type Part struct {
+	ID    string        `json:"id,omitempty"`
+	Name  string        `json:"name"`
+	Ns    string        `json:"ns,omitempty"`
+	Class string        `json:"class,omitempty"`
+	Title string        `json:"title,omitempty"`
+	Props []common.Prop `json:"props,omitempty"`
+	Prose string        `json:"prose,omitempty"`
+	Parts []Part        `json:"parts,omitempty"`
+	Links []common.Link `json:"links,omitempty"`
+}
+
+type Parameter struct {
+	ID         string              `json:"id"`
+	Class      string              `json:"class,omitempty"`
+	DependsOn  string              `json:"depends-on,omitempty"`
+	Props      []common.Prop       `json:"props,omitempty"`
+	Links      []common.Link       `json:"links,omitempty"`
+	Label      string              `json:"label,omitempty"`
+	Usage      string              `json:"usage,omitempty"`
+	Guidelines []Guideline         `json:"guidelines,omitempty"`
+	Values     []string            `json:"values,omitempty"`
+	Select     *ParameterSelection `json:"select,omitempty"`
+	Remarks    string              `json:"remarks,omitempty"`
+}
+
+type Guideline struct {
+	Prose string `json:"prose,omitempty"`
+}
+
+type ParameterSelection struct {
+	HowMany string   `json:"how-many,omitempty"`
+	Choice  []string `json:"choice,omitempty"`
 }
@@ -33,9 +33,26 @@ type Link struct {
 }
 
 type RelevantEvidence struct {
-	Href        string   `json:"href,omitempty"`
-	Description string   `json:"description,omitempty"`
-	Props       []Prop   `json:"props,omitempty"`
-	Links       []Link   `json:"links,omitempty"`
-	Remarks     []string `json:"remarks,omitempty"`
+	Href        string `json:"href,omitempty"`
+	Description string `json:"description,omitempty"`
+	Props       []Prop `json:"props,omitempty"`
+	Links       []Link `json:"links,omitempty"`
+	Remarks     string `json:"remarks,omitempty"`
+}
+
+type Rlink struct {
+	Href      string `json:"href"`
+	MediaType string `json:"media-type,omitempty"`
+}
+
+type Resource struct {
+	UUID        string  `json:"uuid"`
+	Title       string  `json:"title,omitempty"`
+	Description string  `json:"description,omitempty"`
+	Props       []Prop  `json:"props,omitempty"`
+	Rlinks      []Rlink `json:"rlinks,omitempty"`
+}
+
+type BackMatter struct {
+	Resources []Resource `json:"resources,omitempty"`
 }
@@ -25,7 +25,7 @@ type ComponentDefinitionRoot struct {
 type ComponentDefinition struct {
 	UUID       string      `json:"uuid"`
 	Metadata   Metadata    `json:"metadata"`
-	Components []Component `json:"components"`
+	Components []Component `json:"components,omitempty"`
 }
 
 type Metadata struct {
@@ -40,16 +40,16 @@ type Component struct {
 	Type                   string                  `json:"type"`
 	Title                  string                  `json:"title"`
 	Description            string                  `json:"description"`
-	Props                  []Prop                  `json:"props"`
-	ControlImplementations []ControlImplementation `json:"control-implementations"`
+	Props                  []Prop                  `json:"props,omitempty"`
+	ControlImplementations []ControlImplementation `json:"control-implementations,omitempty"`
 }
 
 type ControlImplementation struct {
 	UUID                    string                   `json:"uuid"`
 	Source                  string                   `json:"source"`
 	Description             string                   `json:"description"`
-	Props                   []Prop                   `json:"props"`
-	SetParameters           []SetParameter           `json:"set-parameters"`
+	Props                   []Prop                   `json:"props,omitempty"`
+	SetParameters           []SetParameter           `json:"set-parameters,omitempty"`
 	ImplementedRequirements []ImplementedRequirement `json:"implemented-requirements"`
 }
 
@@ -57,23 +57,23 @@ type ImplementedRequirement struct {
 	UUID        string      `json:"uuid"`
 	ControlID   string      `json:"control-id"`
 	Description string      `json:"description"`
-	Props       []Prop      `json:"props"`
+	Props       []Prop      `json:"props,omitempty"`
 	Statements  []Statement `json:"statements,omitempty"`
 }
 
 type Statement struct {
 	StatementId string `json:"statement-id"`
 	UUID        string `json:"uuid"`
-	Description string `json:"description,omitempty"`
+	Description string `json:"description"`
 	Props       []Prop `json:"props,omitempty"`
 }
 
 type Prop struct {
 	Name    string `json:"name"`
-	Ns      string `json:"ns"`
+	Ns      string `json:"ns,omitempty"`
 	Value   string `json:"value"`
 	Class   string `json:"class,omitempty"`
-	Remarks string `json:"remarks"`
+	Remarks string `json:"remarks,omitempty"`
 }
 
 type SetParameter struct {
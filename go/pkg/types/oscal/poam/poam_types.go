@@ -0,0 +1,50 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package poam
+
+import (
+	typear "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/assessmentresults"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/common"
+)
+
+type RelatedRisk struct {
+	RiskUUID string `json:"risk-uuid"`
+}
+
+type PoamItem struct {
+	UUID                string                      `json:"uuid"`
+	Title               string                      `json:"title"`
+	Description         string                      `json:"description"`
+	Props               []common.Prop               `json:"props,omitempty"`
+	Links               []common.Link               `json:"links,omitempty"`
+	RelatedObservations []typear.RelatedObservation `json:"related-observations,omitempty"`
+	RelatedRisks        []RelatedRisk               `json:"related-risks,omitempty"`
+	Remarks             string                      `json:"remarks,omitempty"`
+}
+
+type PlanOfActionAndMilestones struct {
+	UUID         string               `json:"uuid"`
+	Metadata     typear.Metadata      `json:"metadata"`
+	Observations []typear.Observation `json:"observations,omitempty"`
+	Risks        []typear.Risk        `json:"risks,omitempty"`
+	PoamItems    []PoamItem           `json:"poam-items"`
+	BackMatter   *common.BackMatter   `json:"back-matter,omitempty"`
+}
+
+type PlanOfActionAndMilestonesRoot struct {
+	PlanOfActionAndMilestones PlanOfActionAndMilestones `json:"plan-of-action-and-milestones"`
+}
@@ -16,6 +16,8 @@ limitations under the License.
 
 package oscal
 
+import "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/common"
+
 type ProfileRoot struct {
 	Profile `json:"profile"`
 }
@@ -48,17 +50,87 @@ type ProfileMetadata struct {
 }
 
 type ProfileImport struct {
-	Href            string `json:"href"`
-	IncludeControls []struct {
-		WithIds []string `json:"with-ids"`
-	} `json:"include-controls"`
+	Href            string                     `json:"href"`
+	IncludeAll      *IncludeAll                `json:"include-all,omitempty"`
+	IncludeControls []ProfileSelectControlById `json:"include-controls,omitempty"`
+	ExcludeControls []ProfileSelectControlById `json:"exclude-controls,omitempty"`
+}
+
+type IncludeAll struct{}
+
+type ProfileSelectControlById struct {
+	// "yes" to select the descendant controls of the selected controls as well
+	WithChildControls string            `json:"with-child-controls,omitempty"`
+	WithIds           []string          `json:"with-ids,omitempty"`
+	Matching          []ProfileMatching `json:"matching,omitempty"`
+}
+
+type ProfileMatching struct {
+	// Glob pattern matched against control IDs
+	Pattern string `json:"pattern,omitempty"`
+}
+
+type ProfileMerge struct {
+	AsIs    bool            `json:"as-is,omitempty"`
+	Flat    *ProfileFlat    `json:"flat,omitempty"`
+	Combine *ProfileCombine `json:"combine,omitempty"`
+}
+
+type ProfileFlat struct{}
+
+type ProfileCombine struct {
+	// One of "use-first" (default), "merge" or "keep"
+	Method string `json:"method,omitempty"`
+}
+
+type ProfileModify struct {
+	SetParameters []ProfileSetParameter `json:"set-parameters,omitempty"`
+	Alters        []ProfileAlter        `json:"alters,omitempty"`
+}
+
+type ProfileSetParameter struct {
+	ParamID    string              `json:"param-id"`
+	Class      string              `json:"class,omitempty"`
+	DependsOn  string              `json:"depends-on,omitempty"`
+	Props      []common.Prop       `json:"props,omitempty"`
+	Links      []common.Link       `json:"links,omitempty"`
+	Label      string              `json:"label,omitempty"`
+	Usage      string              `json:"usage,omitempty"`
+	Guidelines []Guideline         `json:"guidelines,omitempty"`
+	Values     []string            `json:"values,omitempty"`
+	Select     *ParameterSelection `json:"select,omitempty"`
+}
+
+type ProfileAlter struct {
+	ControlID string          `json:"control-id"`
+	Removes   []ProfileRemove `json:"removes,omitempty"`
+	Adds      []ProfileAdd    `json:"adds,omitempty"`
+}
+
+type ProfileRemove struct {
+	ByName     string `json:"by-name,omitempty"`
+	ByClass    string `json:"by-class,omitempty"`
+	ById       string `json:"by-id,omitempty"`
+	ByItemName string `json:"by-item-name,omitempty"`
+	ByNs       string `json:"by-ns,omitempty"`
+}
+
+type ProfileAdd struct {
+	// One of "starting", "ending" (default), "before" or "after"
+	Position string        `json:"position,omitempty"`
+	ById     string        `json:"by-id,omitempty"`
+	Title    string        `json:"title,omitempty"`
+	Params   []Parameter   `json:"params,omitempty"`
+	Props    []common.Prop `json:"props,omitempty"`
+	Links    []common.Link `json:"links,omitempty"`
+	Parts    []Part        `json:"parts,omitempty"`
 }
 
 type Profile struct {
-	UUID     string          `json:"uuid"`
-	Metadata ProfileMetadata `json:"metadata"`
-	Imports  []ProfileImport `json:"imports"`
-	Merge    struct {
-		AsIs bool `json:"as-is"`
-	} `json:"merge"`
+	UUID       string          `json:"uuid"`
+	Metadata   ProfileMetadata `json:"metadata"`
+	Imports    []ProfileImport `json:"imports"`
+	Merge      ProfileMerge    `json:"merge"`
+	Modify     *ProfileModify  `json:"modify,omitempty"`
+	BackMatter *BackMatter     `json:"back-matter,omitempty"`
 }
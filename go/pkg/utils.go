@@ -24,12 +24,16 @@ import (
 	"os"
 	"path"
 	"runtime"
+	"sync"
 
 	k8sruntime "k8s.io/apimachinery/pkg/runtime"
 
 	"go.uber.org/zap"
 	"gopkg.in/yaml.v3"
 
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal/format"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal/validation"
+
 	goyaml "gopkg.in/yaml.v3"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	k8syaml "k8s.io/apimachinery/pkg/runtime/serializer/yaml"
@@ -39,6 +43,9 @@ import (
 
 var logger *zap.Logger
 
+var strictOscalValidation = false
+var warnedMissingSchemas sync.Map
+
 const (
 	ANNOTATION_COMPONENT_TITLE string = "compliance-to-policy.component-title"
 )
@@ -168,6 +175,68 @@ func LoadJsonFileToObject(path string, out interface{}) error {
 	return nil
 }
 
+// Make the OSCAL documents loaded and written fail on schema violations or missing schemas.
+// By default, they are logged as warnings.
+func SetStrictOscalValidation(strict bool) {
+	strictOscalValidation = strict
+}
+
+func validateOscal(jsonData []byte) error {
+	if !validation.IsSupported(jsonData) {
+		return nil
+	}
+	err := validation.Validate(jsonData)
+	if err == nil || strictOscalValidation {
+		return err
+	}
+	if errors.Is(err, validation.ErrSchemaNotFound) {
+		if _, warned := warnedMissingSchemas.LoadOrStore(err.Error(), true); !warned {
+			GetLogger("validation").Warn("OSCAL document is not validated", zap.Error(err))
+		}
+		return nil
+	}
+	GetLogger("validation").Warn("OSCAL document is not valid against the OSCAL schema", zap.Error(err))
+	return nil
+}
+
+// Read an OSCAL document in JSON, YAML or XML. The format is detected by the file extension or the content.
+func LoadOscalFileToObject(path string, out interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return UnmarshalOscal(data, format.Detect(path, data), out)
+}
+
+// Decode an OSCAL document in the format. The document is validated against the OSCAL schemas (see SetStrictOscalValidation).
+func UnmarshalOscal(data []byte, inputFormat format.Format, out interface{}) error {
+	jsonData, err := format.ToJson(data, inputFormat)
+	if err != nil {
+		return err
+	}
+	if err := validateOscal(jsonData); err != nil {
+		return err
+	}
+	return json.Unmarshal(jsonData, out)
+}
+
+// Write an OSCAL document in the format (json, yaml or xml). The document is validated against the OSCAL schemas
+// before written (see SetStrictOscalValidation).
+func WriteOscalObjToFile(path string, in interface{}, outputFormat format.Format) error {
+	jsonData, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	if err := validateOscal(jsonData); err != nil {
+		return err
+	}
+	data, err := format.Marshal(in, outputFormat)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, os.ModePerm)
+}
+
 func LoadYamlFileToK8sTypedObject(path string, out interface{}) error {
 	yamlData, err := os.ReadFile(path)
 	if err != nil {
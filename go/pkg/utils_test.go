@@ -0,0 +1,89 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pkg
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal/format"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal/validation"
+	typear "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/assessmentresults"
+	typecd "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/componentdefinition"
+)
+
+func TestLoadOscalFileToObjectWithValidation(t *testing.T) {
+	var cdRoot typecd.ComponentDefinitionRoot
+	err := LoadOscalFileToObject(PathFromPkgDirectory("./testdata/kyverno/component-definition.json"), &cdRoot)
+	assert.NoError(t, err, "Should not happen")
+	assert.Len(t, cdRoot.ComponentDefinition.Components, 2)
+
+	tempDir := NewTempDirectory(PathFromPkgDirectory("./testdata/_test"))
+	path := tempDir.GetTempDir() + "/component-definition.yaml"
+	err = os.WriteFile(path, []byte(`component-definition:
+  uuid: c8106bc8-5174-4e86-91a4-52f2fe0ed027
+  metadata:
+    title: Component Definition without version
+    last-modified: "2023-12-05T16:10:04+09:00"
+    oscal-version: 1.1.2
+`), os.ModePerm)
+	assert.NoError(t, err, "Should not happen")
+
+	// Schema violations are warned by default
+	err = LoadOscalFileToObject(path, &cdRoot)
+	assert.NoError(t, err, "Should not happen")
+	assert.Equal(t, "Component Definition without version", cdRoot.ComponentDefinition.Metadata.Title)
+
+	SetStrictOscalValidation(true)
+	defer SetStrictOscalValidation(false)
+	err = LoadOscalFileToObject(path, &cdRoot)
+	if !validation.HasSchema("component-definition") {
+		assert.ErrorIs(t, err, validation.ErrSchemaNotFound)
+		return
+	}
+	var validationErrs *validation.ValidationErrors
+	assert.True(t, errors.As(err, &validationErrs))
+	assert.Equal(t, "/component-definition/metadata", validationErrs.Errors[0].InstanceLocation)
+}
+
+func TestWriteOscalObjToFileWithValidation(t *testing.T) {
+	var arRoot typear.AssessmentResultsRoot
+	err := LoadOscalFileToObject(PathFromPkgDirectory("./testdata/ocm/assessment-results.json"), &arRoot)
+	assert.NoError(t, err, "Should not happen")
+
+	tempDir := NewTempDirectory(PathFromPkgDirectory("./testdata/_test"))
+	err = WriteOscalObjToFile(tempDir.GetTempDir()+"/assessment-results.yaml", arRoot, format.YAML)
+	assert.NoError(t, err, "Should not happen")
+
+	arRoot.AssessmentResults.Results[0].UUID = "50436d5c-933d-11ee-a9ee-62f79297f1b7"
+	err = WriteOscalObjToFile(tempDir.GetTempDir()+"/invalid-assessment-results.json", arRoot, format.JSON)
+	assert.NoError(t, err, "Should not happen")
+
+	SetStrictOscalValidation(true)
+	defer SetStrictOscalValidation(false)
+	err = WriteOscalObjToFile(tempDir.GetTempDir()+"/invalid-assessment-results.json", arRoot, format.JSON)
+	if !validation.HasSchema("assessment-results") {
+		assert.ErrorIs(t, err, validation.ErrSchemaNotFound)
+		return
+	}
+	var validationErrs *validation.ValidationErrors
+	assert.True(t, errors.As(err, &validationErrs))
+	assert.Equal(t, "/assessment-results/results/0/uuid", validationErrs.Errors[0].InstanceLocation)
+}
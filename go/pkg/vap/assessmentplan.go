@@ -0,0 +1,56 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vap
+
+import (
+	"slices"
+
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal"
+	typec2pcr "github.com/oscal-compass/compliance-to-policy/go/pkg/types/c2pcr"
+	typeap "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/assessmentplan"
+)
+
+// GenerateAssessmentPlan returns the assessment plan of the ValidatingAdmissionPolicies of the C2P config.
+// The subject is the whole cluster since the bindings select the resources of the cluster.
+func GenerateAssessmentPlan(c2pParsed typec2pcr.C2PCRParsed, cdHref string, stamper oscal.Stamper) (*typeap.AssessmentPlanRoot, error) {
+	policyNames := []string{}
+	for _, componentObject := range c2pParsed.ComponentObjects {
+		if componentObject.ComponentType == "validation" {
+			continue
+		}
+		for _, ruleObject := range componentObject.RuleObjects {
+			for _, policyName := range ruleObject.PolicyIds(true) {
+				if !slices.Contains(policyNames, policyName) {
+					policyNames = append(policyNames, policyName)
+				}
+			}
+		}
+	}
+	slices.Sort(policyNames)
+
+	task := oscal.AssessmentPlanTask{
+		PVP:         "vap",
+		Description: "ValidatingAdmissionPolicies validate the requests to the Kubernetes API server and record the results in the audit events",
+		PolicyIds:   policyNames,
+		Subjects: []typeap.AssessmentSubject{{
+			Type:        "inventory-item",
+			Description: "Kubernetes resources matched by the ValidatingAdmissionPolicyBindings",
+			IncludeAll:  &typeap.IncludeAll{},
+		}},
+	}
+	return oscal.GenerateAssessmentPlan(c2pParsed.ComponentDefinition, c2pParsed.ComponentObjects, cdHref, []oscal.AssessmentPlanTask{task}, stamper)
+}
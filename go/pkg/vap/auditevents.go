@@ -0,0 +1,114 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vap
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+)
+
+// Annotation of the audit events in which the API server records the validation failures of the policies whose bindings have the Audit action
+const ValidationFailureAnnotation = "validation.policy.admission.k8s.io/validation_failure"
+
+// ValidationFailure is an element of the value of the validation failure annotation
+type ValidationFailure struct {
+	Message           string                                     `json:"message"`
+	Policy            string                                     `json:"policy"`
+	Binding           string                                     `json:"binding"`
+	ExpressionIndex   int                                        `json:"expressionIndex"`
+	ValidationActions []admissionregistrationv1.ValidationAction `json:"validationActions"`
+}
+
+// LoadAuditEvents returns the audit events in the file or in the files of the directory.
+// A file is an audit log written by the log backend of the API server (an event per line) or an EventList.
+func LoadAuditEvents(path string) ([]auditv1.Event, error) {
+	events := []auditv1.Event{}
+	err := filepath.Walk(path, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		decoded, err := decodeAuditEvents(data)
+		if err != nil {
+			return fmt.Errorf("failed to load audit events from %s: %w", path, err)
+		}
+		events = append(events, decoded...)
+		return nil
+	})
+	return events, err
+}
+
+func decodeAuditEvents(data []byte) ([]auditv1.Event, error) {
+	events := []auditv1.Event{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	for {
+		var raw json.RawMessage
+		err := decoder.Decode(&raw)
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		var typeMeta struct {
+			Kind string `json:"kind"`
+		}
+		if err := json.Unmarshal(raw, &typeMeta); err != nil {
+			return nil, err
+		}
+		if typeMeta.Kind == "EventList" {
+			var eventList auditv1.EventList
+			if err := json.Unmarshal(raw, &eventList); err != nil {
+				return nil, err
+			}
+			events = append(events, eventList.Items...)
+			continue
+		}
+		var event auditv1.Event
+		if err := json.Unmarshal(raw, &event); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// validationFailures returns the validation failures recorded in the audit event
+func validationFailures(event auditv1.Event) ([]ValidationFailure, error) {
+	failures := []ValidationFailure{}
+	value, ok := event.Annotations[ValidationFailureAnnotation]
+	if !ok {
+		return failures, nil
+	}
+	if err := json.Unmarshal([]byte(value), &failures); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation of audit event %s: %w", ValidationFailureAnnotation, event.AuditID, err)
+	}
+	return failures, nil
+}
@@ -0,0 +1,39 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vap
+
+import (
+	"github.com/oscal-compass/compliance-to-policy/go/pkg"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/kyverno"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/types/c2pcr"
+)
+
+// C2PCRParser parses the c2p config as the Kyverno parser does, since the policy resources of both are directories of the policies
+type C2PCRParser struct {
+	kyverno.C2PCRParser
+}
+
+func NewParser(gitUtils pkg.GitUtils) C2PCRParser {
+	return C2PCRParser{C2PCRParser: kyverno.NewParser(gitUtils)}
+}
+
+func (p *C2PCRParser) Parse(c2pcrSpec c2pcr.Spec) (c2pcr.C2PCRParsed, error) {
+	parsed, err := p.C2PCRParser.Parse(c2pcrSpec)
+	// Namespace of the ConfigMaps of the parameters
+	parsed.Namespace = c2pcrSpec.Target.Namespace
+	return parsed, err
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vap
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/oscal-compass/compliance-to-policy/go/pkg"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal"
+	typec2pcr "github.com/oscal-compass/compliance-to-policy/go/pkg/types/c2pcr"
+	cp "github.com/otiai10/copy"
+	"go.uber.org/zap"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Namespace of the ConfigMaps of the parameters if the c2p config has no target namespace
+const DefaultParamsNamespace = "default"
+
+// Key of the audit annotation added to the policies with the IDs of the rules referring to the policy joined by comma.
+// The API server records it as <policy name>/c2p-rule in the audit events of the requests evaluated by the policy,
+// by which result2oscal finds the passed requests.
+const RuleAuditAnnotationKey = "c2p-rule"
+
+type Oscal2Policy struct {
+	policiesDir string
+	tempDir     pkg.TempDirectory
+	logger      *zap.Logger
+}
+
+func NewOscal2Policy(policiesDir string, tempDir pkg.TempDirectory) *Oscal2Policy {
+	return &Oscal2Policy{
+		policiesDir: policiesDir,
+		tempDir:     tempDir,
+		logger:      pkg.GetLogger("vap/composer"),
+	}
+}
+
+// Generate generates a ValidatingAdmissionPolicy, a ValidatingAdmissionPolicyBinding and a ConfigMap of the parameters (if the rules have parameters)
+// for each policy in the policy resources of the rules into the directory of the policy resources. A policy referred to by multiple rules
+// is generated once with the parameters and the scope of all the rules.
+func (c *Oscal2Policy) Generate(c2pParsed typec2pcr.C2PCRParsed) error {
+	namespace := c2pParsed.Namespace
+	if namespace == "" {
+		namespace = DefaultParamsNamespace
+	}
+	policyObjects, err := oscal.PolicyObjects(c2pParsed.ComponentObjects)
+	if err != nil {
+		return err
+	}
+	for _, policyObject := range policyObjects {
+		sourceDir := fmt.Sprintf("%s/%s", c.policiesDir, policyObject.PolicyId)
+		policies, err := loadPolicies(sourceDir, c.logger)
+		if err != nil {
+			return err
+		}
+		if len(policies) == 0 {
+			return fmt.Errorf("no %s is found in %s for rule %s", policyKind, sourceDir, ruleIdsOf(policyObject))
+		}
+		destDir, err := pkg.MakeDir(fmt.Sprintf("%s/%s", c.tempDir.GetTempDir(), policyObject.PolicyId))
+		if err != nil {
+			return err
+		}
+		for _, policy := range policies {
+			if err := c.generatePolicy(destDir, policy, policyObject, namespace); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ruleIdsOf returns the IDs of the rules referring to the policy joined by comma
+func ruleIdsOf(policyObject oscal.PolicyObject) string {
+	ruleIds := []string{}
+	for _, ruleObject := range policyObject.RuleObjects {
+		ruleIds = append(ruleIds, ruleObject.RuleId)
+	}
+	return strings.Join(ruleIds, ",")
+}
+
+func (c *Oscal2Policy) generatePolicy(destDir string, policy admissionregistrationv1.ValidatingAdmissionPolicy, policyObject oscal.PolicyObject, namespace string) error {
+	ruleIds := ruleIdsOf(policyObject)
+	if len(policy.Spec.Validations) == 0 {
+		return fmt.Errorf("%s %s of rule %s has no validations", policyKind, policy.Name, ruleIds)
+	}
+	paramsName := fmt.Sprintf("%s-params", policy.Name)
+	var paramRef *admissionregistrationv1.ParamRef
+	if len(policyObject.Parameters) > 0 {
+		paramKind := policy.Spec.ParamKind
+		if paramKind != nil && (paramKind.APIVersion != "v1" || paramKind.Kind != "ConfigMap") {
+			return fmt.Errorf("paramKind of %s %s must be v1 ConfigMap to bind the parameters of rule %s", policyKind, policy.Name, ruleIds)
+		}
+		policy.Spec.ParamKind = &admissionregistrationv1.ParamKind{APIVersion: "v1", Kind: "ConfigMap"}
+		parameterNotFoundAction := admissionregistrationv1.DenyAction
+		paramRef = &admissionregistrationv1.ParamRef{
+			Name:                    paramsName,
+			Namespace:               namespace,
+			ParameterNotFoundAction: &parameterNotFoundAction,
+		}
+		configMap := corev1.ConfigMap{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "ConfigMap",
+				APIVersion: "v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      paramsName,
+				Namespace: namespace,
+			},
+			Data: toConfigMapData(policyObject.Parameters),
+		}
+		if err := writeManifest(fmt.Sprintf("%s/%s.yaml", destDir, paramsName), &configMap); err != nil {
+			return err
+		}
+	} else if policy.Spec.ParamKind != nil {
+		return fmt.Errorf("%s %s has paramKind but rule %s has no parameter", policyKind, policy.Name, ruleIds)
+	}
+
+	auditAnnotation := admissionregistrationv1.AuditAnnotation{
+		Key:             RuleAuditAnnotationKey,
+		ValueExpression: strconv.Quote(ruleIds),
+	}
+	auditAnnotations := []admissionregistrationv1.AuditAnnotation{}
+	for _, annotation := range policy.Spec.AuditAnnotations {
+		if annotation.Key != RuleAuditAnnotationKey {
+			auditAnnotations = append(auditAnnotations, annotation)
+		}
+	}
+	policy.Spec.AuditAnnotations = append(auditAnnotations, auditAnnotation)
+	if err := writeManifest(fmt.Sprintf("%s/%s.yaml", destDir, policy.Name), &policy); err != nil {
+		return err
+	}
+
+	scope := scopeOfPolicy(policyObject)
+	binding := admissionregistrationv1.ValidatingAdmissionPolicyBinding{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ValidatingAdmissionPolicyBinding",
+			APIVersion: admissionregistrationv1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: fmt.Sprintf("%s-binding", policy.Name),
+		},
+		Spec: admissionregistrationv1.ValidatingAdmissionPolicyBindingSpec{
+			PolicyName:        policy.Name,
+			ParamRef:          paramRef,
+			ValidationActions: validationActions[scope.EnforcementMode],
+		},
+	}
+	if selector := namespaceSelector(scope); selector != nil {
+		binding.Spec.MatchResources = &admissionregistrationv1.MatchResources{NamespaceSelector: selector}
+	}
+	return writeManifest(fmt.Sprintf("%s/%s.yaml", destDir, binding.Name), &binding)
+}
+
+func (c *Oscal2Policy) CopyAllTo(destDir string) error {
+	if _, err := pkg.MakeDir(destDir); err != nil {
+		return err
+	}
+	if err := cp.Copy(c.tempDir.GetTempDir(), destDir); err != nil {
+		return err
+	}
+	return nil
+}
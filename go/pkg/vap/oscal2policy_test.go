@@ -0,0 +1,148 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vap
+
+import (
+	"os"
+	"testing"
+
+	"github.com/oscal-compass/compliance-to-policy/go/pkg"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal"
+	typec2pcr "github.com/oscal-compass/compliance-to-policy/go/pkg/types/c2pcr"
+	cd "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/componentdefinition"
+	"github.com/stretchr/testify/assert"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func parseTestC2PCR(t *testing.T, namespace string) typec2pcr.C2PCRParsed {
+	tempDirPath := pkg.PathFromPkgDirectory("./testdata/_test")
+	err := os.MkdirAll(tempDirPath, os.ModePerm)
+	assert.NoError(t, err, "Should not happen")
+
+	c2pcrSpec := typec2pcr.Spec{
+		Compliance: typec2pcr.Compliance{
+			Name: "Test Compliance",
+			ComponentDefinition: typec2pcr.ResourceRef{
+				Url: pkg.PathFromPkgDirectory("./testdata/vap/component-definition.json"),
+			},
+		},
+		PolicyResources: typec2pcr.ResourceRef{
+			Url: pkg.PathFromPkgDirectory("./testdata/vap/policy-resources"),
+		},
+		Target: typec2pcr.Target{
+			Namespace: namespace,
+		},
+	}
+	c2pcrParser := NewParser(pkg.NewGitUtils(pkg.NewTempDirectory(tempDirPath)))
+	c2pcrParsed, err := c2pcrParser.Parse(c2pcrSpec)
+	assert.NoError(t, err, "Should not happen")
+	return c2pcrParsed
+}
+
+func TestOscal2Policy(t *testing.T) {
+	c2pcrParsed := parseTestC2PCR(t, "c2p")
+	tempDir := pkg.NewTempDirectory(pkg.PathFromPkgDirectory("./testdata/_test"))
+	o2p := NewOscal2Policy(c2pcrParsed.PolicyResoureDir, tempDir)
+	err := o2p.Generate(c2pcrParsed)
+	assert.NoError(t, err, "Should not happen")
+	generatedDir := tempDir.GetTempDir()
+
+	// The parameter is bound by the ConfigMap and the binding selects the target namespaces
+	var policy admissionregistrationv1.ValidatingAdmissionPolicy
+	err = pkg.LoadYamlFileToObject(generatedDir+"/require-minimum-replicas/require-minimum-replicas.yaml", &policy)
+	assert.NoError(t, err, "Should not happen")
+	assert.Equal(t, &admissionregistrationv1.ParamKind{APIVersion: "v1", Kind: "ConfigMap"}, policy.Spec.ParamKind)
+	assert.Equal(t, "object.spec.replicas >= int(params.data.minimum_replicas)", policy.Spec.Validations[0].Expression)
+	assert.Equal(t, []admissionregistrationv1.AuditAnnotation{{Key: "c2p-rule", ValueExpression: `"require-minimum-replicas"`}}, policy.Spec.AuditAnnotations)
+
+	var configMap corev1.ConfigMap
+	err = pkg.LoadYamlFileToObject(generatedDir+"/require-minimum-replicas/require-minimum-replicas-params.yaml", &configMap)
+	assert.NoError(t, err, "Should not happen")
+	assert.Equal(t, "c2p", configMap.Namespace)
+	assert.Equal(t, map[string]string{"minimum_replicas": "3"}, configMap.Data)
+
+	var binding admissionregistrationv1.ValidatingAdmissionPolicyBinding
+	err = pkg.LoadYamlFileToObject(generatedDir+"/require-minimum-replicas/require-minimum-replicas-binding.yaml", &binding)
+	assert.NoError(t, err, "Should not happen")
+	assert.Equal(t, "require-minimum-replicas", binding.Spec.PolicyName)
+	assert.Equal(t, "require-minimum-replicas-params", binding.Spec.ParamRef.Name)
+	assert.Equal(t, "c2p", binding.Spec.ParamRef.Namespace)
+	assert.Equal(t, []admissionregistrationv1.ValidationAction{admissionregistrationv1.Warn, admissionregistrationv1.Audit}, binding.Spec.ValidationActions)
+	assert.Equal(t, &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+		{Key: NamespaceNameLabel, Operator: metav1.LabelSelectorOpIn, Values: []string{"app", "web"}},
+	}}, binding.Spec.MatchResources.NamespaceSelector)
+
+	// The policy without parameters is enforced except for the excluded namespaces
+	policy = admissionregistrationv1.ValidatingAdmissionPolicy{}
+	err = pkg.LoadYamlFileToObject(generatedDir+"/disallow-host-network/disallow-host-network.yaml", &policy)
+	assert.NoError(t, err, "Should not happen")
+	assert.Nil(t, policy.Spec.ParamKind)
+	_, err = os.Stat(generatedDir + "/disallow-host-network/disallow-host-network-params.yaml")
+	assert.True(t, os.IsNotExist(err))
+
+	binding = admissionregistrationv1.ValidatingAdmissionPolicyBinding{}
+	err = pkg.LoadYamlFileToObject(generatedDir+"/disallow-host-network/disallow-host-network-binding.yaml", &binding)
+	assert.NoError(t, err, "Should not happen")
+	assert.Nil(t, binding.Spec.ParamRef)
+	assert.Equal(t, []admissionregistrationv1.ValidationAction{admissionregistrationv1.Deny, admissionregistrationv1.Audit}, binding.Spec.ValidationActions)
+	assert.Equal(t, &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+		{Key: NamespaceNameLabel, Operator: metav1.LabelSelectorOpNotIn, Values: []string{"kube-system"}},
+	}}, binding.Spec.MatchResources.NamespaceSelector)
+}
+
+// addMaximumReplicasRule adds a rule with another parameter referring to the policy require-minimum-replicas
+func addMaximumReplicasRule(c2pcrParsed *typec2pcr.C2PCRParsed) {
+	componentObject := &c2pcrParsed.ComponentObjects[0]
+	componentObject.RuleObjects = append(componentObject.RuleObjects, oscal.RuleObject{
+		RuleId:      "require-maximum-replicas",
+		PolicyId:    "require-minimum-replicas",
+		ParameterId: "maximum_replicas",
+	})
+	cio := &componentObject.ControlImpleObjects[0]
+	cio.SetParameters = append(cio.SetParameters, cd.SetParameter{ParamID: "maximum_replicas", Values: []string{"10"}})
+	cio.ControlObjects[0].RuleIds = append(cio.ControlObjects[0].RuleIds, "require-maximum-replicas")
+}
+
+func TestOscal2PolicyWithParametersOfRulesOfSamePolicy(t *testing.T) {
+	c2pcrParsed := parseTestC2PCR(t, "c2p")
+	addMaximumReplicasRule(&c2pcrParsed)
+	tempDir := pkg.NewTempDirectory(pkg.PathFromPkgDirectory("./testdata/_test"))
+	err := NewOscal2Policy(c2pcrParsed.PolicyResoureDir, tempDir).Generate(c2pcrParsed)
+	assert.NoError(t, err, "Should not happen")
+	generatedDir := tempDir.GetTempDir()
+
+	// The policy is annotated with both rules and the ConfigMap has the parameters of both rules
+	var policy admissionregistrationv1.ValidatingAdmissionPolicy
+	err = pkg.LoadYamlFileToObject(generatedDir+"/require-minimum-replicas/require-minimum-replicas.yaml", &policy)
+	assert.NoError(t, err, "Should not happen")
+	assert.Equal(t, []admissionregistrationv1.AuditAnnotation{{Key: "c2p-rule", ValueExpression: `"require-minimum-replicas,require-maximum-replicas"`}}, policy.Spec.AuditAnnotations)
+
+	var configMap corev1.ConfigMap
+	err = pkg.LoadYamlFileToObject(generatedDir+"/require-minimum-replicas/require-minimum-replicas-params.yaml", &configMap)
+	assert.NoError(t, err, "Should not happen")
+	assert.Equal(t, map[string]string{"minimum_replicas": "3", "maximum_replicas": "10"}, configMap.Data)
+}
+
+func TestOscal2PolicyWithUnsetParameter(t *testing.T) {
+	c2pcrParsed := parseTestC2PCR(t, "")
+	c2pcrParsed.ComponentObjects[0].ControlImpleObjects[0].SetParameters = nil
+	tempDir := pkg.NewTempDirectory(pkg.PathFromPkgDirectory("./testdata/_test"))
+	err := NewOscal2Policy(c2pcrParsed.PolicyResoureDir, tempDir).Generate(c2pcrParsed)
+	assert.ErrorContains(t, err, "parameter minimum_replicas is not set for rule require-minimum-replicas")
+}
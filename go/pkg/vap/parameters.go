@@ -0,0 +1,31 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vap
+
+import (
+	"strings"
+)
+
+// toConfigMapData returns the data of the ConfigMap of the parameters. Multiple values are joined by comma
+// since CEL expressions cannot decode JSON (e.g. params.data.allowed_registries.split(',')).
+func toConfigMapData(parameters map[string][]string) map[string]string {
+	data := map[string]string{}
+	for paramId, values := range parameters {
+		data[paramId] = strings.Join(values, ",")
+	}
+	return data
+}
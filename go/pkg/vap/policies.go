@@ -0,0 +1,75 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vap
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/oscal-compass/compliance-to-policy/go/pkg"
+	"go.uber.org/zap"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const policyKind = "ValidatingAdmissionPolicy"
+
+// loadPolicies returns the ValidatingAdmissionPolicies in the yaml files of the directory.
+// The other objects (e.g. bindings, which are generated by oscal2policy) are ignored.
+func loadPolicies(dir string, logger *zap.Logger) ([]admissionregistrationv1.ValidatingAdmissionPolicy, error) {
+	policies := []admissionregistrationv1.ValidatingAdmissionPolicy{}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !(strings.HasSuffix(info.Name(), ".yaml") || strings.HasSuffix(info.Name(), ".yml")) {
+			return nil
+		}
+		unstObjs, err := pkg.LoadYaml(path)
+		if err != nil {
+			return fmt.Errorf("failed to load %s: %w", path, err)
+		}
+		for _, unstObj := range unstObjs {
+			if unstObj.GetKind() != policyKind || !strings.HasPrefix(unstObj.GetAPIVersion(), admissionregistrationv1.GroupName+"/") {
+				logger.Warn(fmt.Sprintf("%s %s in %s is ignored: only %s is loaded", unstObj.GetKind(), unstObj.GetName(), path, policyKind))
+				continue
+			}
+			// v1beta1 and v1alpha1 policies are read as v1 policies since the fields are the same
+			var policy admissionregistrationv1.ValidatingAdmissionPolicy
+			if err := pkg.ToK8sTypedObject(unstObj, &policy); err != nil {
+				return fmt.Errorf("failed to load %s %s in %s: %w", policyKind, unstObj.GetName(), path, err)
+			}
+			policy.APIVersion = admissionregistrationv1.SchemeGroupVersion.String()
+			policies = append(policies, policy)
+		}
+		return nil
+	})
+	return policies, err
+}
+
+// writeManifest writes the Kubernetes object to the yaml file without the fields set by the API server
+func writeManifest(path string, obj interface{}) error {
+	unstObj, err := pkg.ToK8sUnstructedObject(obj)
+	if err != nil {
+		return err
+	}
+	unstructured.RemoveNestedField(unstObj.Object, "metadata", "creationTimestamp")
+	unstructured.RemoveNestedField(unstObj.Object, "status")
+	return pkg.WriteObjToYamlFile(path, unstObj.Object)
+}
@@ -0,0 +1,360 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vap
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/oscal-compass/compliance-to-policy/go/pkg"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal"
+	typec2pcr "github.com/oscal-compass/compliance-to-policy/go/pkg/types/c2pcr"
+	typeap "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/assessmentplan"
+	typear "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/assessmentresults"
+	typeoscalcommon "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/common"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/util/sets"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+)
+
+type ResultToOscal struct {
+	logger             *zap.Logger
+	c2pParsed          typec2pcr.C2PCRParsed
+	auditLogPath       string
+	aggregationRule    oscal.AggregationRule
+	stamper            oscal.Stamper
+	assessmentPlanHref string
+	assessmentPlan     *typeap.AssessmentPlan
+}
+
+type PolicyContainer struct {
+	// Name of the ValidatingAdmissionPolicy
+	PolicyName string
+	// Rule validated by the policy
+	RuleId string
+	// Check of the rule implemented by the policy. Empty if the rule has no checks.
+	CheckId string
+}
+
+// PolicyResult is the result of the latest request to a resource evaluated by a policy
+type PolicyResult struct {
+	ObjectRef auditv1.ObjectReference
+	// pass or fail
+	Result string
+	// Messages of the failed validations
+	Message string
+	// Bindings of the failed validations
+	Binding   string
+	Timestamp time.Time
+}
+
+func NewResultToOscal(c2pParsed typec2pcr.C2PCRParsed, auditLogPath string) *ResultToOscal {
+	r := ResultToOscal{
+		logger:          pkg.GetLogger("vap/result2oscal"),
+		c2pParsed:       c2pParsed,
+		auditLogPath:    auditLogPath,
+		aggregationRule: oscal.AggregationRuleAllPass,
+		stamper:         oscal.NewStamper(false),
+	}
+	return &r
+}
+
+// SetAggregationRule sets the rule aggregating the results of the observations into the status of the findings of the controls.
+func (r *ResultToOscal) SetAggregationRule(aggregationRule oscal.AggregationRule) {
+	r.aggregationRule = aggregationRule
+}
+
+// SetDeterministic makes the same inputs produce the same assessment results.
+func (r *ResultToOscal) SetDeterministic(deterministic bool) {
+	r.stamper = oscal.NewStamper(deterministic)
+}
+
+// SetAssessmentPlan makes the assessment results import the assessment plan by the href.
+func (r *ResultToOscal) SetAssessmentPlan(href string, ap typeap.AssessmentPlan) {
+	r.assessmentPlanHref = href
+	r.assessmentPlan = &ap
+}
+
+func (r *ResultToOscal) aggregateComponentObjects() (policyContainers []PolicyContainer, controlObjects []oscal.ControlObject) {
+	for _, componentObject := range r.c2pParsed.ComponentObjects {
+		// Validation components only map checks to the rules of the target components
+		if componentObject.ComponentType == "validation" {
+			continue
+		}
+		for _, ruleObject := range componentObject.RuleObjects {
+			for _, policyName := range ruleObject.PolicyIds(true) {
+				sourceDir := fmt.Sprintf("%s/%s", r.c2pParsed.PolicyResoureDir, policyName)
+				policies, err := loadPolicies(sourceDir, r.logger)
+				if err != nil {
+					r.logger.Error(fmt.Sprintf("Failed to load %s: %v", sourceDir, err))
+					continue
+				}
+				checkId := ""
+				if slices.Contains(ruleObject.CheckIds(), policyName) {
+					checkId = policyName
+				}
+				for _, policy := range policies {
+					policyContainers = append(policyContainers, PolicyContainer{
+						PolicyName: policy.Name,
+						RuleId:     ruleObject.RuleId,
+						CheckId:    checkId,
+					})
+				}
+			}
+		}
+		for _, cio := range componentObject.ControlImpleObjects {
+			controlObjects = append(controlObjects, cio.ControlObjects...)
+		}
+	}
+	return
+}
+
+func (r *ResultToOscal) findControls(ruleId string) []oscal.ControlObject {
+	controls := []oscal.ControlObject{}
+	for _, componentObject := range r.c2pParsed.ComponentObjects {
+		if componentObject.ComponentType == "validation" {
+			continue
+		}
+		for _, cio := range componentObject.ControlImpleObjects {
+			for _, co := range cio.ControlObjects {
+				for _, _ruleId := range co.RuleIds {
+					if ruleId == _ruleId {
+						controls = append(controls, co)
+					}
+				}
+			}
+		}
+	}
+	return controls
+}
+
+// retrievePolicyResults returns the results of the policies by the audit events of the requests evaluated by the policies.
+// A request is evaluated by a policy if the event has the audit annotation <policy name>/c2p-rule added by oscal2policy
+// or a validation failure of the policy. Only the latest request to each resource is taken for each policy.
+func retrievePolicyResults(events []auditv1.Event) (map[string][]*PolicyResult, error) {
+	latest := map[string]map[string]*PolicyResult{}
+	for _, event := range events {
+		if event.ObjectRef == nil || len(event.Annotations) == 0 {
+			continue
+		}
+		failures, err := validationFailures(event)
+		if err != nil {
+			return nil, err
+		}
+		eventResults := map[string]*PolicyResult{}
+		resultOf := func(policyName string) *PolicyResult {
+			result, ok := eventResults[policyName]
+			if !ok {
+				result = &PolicyResult{
+					ObjectRef: *event.ObjectRef,
+					Result:    "pass",
+					Timestamp: event.StageTimestamp.UTC(),
+				}
+				eventResults[policyName] = result
+			}
+			return result
+		}
+		for key := range event.Annotations {
+			if policyName, found := strings.CutSuffix(key, "/"+RuleAuditAnnotationKey); found {
+				resultOf(policyName)
+			}
+		}
+		for _, failure := range failures {
+			result := resultOf(failure.Policy)
+			result.Result = "fail"
+			result.Message = joinUnique(result.Message, failure.Message)
+			result.Binding = joinUnique(result.Binding, failure.Binding)
+		}
+		for policyName, result := range eventResults {
+			if latest[policyName] == nil {
+				latest[policyName] = map[string]*PolicyResult{}
+			}
+			key := resourceKey(result.ObjectRef)
+			if current, ok := latest[policyName][key]; !ok || !result.Timestamp.Before(current.Timestamp) {
+				latest[policyName][key] = result
+			}
+		}
+	}
+	policyResults := map[string][]*PolicyResult{}
+	for policyName, results := range latest {
+		keys := []string{}
+		for key := range results {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			policyResults[policyName] = append(policyResults[policyName], results[key])
+		}
+	}
+	return policyResults, nil
+}
+
+func joinUnique(joined string, value string) string {
+	if joined == "" {
+		return value
+	}
+	for _, element := range strings.Split(joined, "; ") {
+		if element == value {
+			return joined
+		}
+	}
+	return joined + "; " + value
+}
+
+func resourceKey(objectRef auditv1.ObjectReference) string {
+	return strings.Join([]string{objectRef.APIGroup, objectRef.APIVersion, objectRef.Resource, objectRef.Subresource, objectRef.Namespace, objectRef.Name}, "/")
+}
+
+func apiVersionOf(objectRef auditv1.ObjectReference) string {
+	if objectRef.APIGroup == "" {
+		return objectRef.APIVersion
+	}
+	return fmt.Sprintf("%s/%s", objectRef.APIGroup, objectRef.APIVersion)
+}
+
+// makeResultProps makes the props of a subject from the result of the policy.
+func makeResultProps(result *PolicyResult) []typeoscalcommon.Prop {
+	props := []typeoscalcommon.Prop{}
+	props = append(props, makeProp("result", result.Result))
+	if reason := oscal.ToPropValue(result.Message); reason != "" {
+		props = append(props, makeProp("reason", reason))
+	}
+	if result.Binding != "" {
+		props = append(props, makeProp("binding", oscal.ToPropValue(result.Binding)))
+	}
+	return props
+}
+
+func makeProp(name string, value string) typeoscalcommon.Prop {
+	return typeoscalcommon.Prop{
+		Name:  name,
+		Value: value,
+	}
+}
+
+func (r *ResultToOscal) GenerateAssessmentResults() (*typear.AssessmentResultsRoot, error) {
+	events, err := LoadAuditEvents(r.auditLogPath)
+	if err != nil {
+		return nil, err
+	}
+	policyResults, err := retrievePolicyResults(events)
+	if err != nil {
+		return nil, err
+	}
+
+	observations := []typear.Observation{}
+	policyContainers, controlObjects := r.aggregateComponentObjects()
+	inputTimestamps := []time.Time{}
+
+	for _, policyContainer := range policyContainers {
+		props := []typeoscalcommon.Prop{}
+		props = append(props, makeProp("assessment-rule-id", policyContainer.RuleId))
+		description := fmt.Sprintf("Observation of rule %s", policyContainer.RuleId)
+		if policyContainer.CheckId != "" {
+			props = append(props, makeProp("check-id", policyContainer.CheckId))
+			description = fmt.Sprintf("Observation of check %s of rule %s", policyContainer.CheckId, policyContainer.RuleId)
+		}
+		props = append(props, makeProp("policy-id", policyContainer.PolicyName))
+		controls := r.findControls(policyContainer.RuleId)
+		controlIds := sets.NewString()
+		for _, control := range controls {
+			controlIds = controlIds.Insert(control.GetControlId())
+		}
+		props = append(props, makeProp("controls", strings.Join(controlIds.List(), ",")))
+		observation := typear.Observation{
+			UUID:        r.stamper.UUID("observation", policyContainer.RuleId, policyContainer.CheckId, policyContainer.PolicyName),
+			Description: description,
+			Methods:     []string{"TEST-AUTOMATED"},
+			Props:       props,
+			Subjects:    []typear.Subject{},
+		}
+		for _, result := range policyResults[policyContainer.PolicyName] {
+			if !result.Timestamp.IsZero() {
+				inputTimestamps = append(inputTimestamps, result.Timestamp)
+				if result.Timestamp.After(observation.Collected) {
+					observation.Collected = result.Timestamp
+				}
+			}
+			objectRef := result.ObjectRef
+			apiVersion := apiVersionOf(objectRef)
+			uid := string(objectRef.UID)
+			if uid == "" {
+				uid = r.stamper.UUID("resource", apiVersion, objectRef.Resource, objectRef.Namespace, objectRef.Name)
+			}
+			subject := typear.Subject{
+				SubjectUUID: uid,
+				Title:       fmt.Sprintf("ApiVersion: %s, Resource: %s, Namespace: %s, Name: %s", apiVersion, objectRef.Resource, objectRef.Namespace, objectRef.Name),
+				Type:        "resource",
+				Props:       makeResultProps(result),
+			}
+			observation.Subjects = append(observation.Subjects, subject)
+		}
+		observations = append(observations, observation)
+	}
+
+	timestamp, err := r.stamper.Timestamp(inputTimestamps...)
+	if err != nil {
+		return nil, err
+	}
+	// Observations without results are collected at the time of the assessment results
+	for i := range observations {
+		if observations[i].Collected.IsZero() {
+			observations[i].Collected = timestamp
+		}
+	}
+	metadata := typear.Metadata{
+		Title:        "OSCAL Assessment Results",
+		LastModified: timestamp,
+		Version:      "0.0.1",
+		OscalVersion: oscal.OscalVersion,
+	}
+	ar := typear.AssessmentResults{
+		UUID:     r.stamper.UUID("assessment-results", "vap", timestamp.Format(time.RFC3339Nano)),
+		Metadata: metadata,
+		Results:  []typear.Result{},
+	}
+
+	controlSelection := typear.ControlSelection{
+		IncludeControls: oscal.SelectControls(controlObjects),
+	}
+	result := typear.Result{
+		UUID:        r.stamper.UUID("result", "vap", timestamp.Format(time.RFC3339Nano)),
+		Title:       "Assessment Results by ValidatingAdmissionPolicy",
+		Description: "Assessment Results by ValidatingAdmissionPolicy...",
+		Start:       timestamp,
+		ReviewedControls: typear.ReviewedControl{
+			ControlSelections: []typear.ControlSelection{controlSelection},
+		},
+		Observations: observations,
+		Findings:     oscal.GenerateFindings(r.c2pParsed.ComponentObjects, observations, r.aggregationRule, r.stamper),
+	}
+
+	ar.Results = append(ar.Results, result)
+	if r.assessmentPlan != nil {
+		oscal.ImportAssessmentPlan(&ar, r.assessmentPlanHref, *r.assessmentPlan)
+	} else {
+		oscal.ImportComponentDefinition(&ar, r.c2pParsed.ComponentDefinition, r.stamper)
+	}
+	if r.stamper.IsDeterministic() {
+		oscal.SortAssessmentResults(&ar)
+	}
+	arRoot := typear.AssessmentResultsRoot{AssessmentResults: ar}
+	return &arRoot, nil
+}
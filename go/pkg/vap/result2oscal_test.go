@@ -0,0 +1,130 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vap
+
+import (
+	"testing"
+
+	"github.com/oscal-compass/compliance-to-policy/go/pkg"
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal/format"
+	typear "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/assessmentresults"
+	typeoscalcommon "github.com/oscal-compass/compliance-to-policy/go/pkg/types/oscal/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func findProp(props []typeoscalcommon.Prop, name string) string {
+	for _, prop := range props {
+		if prop.Name == name {
+			return prop.Value
+		}
+	}
+	return ""
+}
+
+func TestResultToOscal(t *testing.T) {
+	c2pcrParsed := parseTestC2PCR(t, "c2p")
+	r := NewResultToOscal(c2pcrParsed, pkg.PathFromPkgDirectory("./testdata/vap/audit-events"))
+	r.SetDeterministic(true)
+	arRoot, err := r.GenerateAssessmentResults()
+	assert.NoError(t, err, "Should not happen")
+
+	err = pkg.WriteOscalObjToFile(pkg.PathFromPkgDirectory("./testdata/_test/vap-assessment-results.json"), arRoot, format.JSON)
+	assert.NoError(t, err, "Should not happen")
+
+	result := arRoot.AssessmentResults.Results[0]
+	observations := map[string]typear.Observation{}
+	for _, observation := range result.Observations {
+		observations[findProp(observation.Props, "policy-id")] = observation
+	}
+
+	// The failed deployment passes by the later update and the requests not evaluated by the policies are ignored
+	replicas := observations["require-minimum-replicas"]
+	assert.Equal(t, "require-minimum-replicas", findProp(replicas.Props, "assessment-rule-id"))
+	assert.Equal(t, "cp-10", findProp(replicas.Props, "controls"))
+	assert.Equal(t, "2024-05-01T10:05:00Z", replicas.Collected.Format("2006-01-02T15:04:05Z07:00"))
+	assert.Len(t, replicas.Subjects, 2)
+	for _, subject := range replicas.Subjects {
+		assert.Equal(t, "pass", findProp(subject.Props, "result"))
+	}
+	assert.Equal(t, "ApiVersion: apps/v1, Resource: deployments, Namespace: app, Name: web-frontend", replicas.Subjects[1].Title)
+	assert.Equal(t, "5d8a3b8e-1111-4c3a-9a55-7a6c7a9e0001", replicas.Subjects[1].SubjectUUID)
+
+	hostNetwork := observations["disallow-host-network"]
+	assert.Len(t, hostNetwork.Subjects, 2)
+	assert.Equal(t, "ApiVersion: v1, Resource: pods, Namespace: web, Name: debug", hostNetwork.Subjects[0].Title)
+	assert.Equal(t, "fail", findProp(hostNetwork.Subjects[0].Props, "result"))
+	assert.Equal(t, "Pods must not use the host network", findProp(hostNetwork.Subjects[0].Props, "reason"))
+	assert.Equal(t, "disallow-host-network-binding", findProp(hostNetwork.Subjects[0].Props, "binding"))
+	assert.Equal(t, "pass", findProp(hostNetwork.Subjects[1].Props, "result"))
+
+	statuses := map[string]string{}
+	for _, finding := range result.Findings {
+		statuses[finding.Target.TargetId] = finding.Target.Status.State
+	}
+	assert.Equal(t, map[string]string{"cp-10_smt": "satisfied", "sc-7_smt": "not-satisfied"}, statuses)
+}
+
+func TestResultToOscalWithRulesOfSamePolicy(t *testing.T) {
+	c2pcrParsed := parseTestC2PCR(t, "c2p")
+	addMaximumReplicasRule(&c2pcrParsed)
+	r := NewResultToOscal(c2pcrParsed, pkg.PathFromPkgDirectory("./testdata/vap/audit-events"))
+	r.SetDeterministic(true)
+	arRoot, err := r.GenerateAssessmentResults()
+	assert.NoError(t, err, "Should not happen")
+
+	// The results of the policy are attributed to every rule referring to the policy
+	observations := map[string]typear.Observation{}
+	for _, observation := range arRoot.AssessmentResults.Results[0].Observations {
+		if findProp(observation.Props, "policy-id") == "require-minimum-replicas" {
+			observations[findProp(observation.Props, "assessment-rule-id")] = observation
+		}
+	}
+	assert.Len(t, observations, 2)
+	for _, ruleId := range []string{"require-minimum-replicas", "require-maximum-replicas"} {
+		observation := observations[ruleId]
+		assert.Equal(t, "cp-10", findProp(observation.Props, "controls"))
+		assert.Len(t, observation.Subjects, 2)
+	}
+	assert.NotEqual(t, observations["require-minimum-replicas"].UUID, observations["require-maximum-replicas"].UUID)
+}
+
+func TestRetrievePolicyResultsFromEventList(t *testing.T) {
+	events, err := decodeAuditEvents([]byte(`{
+  "kind": "EventList",
+  "apiVersion": "audit.k8s.io/v1",
+  "items": [
+    {
+      "auditID": "1d2c3b4a-0000-4000-8000-000000000001",
+      "stage": "ResponseComplete",
+      "verb": "create",
+      "objectRef": {"resource": "pods", "namespace": "web", "name": "debug", "apiVersion": "v1"},
+      "stageTimestamp": "2024-05-01T10:00:00.000000Z",
+      "annotations": {
+        "validation.policy.admission.k8s.io/validation_failure": "[{\"message\":\"first\",\"policy\":\"disallow-host-network\",\"binding\":\"b\",\"expressionIndex\":0,\"validationActions\":[\"Audit\"]},{\"message\":\"second\",\"policy\":\"disallow-host-network\",\"binding\":\"b\",\"expressionIndex\":1,\"validationActions\":[\"Audit\"]}]"
+      }
+    }
+  ]
+}`))
+	assert.NoError(t, err, "Should not happen")
+	policyResults, err := retrievePolicyResults(events)
+	assert.NoError(t, err, "Should not happen")
+	assert.Len(t, policyResults["disallow-host-network"], 1)
+	result := policyResults["disallow-host-network"][0]
+	assert.Equal(t, "fail", result.Result)
+	assert.Equal(t, "first; second", result.Message)
+	assert.Equal(t, "b", result.Binding)
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2023 IBM Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vap
+
+import (
+	"github.com/oscal-compass/compliance-to-policy/go/pkg/oscal"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Label of the namespaces set by Kubernetes to their names, by which the bindings select the namespaces
+const NamespaceNameLabel = "kubernetes.io/metadata.name"
+
+// Validation actions of the bindings by the enforcement modes given by Enforcement_Mode.
+// Audit is always included so that the validation failures are recorded in the audit events.
+var validationActions = map[string][]admissionregistrationv1.ValidationAction{
+	"audit":   {admissionregistrationv1.Warn, admissionregistrationv1.Audit},
+	"enforce": {admissionregistrationv1.Deny, admissionregistrationv1.Audit},
+}
+
+// Enforcement mode of the rules without Enforcement_Mode
+const DefaultEnforcementMode = "audit"
+
+// scopeOfPolicy returns the scope of the policy merged from the scopes of the rules with the default enforcement mode
+func scopeOfPolicy(policyObject oscal.PolicyObject) oscal.PolicyScope {
+	scope := policyObject.Scope
+	if scope.EnforcementMode == "" {
+		scope.EnforcementMode = DefaultEnforcementMode
+	}
+	return scope
+}
+
+// namespaceSelector returns the selector of the target namespaces except the excluded namespaces, or nil if the scope selects all namespaces.
+func namespaceSelector(scope oscal.PolicyScope) *metav1.LabelSelector {
+	expressions := []metav1.LabelSelectorRequirement{}
+	if len(scope.TargetNamespaces) > 0 {
+		expressions = append(expressions, metav1.LabelSelectorRequirement{
+			Key:      NamespaceNameLabel,
+			Operator: metav1.LabelSelectorOpIn,
+			Values:   scope.TargetNamespaces,
+		})
+	}
+	if len(scope.ExcludedNamespaces) > 0 {
+		expressions = append(expressions, metav1.LabelSelectorRequirement{
+			Key:      NamespaceNameLabel,
+			Operator: metav1.LabelSelectorOpNotIn,
+			Values:   scope.ExcludedNamespaces,
+		})
+	}
+	if len(expressions) == 0 {
+		return nil
+	}
+	return &metav1.LabelSelector{MatchExpressions: expressions}
+}